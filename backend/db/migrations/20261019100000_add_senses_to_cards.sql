@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS senses TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+
+ALTER TABLE cards DROP COLUMN IF EXISTS senses;
@@ -0,0 +1,25 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+-- Move the swipe records of duplicated cards to the oldest card sharing the same front
+UPDATE swipe_records sr
+SET card_id = d.keep_id
+FROM (SELECT id,
+             MIN(id) OVER (PARTITION BY cardgroup_id, front) AS keep_id
+      FROM cards) d
+WHERE sr.card_id = d.id
+  AND d.id <> d.keep_id;
+
+-- Remove the duplicated cards
+DELETE
+FROM cards c
+    USING cards k
+WHERE c.cardgroup_id = k.cardgroup_id
+  AND c.front = k.front
+  AND c.id > k.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_cards_cardgroup_id_front ON cards(cardgroup_id, front);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS uq_cards_cardgroup_id_front;
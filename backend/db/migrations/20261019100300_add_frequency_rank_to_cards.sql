@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS frequency_rank INTEGER;
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS new_card_order VARCHAR(20) NOT NULL DEFAULT 'RANDOM';
+
+-- +goose Down
+
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS new_card_order;
+ALTER TABLE cards DROP COLUMN IF EXISTS frequency_rank;
@@ -0,0 +1,88 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+CREATE TABLE IF NOT EXISTS permissions
+(
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT      NOT NULL UNIQUE,
+    description TEXT      NOT NULL DEFAULT '',
+    created     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id       BIGINT NOT NULL,
+    permission_id BIGINT NOT NULL,
+    created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_role_permissions_permission_id ON role_permissions(permission_id);
+
+INSERT INTO permissions (name, description)
+VALUES ('cardgroup:write', 'Create card groups and import decks'),
+       ('cardgroup:admin', 'Access every card group regardless of membership'),
+       ('deck:publish', 'Publish card groups to the public catalog'),
+       ('user:admin', 'Manage users'),
+       ('role:admin', 'Manage roles and their permissions')
+ON CONFLICT (name) DO NOTHING;
+
+-- Roles and user roles seeded by this migration, so that Down removes only those
+CREATE TABLE IF NOT EXISTS seeded_roles
+(
+    role_id BIGINT PRIMARY KEY,
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS seeded_user_roles
+(
+    user_id BIGINT NOT NULL,
+    role_id BIGINT NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id, role_id) REFERENCES user_roles (user_id, role_id) ON DELETE CASCADE
+);
+
+-- Default roles. New users get the role of FL_DEFAULT_ROLE, which is user by default.
+WITH inserted AS (
+    INSERT INTO roles (name)
+        VALUES ('admin'),
+               ('user')
+        ON CONFLICT (name) DO NOTHING
+        RETURNING id)
+INSERT INTO seeded_roles (role_id)
+SELECT id
+FROM inserted;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles
+         JOIN permissions
+              ON roles.name = 'admin'
+                  OR (roles.name = 'user' AND permissions.name IN ('cardgroup:write', 'deck:publish'))
+ON CONFLICT DO NOTHING;
+
+-- Existing users get the default role, as new users do
+WITH inserted AS (
+    INSERT INTO user_roles (user_id, role_id)
+        SELECT users.id, roles.id
+        FROM users
+                 JOIN roles ON roles.name = 'user'
+        ON CONFLICT DO NOTHING
+        RETURNING user_id, role_id)
+INSERT INTO seeded_user_roles (user_id, role_id)
+SELECT user_id, role_id
+FROM inserted;
+
+-- +goose Down
+
+DELETE FROM user_roles USING seeded_user_roles
+WHERE user_roles.user_id = seeded_user_roles.user_id
+  AND user_roles.role_id = seeded_user_roles.role_id;
+DELETE FROM roles WHERE id IN (SELECT role_id FROM seeded_roles);
+DROP TABLE IF EXISTS seeded_user_roles;
+DROP TABLE IF EXISTS seeded_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
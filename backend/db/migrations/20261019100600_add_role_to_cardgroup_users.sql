@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+-- Existing members could do anything with their card groups, so they become owners.
+ALTER TABLE cardgroup_users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'OWNER';
+ALTER TABLE cardgroup_users ALTER COLUMN role SET DEFAULT 'VIEWER';
+ALTER TABLE cardgroup_users ADD CONSTRAINT chk_cardgroup_users_role CHECK (role IN ('OWNER', 'EDITOR', 'VIEWER'));
+
+-- +goose Down
+
+ALTER TABLE cardgroup_users DROP CONSTRAINT IF EXISTS chk_cardgroup_users_role;
+ALTER TABLE cardgroup_users DROP COLUMN IF EXISTS role;
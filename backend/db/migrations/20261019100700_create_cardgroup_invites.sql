@@ -0,0 +1,27 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+-- Only the SHA-256 hash of the token is stored, the token itself is shown once to its creator.
+CREATE TABLE IF NOT EXISTS cardgroup_invites
+(
+    id           BIGSERIAL PRIMARY KEY,
+    cardgroup_id BIGINT      NOT NULL,
+    token_hash   VARCHAR(64) NOT NULL UNIQUE,
+    role         VARCHAR(20) NOT NULL DEFAULT 'VIEWER',
+    expires_at   TIMESTAMP   NOT NULL,
+    max_uses     INT         NOT NULL DEFAULT 1,
+    uses         INT         NOT NULL DEFAULT 0,
+    created_by   BIGINT,
+    revoked_at   TIMESTAMP,
+    created      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_cardgroup_invites_role CHECK (role IN ('OWNER', 'EDITOR', 'VIEWER')),
+    CONSTRAINT chk_cardgroup_invites_uses CHECK (max_uses >= 1 AND uses <= max_uses),
+    FOREIGN KEY (cardgroup_id) REFERENCES cardgroups (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX idx_cardgroup_invites_cardgroup_id ON cardgroup_invites(cardgroup_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS cardgroup_invites;
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS is_public BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS source_language VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS target_language VARCHAR(10) NOT NULL DEFAULT '';
+-- Subscriptions and forks remember the public card group and the cards they were copied from
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS source_cardgroup_id BIGINT REFERENCES cardgroups (id) ON DELETE SET NULL;
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS subscribed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE cards ADD COLUMN IF NOT EXISTS source_card_id BIGINT REFERENCES cards (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_cardgroups_is_public ON cardgroups(is_public) WHERE is_public;
+CREATE INDEX idx_cardgroups_source_cardgroup_id ON cardgroups(source_cardgroup_id);
+CREATE INDEX idx_cards_source_card_id ON cards(source_card_id);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_cards_source_card_id;
+DROP INDEX IF EXISTS idx_cardgroups_source_cardgroup_id;
+DROP INDEX IF EXISTS idx_cardgroups_is_public;
+ALTER TABLE cards DROP COLUMN IF EXISTS source_card_id;
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS subscribed;
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS source_cardgroup_id;
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS target_language;
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS source_language;
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS description;
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS is_public;
@@ -0,0 +1,56 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+CREATE TABLE IF NOT EXISTS cardgroup_versions
+(
+    id           BIGSERIAL PRIMARY KEY,
+    cardgroup_id BIGINT    NOT NULL,
+    version      INT       NOT NULL,
+    note         TEXT      NOT NULL DEFAULT '',
+    created      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (cardgroup_id, version),
+    FOREIGN KEY (cardgroup_id) REFERENCES cardgroups (id) ON DELETE CASCADE
+);
+
+-- The cards of a version as they were published. card_id has no foreign key because the card may
+-- be deleted after publishing while copies still refer to it by source_card_id.
+CREATE TABLE IF NOT EXISTS cardgroup_version_cards
+(
+    version_id     BIGINT NOT NULL,
+    card_id        BIGINT NOT NULL,
+    front          TEXT   NOT NULL,
+    back           TEXT   NOT NULL,
+    reading        TEXT   NOT NULL DEFAULT '',
+    senses         TEXT[],
+    tags           TEXT[],
+    frequency_rank INTEGER,
+    PRIMARY KEY (version_id, card_id),
+    FOREIGN KEY (version_id) REFERENCES cardgroup_versions (id) ON DELETE CASCADE
+);
+
+-- Version of the source card group a subscription or a fork is up to date with
+ALTER TABLE cardgroups ADD COLUMN IF NOT EXISTS source_version INT;
+
+-- Card groups published before versions get their current cards as version 1
+INSERT INTO cardgroup_versions (cardgroup_id, version)
+SELECT id, 1
+FROM cardgroups
+WHERE is_public;
+
+INSERT INTO cardgroup_version_cards (version_id, card_id, front, back, reading, senses, tags, frequency_rank)
+SELECT cardgroup_versions.id, cards.id, cards.front, cards.back, cards.reading, cards.senses, cards.tags, cards.frequency_rank
+FROM cardgroup_versions
+         JOIN cards ON cards.cardgroup_id = cardgroup_versions.cardgroup_id;
+
+-- Existing subscriptions and forks are up to date with version 1 of their source
+UPDATE cardgroups
+SET source_version = 1
+WHERE source_version IS NULL
+  AND source_cardgroup_id IN (SELECT cardgroup_id FROM cardgroup_versions);
+
+-- +goose Down
+
+ALTER TABLE cardgroups DROP COLUMN IF EXISTS source_version;
+DROP TABLE IF EXISTS cardgroup_version_cards;
+DROP TABLE IF EXISTS cardgroup_versions;
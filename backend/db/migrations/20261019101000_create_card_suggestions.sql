@@ -0,0 +1,29 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+-- Edits of a card suggested by a member of its card group, applied by an owner on approval
+CREATE TABLE IF NOT EXISTS card_suggestions
+(
+    id           BIGSERIAL PRIMARY KEY,
+    card_id      BIGINT      NOT NULL,
+    cardgroup_id BIGINT      NOT NULL,
+    suggested_by BIGINT,
+    front        TEXT        NOT NULL,
+    back         TEXT        NOT NULL,
+    comment      TEXT        NOT NULL DEFAULT '',
+    status       VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    reviewed_by  BIGINT,
+    reviewed_at  TIMESTAMP,
+    created      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated      TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_card_suggestions_status CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    FOREIGN KEY (card_id) REFERENCES cards (id) ON DELETE CASCADE,
+    FOREIGN KEY (cardgroup_id) REFERENCES cardgroups (id) ON DELETE CASCADE,
+    FOREIGN KEY (suggested_by) REFERENCES users (id) ON DELETE SET NULL,
+    FOREIGN KEY (reviewed_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX idx_card_suggestions_cardgroup_id_status ON card_suggestions(cardgroup_id, status);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS card_suggestions;
@@ -0,0 +1,78 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id      BIGSERIAL PRIMARY KEY,
+    name    TEXT      NOT NULL,
+    created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Teachers manage the classrooms of the organization, students are enrolled in them
+CREATE TABLE IF NOT EXISTS organization_users
+(
+    organization_id BIGINT      NOT NULL,
+    user_id         BIGINT      NOT NULL,
+    role            VARCHAR(20) NOT NULL DEFAULT 'STUDENT',
+    created         TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated         TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT chk_organization_users_role CHECK (role IN ('TEACHER', 'STUDENT')),
+    FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_organization_users_user_id ON organization_users(user_id);
+
+CREATE TABLE IF NOT EXISTS classrooms
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT    NOT NULL,
+    name            TEXT      NOT NULL,
+    created         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_classrooms_organization_id ON classrooms(organization_id);
+
+CREATE TABLE IF NOT EXISTS classroom_students
+(
+    classroom_id BIGINT    NOT NULL,
+    user_id      BIGINT    NOT NULL,
+    created      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (classroom_id, user_id),
+    FOREIGN KEY (classroom_id) REFERENCES classrooms (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Card groups assigned to a classroom, whose students are enrolled in cardgroup_users as viewers
+CREATE TABLE IF NOT EXISTS classroom_cardgroups
+(
+    classroom_id BIGINT    NOT NULL,
+    cardgroup_id BIGINT    NOT NULL,
+    created      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (classroom_id, cardgroup_id),
+    FOREIGN KEY (classroom_id) REFERENCES classrooms (id) ON DELETE CASCADE,
+    FOREIGN KEY (cardgroup_id) REFERENCES cardgroups (id) ON DELETE CASCADE
+);
+
+INSERT INTO permissions (name, description)
+VALUES ('organization:write', 'Create organizations'),
+       ('organization:admin', 'Access every organization regardless of membership')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles
+         JOIN permissions ON permissions.name IN ('organization:write', 'organization:admin')
+WHERE roles.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+
+DROP TABLE IF EXISTS classroom_cardgroups;
+DROP TABLE IF EXISTS classroom_students;
+DROP TABLE IF EXISTS classrooms;
+DROP TABLE IF EXISTS organization_users;
+DROP TABLE IF EXISTS organizations;
+DELETE FROM permissions WHERE name IN ('organization:write', 'organization:admin');
@@ -0,0 +1,28 @@
+-- +goose Up
+
+-- SQL in section 'Up' is executed when this migration is applied.
+-- Users join organizations only by accepting an invite. As for card group invites, only the SHA-256
+-- hash of the token is stored.
+CREATE TABLE IF NOT EXISTS organization_invites
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT      NOT NULL,
+    token_hash      VARCHAR(64) NOT NULL UNIQUE,
+    role            VARCHAR(20) NOT NULL DEFAULT 'STUDENT',
+    expires_at      TIMESTAMP   NOT NULL,
+    max_uses        INT         NOT NULL DEFAULT 1,
+    uses            INT         NOT NULL DEFAULT 0,
+    created_by      BIGINT,
+    revoked_at      TIMESTAMP,
+    created         TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated         TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_organization_invites_role CHECK (role IN ('TEACHER', 'STUDENT')),
+    CONSTRAINT chk_organization_invites_uses CHECK (max_uses >= 1 AND uses <= max_uses),
+    FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX idx_organization_invites_organization_id ON organization_invites(organization_id);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS organization_invites;
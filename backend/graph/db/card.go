@@ -18,6 +18,11 @@ func (c *Card) BeforeUpdate(tx *gorm.DB) (err error) {
 	return c.validateStruct(c)
 }
 
+// Validate validates the card in the same way as BeforeCreate, for the queries which do not call hooks
+func (c *Card) Validate() error {
+	return c.validateAtCreate(c)
+}
+
 func (c *Card) validateStruct(card *Card) error {
 	v := customValidator.NewValidateWrapper()
 	err := v.Validator().Struct(card)
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"github.com/lib/pq"
 	"time"
 )
 
@@ -18,41 +19,162 @@ type User struct {
 }
 
 type Card struct {
-	ID           int64     `gorm:"column:id;primaryKey" validate:"number"`
-	Front        string    `gorm:"column:front;not null" validate:"required,min=1"`
-	Back         string    `gorm:"column:back;not null" validate:"required,min=1"`
-	ReviewDate   time.Time `gorm:"column:review_date;not null" validate:"fl_datetime"`
-	IntervalDays int       `gorm:"column:interval_days;default:1;not null" validate:"gte=1"`
-	Created      time.Time `gorm:"column:created;autoCreateTime"`
-	Updated      time.Time `gorm:"column:updated;autoCreateTime"`
-	CardGroupID  int64     `gorm:"column:cardgroup_id" validate:"number"`
-	CardGroup    Cardgroup `gorm:"foreignKey:CardGroupID;references:ID" validate:"-"`
+	ID            int64          `gorm:"column:id;primaryKey" validate:"number"`
+	Front         string         `gorm:"column:front;not null" validate:"required,min=1"`
+	Back          string         `gorm:"column:back;not null" validate:"required,min=1"`
+	Reading       string         `gorm:"column:reading;not null" validate:"-"`
+	Senses        pq.StringArray `gorm:"column:senses;type:text[]" validate:"-"`
+	Tags          pq.StringArray `gorm:"column:tags;type:text[]" validate:"-"`
+	FrequencyRank *int           `gorm:"column:frequency_rank" validate:"omitempty,gte=1"`
+	ReviewDate    time.Time      `gorm:"column:review_date;not null" validate:"fl_datetime"`
+	IntervalDays  int            `gorm:"column:interval_days;default:1;not null" validate:"gte=1"`
+	Created       time.Time      `gorm:"column:created;autoCreateTime"`
+	Updated       time.Time      `gorm:"column:updated;autoCreateTime"`
+	CardGroupID   int64          `gorm:"column:cardgroup_id" validate:"number"`
+	SourceCardID  *int64         `gorm:"column:source_card_id" validate:"-"`
+	CardGroup     Cardgroup      `gorm:"foreignKey:CardGroupID;references:ID" validate:"-"`
 }
 
 type Cardgroup struct {
-	ID      int64     `gorm:"column:id;primaryKey" validate:"number"`
-	Name    string    `gorm:"column:name;not null" validate:"required,fl_name,min=1"`
-	Created time.Time `gorm:"column:created;autoCreateTime"`
-	Updated time.Time `gorm:"column:updated;autoCreateTime"`
-	Cards   []Card    `gorm:"foreignKey:CardGroupID" validate:"-"`
-	Users   []User    `gorm:"many2many:cardgroup_users" validate:"-"`
+	ID                int64     `gorm:"column:id;primaryKey" validate:"number"`
+	Name              string    `gorm:"column:name;not null" validate:"required,fl_name,min=1"`
+	NewCardOrder      string    `gorm:"column:new_card_order;default:RANDOM;not null" validate:"-"`
+	IsPublic          bool      `gorm:"column:is_public;not null" validate:"-"`
+	Description       string    `gorm:"column:description;not null" validate:"-"`
+	SourceLanguage    string    `gorm:"column:source_language;not null" validate:"-"`
+	TargetLanguage    string    `gorm:"column:target_language;not null" validate:"-"`
+	SourceCardGroupID *int64    `gorm:"column:source_cardgroup_id" validate:"-"`
+	SourceVersion     *int      `gorm:"column:source_version" validate:"-"`
+	Subscribed        bool      `gorm:"column:subscribed;not null" validate:"-"`
+	Created           time.Time `gorm:"column:created;autoCreateTime"`
+	Updated           time.Time `gorm:"column:updated;autoCreateTime"`
+	Cards             []Card    `gorm:"foreignKey:CardGroupID" validate:"-"`
+	Users             []User    `gorm:"many2many:cardgroup_users" validate:"-"`
+}
+
+type CardgroupVersion struct {
+	ID          int64     `gorm:"column:id;primaryKey" validate:"number"`
+	CardGroupID int64     `gorm:"column:cardgroup_id;not null" validate:"number"`
+	Version     int       `gorm:"column:version;not null" validate:"gte=1"`
+	Note        string    `gorm:"column:note;not null" validate:"-"`
+	Created     time.Time `gorm:"column:created;autoCreateTime"`
+	Updated     time.Time `gorm:"column:updated;autoCreateTime"`
+}
+
+type CardgroupVersionCard struct {
+	VersionID     int64          `gorm:"column:version_id;primaryKey" validate:"number"`
+	CardID        int64          `gorm:"column:card_id;primaryKey" validate:"number"`
+	Front         string         `gorm:"column:front;not null" validate:"-"`
+	Back          string         `gorm:"column:back;not null" validate:"-"`
+	Reading       string         `gorm:"column:reading;not null" validate:"-"`
+	Senses        pq.StringArray `gorm:"column:senses;type:text[]" validate:"-"`
+	Tags          pq.StringArray `gorm:"column:tags;type:text[]" validate:"-"`
+	FrequencyRank *int           `gorm:"column:frequency_rank" validate:"-"`
 }
 
 type CardgroupUser struct {
 	CardGroupID int64     `gorm:"column:cardgroup_id;primaryKey" validate:"-"`
 	UserID      int64     `gorm:"column:user_id;primaryKey" validate:"number"`
 	State       int       `gorm:"column:state" validate:"number"`
+	Role        string    `gorm:"column:role;default:VIEWER" validate:"-"`
 	Updated     time.Time `gorm:"column:updated;autoUpdateTime"`
 }
 
-type Role struct {
+type CardgroupInvite struct {
+	ID          int64      `gorm:"column:id;primaryKey" validate:"number"`
+	CardGroupID int64      `gorm:"column:cardgroup_id;not null" validate:"number"`
+	TokenHash   string     `gorm:"column:token_hash;not null;unique" validate:"-"`
+	Role        string     `gorm:"column:role;default:VIEWER;not null" validate:"-"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null" validate:"-"`
+	MaxUses     int        `gorm:"column:max_uses;default:1;not null" validate:"gte=1"`
+	Uses        int        `gorm:"column:uses;not null" validate:"gte=0"`
+	CreatedBy   *int64     `gorm:"column:created_by" validate:"-"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at" validate:"-"`
+	Created     time.Time  `gorm:"column:created;autoCreateTime"`
+	Updated     time.Time  `gorm:"column:updated;autoCreateTime"`
+}
+
+type CardSuggestion struct {
+	ID          int64      `gorm:"column:id;primaryKey" validate:"number"`
+	CardID      int64      `gorm:"column:card_id;not null" validate:"number"`
+	CardGroupID int64      `gorm:"column:cardgroup_id;not null" validate:"number"`
+	SuggestedBy *int64     `gorm:"column:suggested_by" validate:"-"`
+	Front       string     `gorm:"column:front;not null" validate:"required,min=1"`
+	Back        string     `gorm:"column:back;not null" validate:"required,min=1"`
+	Comment     string     `gorm:"column:comment;not null" validate:"-"`
+	Status      string     `gorm:"column:status;default:PENDING;not null" validate:"-"`
+	ReviewedBy  *int64     `gorm:"column:reviewed_by" validate:"-"`
+	ReviewedAt  *time.Time `gorm:"column:reviewed_at" validate:"-"`
+	Created     time.Time  `gorm:"column:created;autoCreateTime"`
+	Updated     time.Time  `gorm:"column:updated;autoCreateTime"`
+}
+
+type Organization struct {
 	ID      int64     `gorm:"column:id;primaryKey" validate:"number"`
-	Name    string    `gorm:"column:name;not null" validate:"required,fl_name,min=1"`
-	Users   []User    `gorm:"many2many:user_roles" validate:"-"`
+	Name    string    `gorm:"column:name;not null" validate:"required,min=1"`
 	Created time.Time `gorm:"column:created;autoCreateTime"`
 	Updated time.Time `gorm:"column:updated;autoCreateTime"`
 }
 
+type OrganizationUser struct {
+	OrganizationID int64     `gorm:"column:organization_id;primaryKey" validate:"number"`
+	UserID         int64     `gorm:"column:user_id;primaryKey" validate:"number"`
+	Role           string    `gorm:"column:role;default:STUDENT;not null" validate:"-"`
+	Created        time.Time `gorm:"column:created;autoCreateTime"`
+	Updated        time.Time `gorm:"column:updated;autoCreateTime"`
+}
+
+type OrganizationInvite struct {
+	ID             int64      `gorm:"column:id;primaryKey" validate:"number"`
+	OrganizationID int64      `gorm:"column:organization_id;not null" validate:"number"`
+	TokenHash      string     `gorm:"column:token_hash;not null;unique" validate:"-"`
+	Role           string     `gorm:"column:role;default:STUDENT;not null" validate:"-"`
+	ExpiresAt      time.Time  `gorm:"column:expires_at;not null" validate:"-"`
+	MaxUses        int        `gorm:"column:max_uses;default:1;not null" validate:"gte=1"`
+	Uses           int        `gorm:"column:uses;not null" validate:"gte=0"`
+	CreatedBy      *int64     `gorm:"column:created_by" validate:"-"`
+	RevokedAt      *time.Time `gorm:"column:revoked_at" validate:"-"`
+	Created        time.Time  `gorm:"column:created;autoCreateTime"`
+	Updated        time.Time  `gorm:"column:updated;autoCreateTime"`
+}
+
+type Classroom struct {
+	ID             int64     `gorm:"column:id;primaryKey" validate:"number"`
+	OrganizationID int64     `gorm:"column:organization_id;not null" validate:"number"`
+	Name           string    `gorm:"column:name;not null" validate:"required,min=1"`
+	Created        time.Time `gorm:"column:created;autoCreateTime"`
+	Updated        time.Time `gorm:"column:updated;autoCreateTime"`
+}
+
+type ClassroomStudent struct {
+	ClassroomID int64     `gorm:"column:classroom_id;primaryKey" validate:"number"`
+	UserID      int64     `gorm:"column:user_id;primaryKey" validate:"number"`
+	Created     time.Time `gorm:"column:created;autoCreateTime"`
+}
+
+type ClassroomCardgroup struct {
+	ClassroomID int64     `gorm:"column:classroom_id;primaryKey" validate:"number"`
+	CardGroupID int64     `gorm:"column:cardgroup_id;primaryKey" validate:"number"`
+	Created     time.Time `gorm:"column:created;autoCreateTime"`
+}
+
+type Role struct {
+	ID          int64        `gorm:"column:id;primaryKey" validate:"number"`
+	Name        string       `gorm:"column:name;not null" validate:"required,fl_name,min=1"`
+	Users       []User       `gorm:"many2many:user_roles" validate:"-"`
+	Permissions []Permission `gorm:"many2many:role_permissions" validate:"-"`
+	Created     time.Time    `gorm:"column:created;autoCreateTime"`
+	Updated     time.Time    `gorm:"column:updated;autoCreateTime"`
+}
+
+type Permission struct {
+	ID          int64     `gorm:"column:id;primaryKey" validate:"number"`
+	Name        string    `gorm:"column:name;not null;unique" validate:"required,min=1"`
+	Description string    `gorm:"column:description;not null" validate:"-"`
+	Created     time.Time `gorm:"column:created;autoCreateTime"`
+	Updated     time.Time `gorm:"column:updated;autoCreateTime"`
+}
+
 type SwipeRecord struct {
 	ID          int64     `gorm:"column:id;primaryKey" validate:"number"`
 	UserID      int64     `gorm:"column:user_id" validate:"number"`
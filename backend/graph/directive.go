@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/auth"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/m-mizutani/goerr"
+)
+
+// NewDirectives returns the handlers of the authorization directives declared in schema.graphqls
+func NewDirectives(srv services.Services) DirectiveRoot {
+	return DirectiveRoot{
+		HasRole: func(ctx context.Context, obj interface{}, next graphql.Resolver, role model.RoleName) (interface{}, error) {
+			if err := requireRole(ctx, srv, role); err != nil {
+				return nil, err
+			}
+			return next(ctx)
+		},
+		HasPermission: func(ctx context.Context, obj interface{}, next graphql.Resolver, permission string) (interface{}, error) {
+			if err := requirePermission(ctx, srv, permission); err != nil {
+				return nil, err
+			}
+			return next(ctx)
+		},
+		CardGroupMember: func(ctx context.Context, obj interface{}, next graphql.Resolver, arg string, role model.CardGroupRole) (interface{}, error) {
+			if err := requireCardGroupMember(ctx, srv, arg, role); err != nil {
+				return nil, err
+			}
+			return next(ctx)
+		},
+		OrganizationMember: func(ctx context.Context, obj interface{}, next graphql.Resolver, arg string, role model.OrganizationRole) (interface{}, error) {
+			organizationID, err := idArgument(ctx, arg)
+			if err != nil {
+				return nil, err
+			}
+			if err := requireOrganizationRole(ctx, srv, organizationID, role); err != nil {
+				return nil, err
+			}
+			return next(ctx)
+		},
+	}
+}
+
+// requireRole fails unless the viewer has the role
+func requireRole(ctx context.Context, srv services.Services, role model.RoleName) error {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get viewer")
+	}
+
+	ok, err := srv.UserHasRole(ctx, userID, role.String())
+	if err != nil {
+		return goerr.Wrap(err, "failed to check role")
+	}
+	if !ok {
+		return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("%s role required", role))
+	}
+	return nil
+}
+
+// requirePermission fails unless a role of the viewer grants the permission
+func requirePermission(ctx context.Context, srv services.Services, permission string) error {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get viewer")
+	}
+	return srv.Authorize(ctx, userID, permission)
+}
+
+// requireCardGroupMember fails unless the viewer is a member of the card group given by the argument
+// at the path arg with at least the role, or has the cardgroup:admin permission
+func requireCardGroupMember(ctx context.Context, srv services.Services, arg string, role model.CardGroupRole) error {
+	cardGroupID, err := idArgument(ctx, arg)
+	if err != nil {
+		return err
+	}
+	return requireCardGroupRole(ctx, srv, cardGroupID, role)
+}
+
+// requireCardGroupRole fails unless the viewer is a member of the card group with at least the role,
+// or has the cardgroup:admin permission
+func requireCardGroupRole(ctx context.Context, srv services.Services, cardGroupID int64, role model.CardGroupRole) error {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get viewer")
+	}
+
+	memberRole, err := srv.GetCardGroupRole(ctx, cardGroupID, userID)
+	if err != nil {
+		return goerr.Wrap(err, "failed to check card group membership")
+	}
+	if memberRole != nil && services.CardGroupRoleAtLeast(*memberRole, role) {
+		return nil
+	}
+
+	admin, err := srv.HasPermission(ctx, userID, auth.PermissionCardGroupAdmin)
+	if err != nil {
+		return goerr.Wrap(err, "failed to check permission")
+	}
+	if admin {
+		return nil
+	}
+	if memberRole == nil {
+		return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("not a member of card group : %d", cardGroupID))
+	}
+	return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("%s role required in card group : %d", role, cardGroupID))
+}
+
+// requireOrganizationRole fails unless the viewer is a member of the organization with at least the role,
+// or has the organization:admin permission
+func requireOrganizationRole(ctx context.Context, srv services.Services, organizationID int64, role model.OrganizationRole) error {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get viewer")
+	}
+
+	memberRole, err := srv.GetOrganizationRole(ctx, organizationID, userID)
+	if err != nil {
+		return goerr.Wrap(err, "failed to check organization membership")
+	}
+	if memberRole != nil && services.OrganizationRoleAtLeast(*memberRole, role) {
+		return nil
+	}
+
+	admin, err := srv.HasPermission(ctx, userID, auth.PermissionOrganizationAdmin)
+	if err != nil {
+		return goerr.Wrap(err, "failed to check permission")
+	}
+	if admin {
+		return nil
+	}
+	if memberRole == nil {
+		return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("not a member of organization : %d", organizationID))
+	}
+	return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("%s role required in organization : %d", role, organizationID))
+}
+
+// requireClassroomTeacher fails unless the viewer is a teacher of the organization of the classroom
+func requireClassroomTeacher(ctx context.Context, srv services.Services, classroomID int64) error {
+	classroom, err := srv.GetClassroomByID(ctx, classroomID)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get classroom")
+	}
+	return requireOrganizationRole(ctx, srv, classroom.OrganizationID, model.OrganizationRoleTeacher)
+}
+
+// requireSwipeRecordOwner fails unless the swipe record is of the viewer
+func requireSwipeRecordOwner(ctx context.Context, srv services.Services, id int64) error {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get viewer")
+	}
+
+	swipeRecord, err := srv.GetSwipeRecordByID(ctx, id)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get swipe record")
+	}
+	if swipeRecord.UserID != userID {
+		return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("swipe record of another user : %d", id))
+	}
+	return nil
+}
+
+// requireCardInCardGroup fails unless the card belongs to the card group, whose membership is checked
+// by @cardGroupMember on the card group alone
+func requireCardInCardGroup(ctx context.Context, srv services.Services, cardID int64, cardGroupID int64) error {
+	card, err := srv.GetCardByID(ctx, cardID)
+	if err != nil {
+		return goerr.Wrap(err, "failed to get card")
+	}
+	if card.CardGroupID != cardGroupID {
+		return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("card %d does not belong to card group : %d", cardID, cardGroupID))
+	}
+	return nil
+}
+
+// idArgument reads the ID at the dotted path arg from the raw arguments of the field
+func idArgument(ctx context.Context, arg string) (int64, error) {
+	fc := graphql.GetFieldContext(ctx)
+	var value interface{} = fc.Field.ArgumentMap(graphql.GetOperationContext(ctx).Variables)
+	for _, name := range strings.Split(arg, ".") {
+		args, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, goerr.New("invalid argument path").With("arg", arg)
+		}
+		value = args[name]
+	}
+
+	id, err := graphql.UnmarshalInt64(value)
+	if err != nil {
+		return 0, goerr.Wrap(err, fmt.Sprintf("invalid ID in argument %s", arg))
+	}
+	return id, nil
+}
@@ -3,19 +3,27 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 )
 
 type Card struct {
-	ID           int64      `json:"id"`
-	Front        string     `json:"front" validate:"required,min=1"`
-	Back         string     `json:"back" validate:"required,min=1"`
-	ReviewDate   time.Time  `json:"review_date"`
-	IntervalDays int        `json:"interval_days" validate:"gte=1"`
-	Created      time.Time  `json:"created"`
-	Updated      time.Time  `json:"updated"`
-	CardGroupID  int64      `json:"cardGroupID"`
-	CardGroup    *CardGroup `json:"cardGroup" validate:"-"`
+	ID            int64      `json:"id"`
+	Front         string     `json:"front" validate:"required,min=1"`
+	Back          string     `json:"back" validate:"required,min=1"`
+	Reading       string     `json:"reading"`
+	Ruby          string     `json:"ruby"`
+	Senses        []string   `json:"senses" validate:"-"`
+	Tags          []string   `json:"tags" validate:"-"`
+	FrequencyRank *int       `json:"frequencyRank,omitempty"`
+	ReviewDate    time.Time  `json:"review_date"`
+	IntervalDays  int        `json:"interval_days" validate:"gte=1"`
+	Created       time.Time  `json:"created"`
+	Updated       time.Time  `json:"updated"`
+	CardGroupID   int64      `json:"cardGroupID"`
+	CardGroup     *CardGroup `json:"cardGroup" validate:"-"`
 }
 
 type CardConnection struct {
@@ -25,18 +33,32 @@ type CardConnection struct {
 	TotalCount int         `json:"totalCount"`
 }
 
+type CardCreatePreview struct {
+	Front  string   `json:"front"`
+	Back   string   `json:"back"`
+	Senses []string `json:"senses"`
+}
+
 type CardEdge struct {
 	Cursor int64 `json:"cursor"`
 	Node   *Card `json:"node" validate:"-"`
 }
 
 type CardGroup struct {
-	ID      int64           `json:"id"`
-	Name    string          `json:"name" validate:"required,fl_name,min=1"`
-	Created time.Time       `json:"created"`
-	Updated time.Time       `json:"updated"`
-	Cards   *CardConnection `json:"cards" validate:"-"`
-	Users   *UserConnection `json:"users" validate:"-"`
+	ID                int64           `json:"id"`
+	Name              string          `json:"name" validate:"required,fl_name,min=1"`
+	NewCardOrder      NewCardOrder    `json:"newCardOrder"`
+	IsPublic          bool            `json:"isPublic"`
+	Description       string          `json:"description"`
+	SourceLanguage    string          `json:"sourceLanguage"`
+	TargetLanguage    string          `json:"targetLanguage"`
+	SourceCardGroupID *int64          `json:"sourceCardGroupID,omitempty"`
+	SourceVersion     *int            `json:"sourceVersion,omitempty"`
+	Subscribed        bool            `json:"subscribed"`
+	Created           time.Time       `json:"created"`
+	Updated           time.Time       `json:"updated"`
+	Cards             *CardConnection `json:"cards" validate:"-"`
+	Users             *UserConnection `json:"users" validate:"-"`
 }
 
 type CardGroupConnection struct {
@@ -51,25 +73,171 @@ type CardGroupEdge struct {
 	Node   *CardGroup `json:"node" validate:"-"`
 }
 
+type CardGroupInvite struct {
+	ID          int64         `json:"id"`
+	CardGroupID int64         `json:"cardGroupID"`
+	Token       *string       `json:"token,omitempty"`
+	Role        CardGroupRole `json:"role"`
+	ExpiresAt   time.Time     `json:"expiresAt"`
+	MaxUses     int           `json:"maxUses"`
+	Uses        int           `json:"uses"`
+	Revoked     bool          `json:"revoked"`
+	Created     time.Time     `json:"created"`
+}
+
+type CardGroupUpdate struct {
+	CardGroupID    int64                `json:"cardGroupID"`
+	CurrentVersion *int                 `json:"currentVersion,omitempty"`
+	LatestVersion  int                  `json:"latestVersion"`
+	Added          []*VersionCard       `json:"added" validate:"-"`
+	Changed        []*VersionCardChange `json:"changed" validate:"-"`
+	Removed        []*VersionCard       `json:"removed" validate:"-"`
+	Conflicts      []*VersionCard       `json:"conflicts" validate:"-"`
+}
+
+type CardGroupVersion struct {
+	ID          int64     `json:"id"`
+	CardGroupID int64     `json:"cardGroupID"`
+	Version     int       `json:"version"`
+	Note        string    `json:"note"`
+	CardCount   int       `json:"cardCount"`
+	Created     time.Time `json:"created"`
+}
+
+type CardSuggestion struct {
+	ID          int64                `json:"id"`
+	CardID      int64                `json:"cardID"`
+	CardGroupID int64                `json:"cardGroupID"`
+	SuggestedBy *int64               `json:"suggestedBy,omitempty"`
+	Front       string               `json:"front"`
+	Back        string               `json:"back"`
+	Comment     string               `json:"comment"`
+	Status      CardSuggestionStatus `json:"status"`
+	ReviewedBy  *int64               `json:"reviewedBy,omitempty"`
+	ReviewedAt  *time.Time           `json:"reviewedAt,omitempty"`
+	Created     time.Time            `json:"created"`
+}
+
+type CardUpdatePreview struct {
+	ID         int64   `json:"id"`
+	Front      string  `json:"front"`
+	NewFront   string  `json:"newFront"`
+	OldBack    string  `json:"oldBack"`
+	NewBack    string  `json:"newBack"`
+	Similarity float64 `json:"similarity"`
+}
+
+type Classroom struct {
+	ID             int64     `json:"id"`
+	OrganizationID int64     `json:"organizationID"`
+	Name           string    `json:"name"`
+	StudentIDs     []int64   `json:"studentIDs"`
+	CardGroupIDs   []int64   `json:"cardGroupIDs"`
+	Created        time.Time `json:"created"`
+	Updated        time.Time `json:"updated"`
+}
+
+type ConfirmSubtitleCandidates struct {
+	CardgroupID int64           `json:"cardgroup_id"`
+	Candidates  []*SentenceCard `json:"candidates"`
+}
+
+type DictionaryEntry struct {
+	Kanji    []string `json:"kanji"`
+	Readings []string `json:"readings"`
+	Meanings []string `json:"meanings"`
+	Common   bool     `json:"common"`
+}
+
+type DictionaryPreview struct {
+	Creates   []*CardCreatePreview `json:"creates" validate:"-"`
+	Updates   []*CardUpdatePreview `json:"updates" validate:"-"`
+	Unchanged []*Card              `json:"unchanged" validate:"-"`
+	Deletes   []*Card              `json:"deletes" validate:"-"`
+}
+
+type DuplicateCardCluster struct {
+	Cards []*Card `json:"cards" validate:"-"`
+}
+
+type ImportAnkiPackage struct {
+	Name           string  `json:"name" validate:"required,min=1"`
+	Package        string  `json:"package" validate:"required"`
+	FrontField     *string `json:"frontField,omitempty"`
+	BackField      *string `json:"backField,omitempty"`
+	IncludeHistory *bool   `json:"includeHistory,omitempty"`
+}
+
+type ImportKindleVocabulary struct {
+	CardgroupID int64   `json:"cardgroup_id"`
+	Vocabulary  string  `json:"vocabulary" validate:"required"`
+	Language    *string `json:"language,omitempty"`
+}
+
+type ImportWorkbook struct {
+	Workbook    string              `json:"workbook" validate:"required"`
+	FrontColumn string              `json:"frontColumn" validate:"required"`
+	BackColumn  string              `json:"backColumn" validate:"required"`
+	TagsColumn  *string             `json:"tagsColumn,omitempty"`
+	HeaderRow   *bool               `json:"headerRow,omitempty"`
+	Mode        *WorkbookImportMode `json:"mode,omitempty"`
+	Name        *string             `json:"name,omitempty"`
+	AutoFill    *bool               `json:"autoFill,omitempty"`
+}
+
 type Mutation struct {
 }
 
 type NewCard struct {
-	Front        string    `json:"front" validate:"required,min=1"`
-	Back         string    `json:"back" validate:"required,min=1"`
-	ReviewDate   time.Time `json:"review_date"`
-	IntervalDays *int      `json:"interval_days,omitempty" validate:"gte=1"`
-	CardgroupID  int64     `json:"cardgroup_id"`
-	Created      time.Time `json:"created"`
-	Updated      time.Time `json:"updated"`
+	Front         string    `json:"front" validate:"required,min=1"`
+	Back          string    `json:"back" validate:"required,min=1"`
+	Reading       *string   `json:"reading,omitempty"`
+	Senses        []string  `json:"senses,omitempty" validate:"-"`
+	Tags          []string  `json:"tags,omitempty" validate:"-"`
+	FrequencyRank *int      `json:"frequencyRank,omitempty" validate:"omitempty,gte=1"`
+	ReviewDate    time.Time `json:"review_date"`
+	IntervalDays  *int      `json:"interval_days,omitempty" validate:"gte=1"`
+	CardgroupID   int64     `json:"cardgroup_id"`
+	Created       time.Time `json:"created"`
+	Updated       time.Time `json:"updated"`
 }
 
 type NewCardGroup struct {
-	Name    string    `json:"name" validate:"required,min=1"`
-	CardIds []int64   `json:"card_ids,omitempty"`
-	UserIds []int64   `json:"user_ids"`
-	Created time.Time `json:"created"`
-	Updated time.Time `json:"updated"`
+	Name         string        `json:"name" validate:"required,min=1"`
+	CardIds      []int64       `json:"card_ids,omitempty"`
+	NewCardOrder *NewCardOrder `json:"newCardOrder,omitempty"`
+	Created      time.Time     `json:"created"`
+	Updated      time.Time     `json:"updated"`
+}
+
+type NewCardGroupInvite struct {
+	CardGroupID int64         `json:"cardGroupID" validate:"required"`
+	Role        CardGroupRole `json:"role"`
+	ExpiresAt   time.Time     `json:"expiresAt"`
+	MaxUses     int           `json:"maxUses" validate:"gte=1"`
+}
+
+type NewCardSuggestion struct {
+	CardID  int64  `json:"cardID" validate:"required"`
+	Front   string `json:"front" validate:"required,min=1"`
+	Back    string `json:"back" validate:"required,min=1"`
+	Comment string `json:"comment" validate:"-"`
+}
+
+type NewClassroom struct {
+	OrganizationID int64  `json:"organizationID" validate:"required"`
+	Name           string `json:"name" validate:"required,min=1"`
+}
+
+type NewOrganization struct {
+	Name string `json:"name" validate:"required,min=1"`
+}
+
+type NewOrganizationInvite struct {
+	OrganizationID int64            `json:"organizationID" validate:"required"`
+	Role           OrganizationRole `json:"role"`
+	ExpiresAt      time.Time        `json:"expiresAt"`
+	MaxUses        int              `json:"maxUses" validate:"gte=1"`
 }
 
 type NewRole struct {
@@ -96,6 +264,25 @@ type NewUser struct {
 	Updated  time.Time `json:"updated"`
 }
 
+type Organization struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+type OrganizationInvite struct {
+	ID             int64            `json:"id"`
+	OrganizationID int64            `json:"organizationID"`
+	Token          *string          `json:"token,omitempty"`
+	Role           OrganizationRole `json:"role"`
+	ExpiresAt      time.Time        `json:"expiresAt"`
+	MaxUses        int              `json:"maxUses"`
+	Uses           int              `json:"uses"`
+	Revoked        bool             `json:"revoked"`
+	Created        time.Time        `json:"created"`
+}
+
 type PageInfo struct {
 	EndCursor       *int64 `json:"endCursor,omitempty"`
 	HasNextPage     bool   `json:"hasNextPage"`
@@ -103,15 +290,42 @@ type PageInfo struct {
 	StartCursor     *int64 `json:"startCursor,omitempty"`
 }
 
+type Permission struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ProseCandidate struct {
+	Front        string `json:"front"`
+	Back         string `json:"back"`
+	Reading      string `json:"reading"`
+	PartOfSpeech string `json:"partOfSpeech"`
+	Count        int    `json:"count"`
+}
+
+type ProseCandidates struct {
+	CardgroupID int64  `json:"cardgroup_id"`
+	Text        string `json:"text" validate:"required"`
+}
+
+type PublishCardGroup struct {
+	CardGroupID    int64  `json:"cardGroupID" validate:"required"`
+	Description    string `json:"description" validate:"max=2000"`
+	SourceLanguage string `json:"sourceLanguage" validate:"required,min=2,max=10"`
+	TargetLanguage string `json:"targetLanguage" validate:"required,min=2,max=10"`
+}
+
 type Query struct {
 }
 
 type Role struct {
-	ID      int64           `json:"id"`
-	Name    string          `json:"name" validate:"required,fl_name,min=1"`
-	Created time.Time       `json:"created"`
-	Updated time.Time       `json:"updated"`
-	Users   *UserConnection `json:"users" validate:"-"`
+	ID          int64           `json:"id"`
+	Name        string          `json:"name" validate:"required,fl_name,min=1"`
+	Created     time.Time       `json:"created"`
+	Updated     time.Time       `json:"updated"`
+	Users       *UserConnection `json:"users" validate:"-"`
+	Permissions []*Permission   `json:"permissions" validate:"-"`
 }
 
 type RoleConnection struct {
@@ -126,6 +340,31 @@ type RoleEdge struct {
 	Node   *Role `json:"node" validate:"-"`
 }
 
+type SentenceCard struct {
+	Front string `json:"front" validate:"required,min=1"`
+	Back  string `json:"back" validate:"required,min=1"`
+}
+
+type StudentProgress struct {
+	UserID        int64   `json:"userID"`
+	CardsReviewed int     `json:"cardsReviewed"`
+	Accuracy      float64 `json:"accuracy"`
+	OverdueCount  int     `json:"overdueCount"`
+}
+
+type SubtitleCandidate struct {
+	Front   string `json:"front"`
+	Back    string `json:"back"`
+	StartMs int    `json:"startMs"`
+	EndMs   int    `json:"endMs"`
+}
+
+type SubtitleCandidates struct {
+	CardgroupID int64   `json:"cardgroup_id"`
+	Subtitle    string  `json:"subtitle" validate:"required"`
+	Translation *string `json:"translation,omitempty"`
+}
+
 type SwipeRecord struct {
 	ID          int64     `json:"id"`
 	UserID      int64     `json:"userId"`
@@ -151,6 +390,8 @@ type SwipeRecordEdge struct {
 type UpsertDictionary struct {
 	CardgroupID int64  `json:"cardgroup_id"`
 	Dictionary  string `json:"dictionary" validate:"required"`
+	Mirror      *bool  `json:"mirror,omitempty"`
+	AutoFill    *bool  `json:"autoFill,omitempty"`
 }
 
 type User struct {
@@ -175,3 +416,265 @@ type UserEdge struct {
 	Cursor int64 `json:"cursor"`
 	Node   *User `json:"node" validate:"-"`
 }
+
+type VersionCard struct {
+	SourceCardID int64  `json:"sourceCardID"`
+	Front        string `json:"front"`
+	Back         string `json:"back"`
+	Reading      string `json:"reading"`
+}
+
+type VersionCardChange struct {
+	Before *VersionCard `json:"before"`
+	After  *VersionCard `json:"after"`
+}
+
+type CardGroupRole string
+
+const (
+	CardGroupRoleOwner  CardGroupRole = "OWNER"
+	CardGroupRoleEditor CardGroupRole = "EDITOR"
+	CardGroupRoleViewer CardGroupRole = "VIEWER"
+)
+
+var AllCardGroupRole = []CardGroupRole{
+	CardGroupRoleOwner,
+	CardGroupRoleEditor,
+	CardGroupRoleViewer,
+}
+
+func (e CardGroupRole) IsValid() bool {
+	switch e {
+	case CardGroupRoleOwner, CardGroupRoleEditor, CardGroupRoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e CardGroupRole) String() string {
+	return string(e)
+}
+
+func (e *CardGroupRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CardGroupRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CardGroupRole", str)
+	}
+	return nil
+}
+
+func (e CardGroupRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CardSuggestionStatus string
+
+const (
+	CardSuggestionStatusPending  CardSuggestionStatus = "PENDING"
+	CardSuggestionStatusApproved CardSuggestionStatus = "APPROVED"
+	CardSuggestionStatusRejected CardSuggestionStatus = "REJECTED"
+)
+
+var AllCardSuggestionStatus = []CardSuggestionStatus{
+	CardSuggestionStatusPending,
+	CardSuggestionStatusApproved,
+	CardSuggestionStatusRejected,
+}
+
+func (e CardSuggestionStatus) IsValid() bool {
+	switch e {
+	case CardSuggestionStatusPending, CardSuggestionStatusApproved, CardSuggestionStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e CardSuggestionStatus) String() string {
+	return string(e)
+}
+
+func (e *CardSuggestionStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CardSuggestionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CardSuggestionStatus", str)
+	}
+	return nil
+}
+
+func (e CardSuggestionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type NewCardOrder string
+
+const (
+	NewCardOrderRandom      NewCardOrder = "RANDOM"
+	NewCardOrderFrequency   NewCardOrder = "FREQUENCY"
+	NewCardOrderImportOrder NewCardOrder = "IMPORT_ORDER"
+)
+
+var AllNewCardOrder = []NewCardOrder{
+	NewCardOrderRandom,
+	NewCardOrderFrequency,
+	NewCardOrderImportOrder,
+}
+
+func (e NewCardOrder) IsValid() bool {
+	switch e {
+	case NewCardOrderRandom, NewCardOrderFrequency, NewCardOrderImportOrder:
+		return true
+	}
+	return false
+}
+
+func (e NewCardOrder) String() string {
+	return string(e)
+}
+
+func (e *NewCardOrder) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NewCardOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NewCardOrder", str)
+	}
+	return nil
+}
+
+func (e NewCardOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrganizationRole string
+
+const (
+	OrganizationRoleTeacher OrganizationRole = "TEACHER"
+	OrganizationRoleStudent OrganizationRole = "STUDENT"
+)
+
+var AllOrganizationRole = []OrganizationRole{
+	OrganizationRoleTeacher,
+	OrganizationRoleStudent,
+}
+
+func (e OrganizationRole) IsValid() bool {
+	switch e {
+	case OrganizationRoleTeacher, OrganizationRoleStudent:
+		return true
+	}
+	return false
+}
+
+func (e OrganizationRole) String() string {
+	return string(e)
+}
+
+func (e *OrganizationRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrganizationRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrganizationRole", str)
+	}
+	return nil
+}
+
+func (e OrganizationRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RoleName string
+
+const (
+	RoleNameAdmin RoleName = "ADMIN"
+)
+
+var AllRoleName = []RoleName{
+	RoleNameAdmin,
+}
+
+func (e RoleName) IsValid() bool {
+	switch e {
+	case RoleNameAdmin:
+		return true
+	}
+	return false
+}
+
+func (e RoleName) String() string {
+	return string(e)
+}
+
+func (e *RoleName) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RoleName(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RoleName", str)
+	}
+	return nil
+}
+
+func (e RoleName) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WorkbookImportMode string
+
+const (
+	WorkbookImportModeSheetPerGroup WorkbookImportMode = "SHEET_PER_GROUP"
+	WorkbookImportModeMerged        WorkbookImportMode = "MERGED"
+)
+
+var AllWorkbookImportMode = []WorkbookImportMode{
+	WorkbookImportModeSheetPerGroup,
+	WorkbookImportModeMerged,
+}
+
+func (e WorkbookImportMode) IsValid() bool {
+	switch e {
+	case WorkbookImportModeSheetPerGroup, WorkbookImportModeMerged:
+		return true
+	}
+	return false
+}
+
+func (e WorkbookImportMode) String() string {
+	return string(e)
+}
+
+func (e *WorkbookImportMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WorkbookImportMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WorkbookImportMode", str)
+	}
+	return nil
+}
+
+func (e WorkbookImportMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"backend/graph/model"
 	"backend/graph/services"
 	"backend/pkg/usecases"
 	"backend/pkg/validator"
@@ -16,3 +17,30 @@ type Resolver struct {
 	VW  validator.ValidateWrapper
 	*Loaders
 }
+
+// newCardConnection wraps cards returned by a mutation in a connection without pagination
+func newCardConnection(cards []*model.Card) *model.CardConnection {
+	cardConnection := &model.CardConnection{
+		Edges:    make([]*model.CardEdge, len(cards)),
+		Nodes:    make([]*model.Card, len(cards)),
+		PageInfo: &model.PageInfo{},
+	}
+
+	for i, card := range cards {
+		edge := &model.CardEdge{
+			Node:   card,
+			Cursor: card.ID,
+		}
+		cardConnection.Edges[i] = edge
+		cardConnection.Nodes[i] = card
+	}
+
+	if len(cards) > 0 {
+		cardConnection.PageInfo.StartCursor = &cards[0].ID
+		cardConnection.PageInfo.EndCursor = &cards[len(cards)-1].ID
+		cardConnection.PageInfo.HasPreviousPage = false
+		cardConnection.PageInfo.HasNextPage = false
+	}
+
+	return cardConnection
+}
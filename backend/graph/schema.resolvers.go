@@ -6,6 +6,7 @@ package graph
 
 import (
 	"backend/graph/model"
+	"backend/pkg/auth"
 	"context"
 	"fmt"
 
@@ -157,11 +158,28 @@ func (r *mutationResolver) UpdateCard(ctx context.Context, id int64, input model
 	if err := r.VW.ValidateStruct(input); err != nil {
 		return nil, goerr.Wrap(err, "invalid input UpdateCard")
 	}
+	card, err := r.Srv.GetCardByID(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, card.CardGroupID, model.CardGroupRoleEditor); err != nil {
+		return nil, err
+	}
+	if card.CardGroupID != input.CardgroupID {
+		return nil, goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("card %d does not belong to card group : %d", id, input.CardgroupID))
+	}
 	return r.Srv.UpdateCard(ctx, id, input)
 }
 
 // DeleteCard is the resolver for the deleteCard field.
 func (r *mutationResolver) DeleteCard(ctx context.Context, id int64) (*bool, error) {
+	card, err := r.Srv.GetCardByID(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, card.CardGroupID, model.CardGroupRoleEditor); err != nil {
+		return nil, err
+	}
 	return r.Srv.DeleteCard(ctx, id)
 }
 
@@ -170,7 +188,20 @@ func (r *mutationResolver) CreateCardGroup(ctx context.Context, input model.NewC
 	if err := r.VW.ValidateStruct(input); err != nil {
 		return nil, goerr.Wrap(err, "invalid input CreateCardGroup")
 	}
-	return r.Srv.CreateCardGroup(ctx, input)
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	cardGroup, err := r.Srv.CreateCardGroup(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create card group")
+	}
+	if _, err := r.Srv.AddUserToCardGroup(ctx, userID, cardGroup.ID, model.CardGroupRoleOwner); err != nil {
+		return nil, goerr.Wrap(err, "failed to add owner to card group")
+	}
+	return cardGroup, nil
 }
 
 // UpdateCardGroup is the resolver for the updateCardGroup field.
@@ -229,8 +260,8 @@ func (r *mutationResolver) DeleteRole(ctx context.Context, id int64) (*bool, err
 }
 
 // AddUserToCardGroup is the resolver for the addUserToCardGroup field.
-func (r *mutationResolver) AddUserToCardGroup(ctx context.Context, userID int64, cardGroupID int64) (*model.CardGroup, error) {
-	return r.Srv.AddUserToCardGroup(ctx, userID, cardGroupID)
+func (r *mutationResolver) AddUserToCardGroup(ctx context.Context, userID int64, cardGroupID int64, role model.CardGroupRole) (*model.CardGroup, error) {
+	return r.Srv.AddUserToCardGroup(ctx, userID, cardGroupID, role)
 }
 
 // RemoveUserFromCardGroup is the resolver for the removeUserFromCardGroup field.
@@ -250,61 +281,511 @@ func (r *mutationResolver) RemoveRoleFromUser(ctx context.Context, userID int64,
 
 // CreateSwipeRecord is the resolver for the createSwipeRecord field.
 func (r *mutationResolver) CreateSwipeRecord(ctx context.Context, input model.NewSwipeRecord) (*model.SwipeRecord, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+	input.UserID = userID
+
 	if err := r.VW.ValidateStruct(input); err != nil {
 		return nil, goerr.Wrap(err, "invalid input CreateSwipeRecord")
 	}
+	if err := requireCardInCardGroup(ctx, r.Srv, input.CardID, input.CardGroupID); err != nil {
+		return nil, err
+	}
 	return r.Srv.CreateSwipeRecord(ctx, input)
 }
 
 // UpdateSwipeRecord is the resolver for the updateSwipeRecord field.
 func (r *mutationResolver) UpdateSwipeRecord(ctx context.Context, id int64, input model.NewSwipeRecord) (*model.SwipeRecord, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+	input.UserID = userID
+
 	if err := r.VW.ValidateStruct(input); err != nil {
 		return nil, goerr.Wrap(err, "invalid input UpdateSwipeRecord")
 	}
+	if err := requireSwipeRecordOwner(ctx, r.Srv, id); err != nil {
+		return nil, err
+	}
+	if err := requireCardInCardGroup(ctx, r.Srv, input.CardID, input.CardGroupID); err != nil {
+		return nil, err
+	}
 	return r.Srv.UpdateSwipeRecord(ctx, id, input)
 }
 
 // DeleteSwipeRecord is the resolver for the deleteSwipeRecord field.
 func (r *mutationResolver) DeleteSwipeRecord(ctx context.Context, id int64) (*bool, error) {
+	if err := requireSwipeRecordOwner(ctx, r.Srv, id); err != nil {
+		return nil, err
+	}
 	return r.Srv.DeleteSwipeRecord(ctx, id)
 }
 
 // UpsertDictionary is the resolver for the upsertDictionary field.
 func (r *mutationResolver) UpsertDictionary(ctx context.Context, input model.UpsertDictionary) (*model.CardConnection, error) {
-	createdCards, err := r.U.UpsertCards(ctx, input.Dictionary,
-		input.CardgroupID)
+	createdCards, err := r.U.UpsertCards(ctx, input)
 	if err != nil {
 		return nil, goerr.Wrap(err, "failed to upsert cards")
 	}
 
-	cardConnection := &model.CardConnection{
-		Edges:    make([]*model.CardEdge, len(createdCards)),
-		Nodes:    make([]*model.Card, len(createdCards)),
-		PageInfo: &model.PageInfo{},
+	return newCardConnection(createdCards), nil
+}
+
+// HandleSwipe is the resolver for the handleSwipe field.
+func (r *mutationResolver) HandleSwipe(ctx context.Context, input model.NewSwipeRecord) ([]*model.Card, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
 	}
+	input.UserID = userID
 
-	for i, card := range createdCards {
-		edge := &model.CardEdge{
-			Node:   card,
-			Cursor: card.ID,
+	if err := requireCardInCardGroup(ctx, r.Srv, input.CardID, input.CardGroupID); err != nil {
+		return nil, err
+	}
+	return r.U.HandleSwipe(ctx, input)
+}
+
+// MergeCards is the resolver for the mergeCards field.
+func (r *mutationResolver) MergeCards(ctx context.Context, targetCardID int64, sourceCardIDs []int64) (*model.Card, error) {
+	// The source cards must belong to the card group of the target card
+	target, err := r.Srv.GetCardByID(ctx, targetCardID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get target card")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, target.CardGroupID, model.CardGroupRoleEditor); err != nil {
+		return nil, err
+	}
+	return r.Srv.MergeCards(ctx, targetCardID, sourceCardIDs)
+}
+
+// ImportAnkiPackage is the resolver for the importAnkiPackage field.
+func (r *mutationResolver) ImportAnkiPackage(ctx context.Context, input model.ImportAnkiPackage) (*model.CardGroup, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	cardGroup, err := r.U.ImportAnkiPackage(ctx, userID, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to import anki package")
+	}
+	return cardGroup, nil
+}
+
+// ImportKindleVocabulary is the resolver for the importKindleVocabulary field.
+func (r *mutationResolver) ImportKindleVocabulary(ctx context.Context, input model.ImportKindleVocabulary) (*model.CardConnection, error) {
+	createdCards, err := r.U.ImportKindleVocabulary(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to import kindle vocabulary")
+	}
+
+	return newCardConnection(createdCards), nil
+}
+
+// ConfirmSubtitleCandidates is the resolver for the confirmSubtitleCandidates field.
+func (r *mutationResolver) ConfirmSubtitleCandidates(ctx context.Context, input model.ConfirmSubtitleCandidates) (*model.CardConnection, error) {
+	for _, candidate := range input.Candidates {
+		if err := r.VW.ValidateStruct(candidate); err != nil {
+			return nil, goerr.Wrap(err, "invalid input ConfirmSubtitleCandidates")
 		}
-		cardConnection.Edges[i] = edge
-		cardConnection.Nodes[i] = card
 	}
 
-	if len(createdCards) > 0 {
-		cardConnection.PageInfo.StartCursor = &createdCards[0].ID
-		cardConnection.PageInfo.EndCursor = &createdCards[len(createdCards)-1].ID
-		cardConnection.PageInfo.HasPreviousPage = false // Assuming no pagination for this case
-		cardConnection.PageInfo.HasNextPage = false     // Assuming no pagination for this case
+	createdCards, err := r.U.ConfirmSubtitleCandidates(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to confirm subtitle candidates")
 	}
 
-	return cardConnection, nil
+	return newCardConnection(createdCards), nil
 }
 
-// HandleSwipe is the resolver for the handleSwipe field.
-func (r *mutationResolver) HandleSwipe(ctx context.Context, input model.NewSwipeRecord) ([]*model.Card, error) {
-	return r.U.HandleSwipe(ctx, input)
+// ImportWorkbook is the resolver for the importWorkbook field.
+func (r *mutationResolver) ImportWorkbook(ctx context.Context, input model.ImportWorkbook) ([]*model.CardGroup, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input ImportWorkbook")
+	}
+
+	cardGroups, err := r.U.ImportWorkbook(ctx, userID, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to import workbook")
+	}
+	return cardGroups, nil
+}
+
+// RankCardsByFrequency is the resolver for the rankCardsByFrequency field.
+func (r *mutationResolver) RankCardsByFrequency(ctx context.Context, cardGroupID int64) (*model.CardConnection, error) {
+	cards, err := r.U.RankCardsByFrequency(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to rank cards by frequency")
+	}
+
+	return newCardConnection(cards), nil
+}
+
+// FillReadings is the resolver for the fillReadings field.
+func (r *mutationResolver) FillReadings(ctx context.Context, cardGroupID int64) (*model.CardConnection, error) {
+	cards, err := r.U.FillReadings(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fill readings")
+	}
+
+	return newCardConnection(cards), nil
+}
+
+// CreateCardGroupInvite is the resolver for the createCardGroupInvite field.
+func (r *mutationResolver) CreateCardGroupInvite(ctx context.Context, input model.NewCardGroupInvite) (*model.CardGroupInvite, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input CreateCardGroupInvite")
+	}
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	invite, err := r.Srv.CreateCardGroupInvite(ctx, input, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create card group invite")
+	}
+	return invite, nil
+}
+
+// AcceptInvite is the resolver for the acceptInvite field.
+func (r *mutationResolver) AcceptInvite(ctx context.Context, token string) (*model.CardGroup, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	cardGroup, err := r.Srv.AcceptInvite(ctx, token, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to accept invite")
+	}
+	return cardGroup, nil
+}
+
+// RevokeInvite is the resolver for the revokeInvite field.
+func (r *mutationResolver) RevokeInvite(ctx context.Context, id int64) (*bool, error) {
+	invite, err := r.Srv.GetCardGroupInviteByID(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get invite")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, invite.CardGroupID, model.CardGroupRoleOwner); err != nil {
+		return nil, err
+	}
+	return r.Srv.RevokeInvite(ctx, id)
+}
+
+// SuggestCardEdit is the resolver for the suggestCardEdit field.
+func (r *mutationResolver) SuggestCardEdit(ctx context.Context, input model.NewCardSuggestion) (*model.CardSuggestion, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input SuggestCardEdit")
+	}
+
+	card, err := r.Srv.GetCardByID(ctx, input.CardID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, card.CardGroupID, model.CardGroupRoleViewer); err != nil {
+		return nil, err
+	}
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	suggestion, err := r.Srv.SuggestCardEdit(ctx, input, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to suggest card edit")
+	}
+	return suggestion, nil
+}
+
+// ApproveCardSuggestion is the resolver for the approveCardSuggestion field.
+func (r *mutationResolver) ApproveCardSuggestion(ctx context.Context, id int64) (*model.Card, error) {
+	suggestion, err := r.Srv.GetCardSuggestionByID(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card suggestion")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, suggestion.CardGroupID, model.CardGroupRoleOwner); err != nil {
+		return nil, err
+	}
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	card, err := r.Srv.ApproveCardSuggestion(ctx, id, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to approve card suggestion")
+	}
+	return card, nil
+}
+
+// RejectCardSuggestion is the resolver for the rejectCardSuggestion field.
+func (r *mutationResolver) RejectCardSuggestion(ctx context.Context, id int64) (*model.CardSuggestion, error) {
+	suggestion, err := r.Srv.GetCardSuggestionByID(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card suggestion")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, suggestion.CardGroupID, model.CardGroupRoleOwner); err != nil {
+		return nil, err
+	}
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	suggestion, err = r.Srv.RejectCardSuggestion(ctx, id, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to reject card suggestion")
+	}
+	return suggestion, nil
+}
+
+// PublishCardGroup is the resolver for the publishCardGroup field.
+func (r *mutationResolver) PublishCardGroup(ctx context.Context, input model.PublishCardGroup) (*model.CardGroup, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input PublishCardGroup")
+	}
+
+	cardGroup, err := r.Srv.PublishCardGroup(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to publish card group")
+	}
+	return cardGroup, nil
+}
+
+// PublishCardGroupVersion is the resolver for the publishCardGroupVersion field.
+func (r *mutationResolver) PublishCardGroupVersion(ctx context.Context, cardGroupID int64, note *string) (*model.CardGroupVersion, error) {
+	versionNote := ""
+	if note != nil {
+		versionNote = *note
+	}
+
+	version, err := r.Srv.PublishCardGroupVersion(ctx, cardGroupID, versionNote)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to publish card group version")
+	}
+	return version, nil
+}
+
+// ApplyCardGroupUpdate is the resolver for the applyCardGroupUpdate field.
+func (r *mutationResolver) ApplyCardGroupUpdate(ctx context.Context, cardGroupID int64) (*model.CardGroup, error) {
+	cardGroup, err := r.Srv.GetCardGroupByID(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card group")
+	}
+	if !cardGroup.Subscribed {
+		if err := requireCardGroupRole(ctx, r.Srv, cardGroupID, model.CardGroupRoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
+	cardGroup, err = r.Srv.ApplyCardGroupUpdate(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to apply card group update")
+	}
+	return cardGroup, nil
+}
+
+// UnpublishCardGroup is the resolver for the unpublishCardGroup field.
+func (r *mutationResolver) UnpublishCardGroup(ctx context.Context, id int64) (*model.CardGroup, error) {
+	cardGroup, err := r.Srv.UnpublishCardGroup(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to unpublish card group")
+	}
+	return cardGroup, nil
+}
+
+// SubscribeCardGroup is the resolver for the subscribeCardGroup field.
+func (r *mutationResolver) SubscribeCardGroup(ctx context.Context, id int64) (*model.CardGroup, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	cardGroup, err := r.Srv.SubscribeCardGroup(ctx, id, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to subscribe card group")
+	}
+	return cardGroup, nil
+}
+
+// UnsubscribeCardGroup is the resolver for the unsubscribeCardGroup field.
+func (r *mutationResolver) UnsubscribeCardGroup(ctx context.Context, id int64) (*bool, error) {
+	return r.Srv.UnsubscribeCardGroup(ctx, id)
+}
+
+// ForkCardGroup is the resolver for the forkCardGroup field.
+func (r *mutationResolver) ForkCardGroup(ctx context.Context, id int64) (*model.CardGroup, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	cardGroup, err := r.Srv.ForkCardGroup(ctx, id, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fork card group")
+	}
+	return cardGroup, nil
+}
+
+// CreateOrganization is the resolver for the createOrganization field.
+func (r *mutationResolver) CreateOrganization(ctx context.Context, input model.NewOrganization) (*model.Organization, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input CreateOrganization")
+	}
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	organization, err := r.Srv.CreateOrganization(ctx, input, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create organization")
+	}
+	return organization, nil
+}
+
+// CreateOrganizationInvite is the resolver for the createOrganizationInvite field.
+func (r *mutationResolver) CreateOrganizationInvite(ctx context.Context, input model.NewOrganizationInvite) (*model.OrganizationInvite, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input CreateOrganizationInvite")
+	}
+
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	invite, err := r.Srv.CreateOrganizationInvite(ctx, input, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create organization invite")
+	}
+	return invite, nil
+}
+
+// AcceptOrganizationInvite is the resolver for the acceptOrganizationInvite field.
+func (r *mutationResolver) AcceptOrganizationInvite(ctx context.Context, token string) (*model.Organization, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	organization, err := r.Srv.AcceptOrganizationInvite(ctx, token, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to accept organization invite")
+	}
+	return organization, nil
+}
+
+// RevokeOrganizationInvite is the resolver for the revokeOrganizationInvite field.
+func (r *mutationResolver) RevokeOrganizationInvite(ctx context.Context, id int64) (*bool, error) {
+	invite, err := r.Srv.GetOrganizationInviteByID(ctx, id)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get invite")
+	}
+	if err := requireOrganizationRole(ctx, r.Srv, invite.OrganizationID, model.OrganizationRoleTeacher); err != nil {
+		return nil, err
+	}
+	return r.Srv.RevokeOrganizationInvite(ctx, id)
+}
+
+// ChangeOrganizationRole is the resolver for the changeOrganizationRole field.
+func (r *mutationResolver) ChangeOrganizationRole(ctx context.Context, userID int64, organizationID int64, role model.OrganizationRole) (*model.Organization, error) {
+	return r.Srv.ChangeOrganizationRole(ctx, userID, organizationID, role)
+}
+
+// RemoveUserFromOrganization is the resolver for the removeUserFromOrganization field.
+func (r *mutationResolver) RemoveUserFromOrganization(ctx context.Context, userID int64, organizationID int64) (*model.Organization, error) {
+	return r.Srv.RemoveUserFromOrganization(ctx, userID, organizationID)
+}
+
+// CreateClassroom is the resolver for the createClassroom field.
+func (r *mutationResolver) CreateClassroom(ctx context.Context, input model.NewClassroom) (*model.Classroom, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input CreateClassroom")
+	}
+
+	classroom, err := r.Srv.CreateClassroom(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create classroom")
+	}
+	return classroom, nil
+}
+
+// AddStudentToClassroom is the resolver for the addStudentToClassroom field.
+func (r *mutationResolver) AddStudentToClassroom(ctx context.Context, classroomID int64, userID int64) (*model.Classroom, error) {
+	if err := requireClassroomTeacher(ctx, r.Srv, classroomID); err != nil {
+		return nil, err
+	}
+	return r.Srv.AddStudentToClassroom(ctx, classroomID, userID)
+}
+
+// RemoveStudentFromClassroom is the resolver for the removeStudentFromClassroom field.
+func (r *mutationResolver) RemoveStudentFromClassroom(ctx context.Context, classroomID int64, userID int64) (*model.Classroom, error) {
+	if err := requireClassroomTeacher(ctx, r.Srv, classroomID); err != nil {
+		return nil, err
+	}
+	return r.Srv.RemoveStudentFromClassroom(ctx, classroomID, userID)
+}
+
+// AssignCardGroupToClassroom is the resolver for the assignCardGroupToClassroom field.
+func (r *mutationResolver) AssignCardGroupToClassroom(ctx context.Context, classroomID int64, cardGroupID int64) (*model.Classroom, error) {
+	if err := requireClassroomTeacher(ctx, r.Srv, classroomID); err != nil {
+		return nil, err
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, cardGroupID, model.CardGroupRoleOwner); err != nil {
+		return nil, err
+	}
+	return r.Srv.AssignCardGroupToClassroom(ctx, classroomID, cardGroupID)
+}
+
+// UnassignCardGroupFromClassroom is the resolver for the unassignCardGroupFromClassroom field.
+func (r *mutationResolver) UnassignCardGroupFromClassroom(ctx context.Context, classroomID int64, cardGroupID int64) (*model.Classroom, error) {
+	if err := requireClassroomTeacher(ctx, r.Srv, classroomID); err != nil {
+		return nil, err
+	}
+	return r.Srv.UnassignCardGroupFromClassroom(ctx, classroomID, cardGroupID)
+}
+
+// GrantPermissionToRole is the resolver for the grantPermissionToRole field.
+func (r *mutationResolver) GrantPermissionToRole(ctx context.Context, roleID int64, permissionID int64) (*model.Role, error) {
+	role, err := r.Srv.GrantPermissionToRole(ctx, roleID, permissionID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to grant permission to role")
+	}
+	return role, nil
+}
+
+// RevokePermissionFromRole is the resolver for the revokePermissionFromRole field.
+func (r *mutationResolver) RevokePermissionFromRole(ctx context.Context, roleID int64, permissionID int64) (*model.Role, error) {
+	role, err := r.Srv.RevokePermissionFromRole(ctx, roleID, permissionID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to revoke permission from role")
+	}
+	return role, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+	return r.Srv.GetUserByID(ctx, userID)
 }
 
 // Card is the resolver for the card field.
@@ -315,7 +796,9 @@ func (r *queryResolver) Card(ctx context.Context, id int64) (*model.Card, error)
 	if err != nil {
 		return nil, goerr.Wrap(err, "invalid input Card")
 	}
-
+	if err := requireCardGroupRole(ctx, r.Srv, card.CardGroupID, model.CardGroupRoleViewer); err != nil {
+		return nil, err
+	}
 	return card, nil
 }
 
@@ -352,6 +835,10 @@ func (r *queryResolver) User(ctx context.Context, id int64) (*model.User, error)
 
 // SwipeRecord is the resolver for the swipeRecord field.
 func (r *queryResolver) SwipeRecord(ctx context.Context, id int64) (*model.SwipeRecord, error) {
+	if err := requireSwipeRecordOwner(ctx, r.Srv, id); err != nil {
+		return nil, err
+	}
+
 	// Use DataLoader to fetch the SwipeRecord by ID
 	thunk := r.Loaders.SwipeRecordLoader.Load(ctx, id)
 	swipeRecord, err := thunk()
@@ -373,7 +860,11 @@ func (r *queryResolver) UserRole(ctx context.Context, userID int64) (*model.Role
 }
 
 // CardGroupsByUser is the resolver for the cardGroupsByUser field.
-func (r *queryResolver) CardGroupsByUser(ctx context.Context, userID int64, first *int, after *int64, last *int, before *int64) (*model.CardGroupConnection, error) {
+func (r *queryResolver) CardGroupsByUser(ctx context.Context, first *int, after *int64, last *int, before *int64) (*model.CardGroupConnection, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
 	return r.Srv.PaginatedCardGroupsByUser(ctx, userID, first, after, last, before)
 }
 
@@ -383,10 +874,193 @@ func (r *queryResolver) UsersByRole(ctx context.Context, roleID int64, first *in
 }
 
 // SwipeRecords is the resolver for the swipeRecords field.
-func (r *queryResolver) SwipeRecords(ctx context.Context, userID int64, first *int, after *int64, last *int, before *int64) (*model.SwipeRecordConnection, error) {
+func (r *queryResolver) SwipeRecords(ctx context.Context, first *int, after *int64, last *int, before *int64) (*model.SwipeRecordConnection, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
 	return r.Srv.PaginatedSwipeRecordsByUser(ctx, userID, first, after, last, before)
 }
 
+// CheckAnswer is the resolver for the checkAnswer field.
+func (r *queryResolver) CheckAnswer(ctx context.Context, cardID int64, answer string) (bool, error) {
+	card, err := r.Srv.GetCardByID(ctx, cardID)
+	if err != nil {
+		return false, goerr.Wrap(err, "failed to get card")
+	}
+	if err := requireCardGroupRole(ctx, r.Srv, card.CardGroupID, model.CardGroupRoleViewer); err != nil {
+		return false, err
+	}
+	return r.Srv.CheckAnswer(ctx, cardID, answer)
+}
+
+// PreviewDictionary is the resolver for the previewDictionary field.
+func (r *queryResolver) PreviewDictionary(ctx context.Context, input model.UpsertDictionary) (*model.DictionaryPreview, error) {
+	preview, err := r.U.PreviewCards(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to preview cards")
+	}
+	return preview, nil
+}
+
+// FindDuplicateCards is the resolver for the findDuplicateCards field.
+func (r *queryResolver) FindDuplicateCards(ctx context.Context, cardGroupID int64, threshold float64) ([]*model.DuplicateCardCluster, error) {
+	return r.Srv.FindDuplicateCards(ctx, cardGroupID, threshold)
+}
+
+// SubtitleCandidates is the resolver for the subtitleCandidates field.
+func (r *queryResolver) SubtitleCandidates(ctx context.Context, input model.SubtitleCandidates) ([]*model.SubtitleCandidate, error) {
+	candidates, err := r.U.SubtitleCandidates(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to make subtitle candidates")
+	}
+	return candidates, nil
+}
+
+// LookupWord is the resolver for the lookupWord field.
+func (r *queryResolver) LookupWord(ctx context.Context, term string) ([]*model.DictionaryEntry, error) {
+	entries, err := r.U.LookupWord(ctx, term)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to look up word")
+	}
+	return entries, nil
+}
+
+// ProseCandidates is the resolver for the proseCandidates field.
+func (r *queryResolver) ProseCandidates(ctx context.Context, input model.ProseCandidates) ([]*model.ProseCandidate, error) {
+	if err := r.VW.ValidateStruct(input); err != nil {
+		return nil, goerr.Wrap(err, "invalid input ProseCandidates")
+	}
+
+	candidates, err := r.U.ProseCandidates(ctx, input)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to make prose candidates")
+	}
+	return candidates, nil
+}
+
+// Permissions is the resolver for the permissions field.
+func (r *queryResolver) Permissions(ctx context.Context) ([]*model.Permission, error) {
+	permissions, err := r.Srv.Permissions(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get permissions")
+	}
+	return permissions, nil
+}
+
+// PublicCardGroups is the resolver for the publicCardGroups field.
+func (r *queryResolver) PublicCardGroups(ctx context.Context, search *string, language *string, first *int, after *int64) (*model.CardGroupConnection, error) {
+	cardGroups, err := r.Srv.PublicCardGroups(ctx, search, language, first, after)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get public card groups")
+	}
+	return cardGroups, nil
+}
+
+// CardGroupVersions is the resolver for the cardGroupVersions field.
+func (r *queryResolver) CardGroupVersions(ctx context.Context, cardGroupID int64) ([]*model.CardGroupVersion, error) {
+	versions, err := r.Srv.CardGroupVersions(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card group versions")
+	}
+	return versions, nil
+}
+
+// CardGroupUpdate is the resolver for the cardGroupUpdate field.
+func (r *queryResolver) CardGroupUpdate(ctx context.Context, cardGroupID int64) (*model.CardGroupUpdate, error) {
+	update, err := r.Srv.CardGroupUpdate(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card group update")
+	}
+	return update, nil
+}
+
+// CardSuggestions is the resolver for the cardSuggestions field.
+func (r *queryResolver) CardSuggestions(ctx context.Context, cardGroupID int64) ([]*model.CardSuggestion, error) {
+	suggestions, err := r.Srv.PendingCardSuggestions(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card suggestions")
+	}
+	return suggestions, nil
+}
+
+// CardGroupInvites is the resolver for the cardGroupInvites field.
+func (r *queryResolver) CardGroupInvites(ctx context.Context, cardGroupID int64) ([]*model.CardGroupInvite, error) {
+	invites, err := r.Srv.CardGroupInvites(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card group invites")
+	}
+	return invites, nil
+}
+
+// Organizations is the resolver for the organizations field.
+func (r *queryResolver) Organizations(ctx context.Context) ([]*model.Organization, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	organizations, err := r.Srv.OrganizationsByUser(ctx, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get organizations")
+	}
+	return organizations, nil
+}
+
+// OrganizationInvites is the resolver for the organizationInvites field.
+func (r *queryResolver) OrganizationInvites(ctx context.Context, organizationID int64) ([]*model.OrganizationInvite, error) {
+	invites, err := r.Srv.OrganizationInvites(ctx, organizationID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get organization invites")
+	}
+	return invites, nil
+}
+
+// Classrooms is the resolver for the classrooms field.
+func (r *queryResolver) Classrooms(ctx context.Context, organizationID int64) ([]*model.Classroom, error) {
+	classrooms, err := r.Srv.Classrooms(ctx, organizationID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get classrooms")
+	}
+	return classrooms, nil
+}
+
+// ClassroomProgress is the resolver for the classroomProgress field.
+func (r *queryResolver) ClassroomProgress(ctx context.Context, classroomID int64) ([]*model.StudentProgress, error) {
+	if err := requireClassroomTeacher(ctx, r.Srv, classroomID); err != nil {
+		return nil, err
+	}
+
+	progress, err := r.Srv.ClassroomProgress(ctx, classroomID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get classroom progress")
+	}
+	return progress, nil
+}
+
+// MyPermissions is the resolver for the myPermissions field.
+func (r *queryResolver) MyPermissions(ctx context.Context) ([]string, error) {
+	userID, err := auth.ViewerID(ctx)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get viewer")
+	}
+
+	permissions, err := r.Srv.UserPermissions(ctx, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get permissions of viewer")
+	}
+	return permissions, nil
+}
+
+// UserPermissions is the resolver for the userPermissions field.
+func (r *queryResolver) UserPermissions(ctx context.Context, userID int64) ([]string, error) {
+	permissions, err := r.Srv.UserPermissions(ctx, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get permissions of user")
+	}
+	return permissions, nil
+}
+
 // Users is the resolver for the users field in Role.
 func (r *roleResolver) Users(ctx context.Context, obj *model.Role, first *int, after *int64, last *int, before *int64) (*model.UserConnection, error) {
 	var userIDs []int64
@@ -448,6 +1122,15 @@ func (r *roleResolver) Users(ctx context.Context, obj *model.Role, first *int, a
 	}, nil
 }
 
+// Permissions is the resolver for the permissions field.
+func (r *roleResolver) Permissions(ctx context.Context, obj *model.Role) ([]*model.Permission, error) {
+	permissions, err := r.Srv.GetPermissionsByRole(ctx, obj.ID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get permissions of role")
+	}
+	return permissions, nil
+}
+
 // CardGroups is the resolver for the cardGroups field in User.
 func (r *userResolver) CardGroups(ctx context.Context, obj *model.User, first *int, after *int64, last *int, before *int64) (*model.CardGroupConnection, error) {
 	var cardGroupIDs []int64
@@ -30,6 +30,7 @@ import (
 
 	"backend/graph/model"
 	"backend/graph/services"
+	"backend/pkg/auth"
 	"backend/pkg/config"
 	"backend/pkg/middlewares"
 	"backend/pkg/validator"
@@ -68,6 +69,8 @@ func NewRouter(db *gorm.DB) *echo.Echo {
 	e.Use(middleware.Recover())
 	e.Use(middlewares.DatabaseCtxMiddleware(db))
 	e.Use(middlewares.TransactionMiddleware())
+	e.Use(middlewares.JWTMiddleware(auth.NewVerifier(auth.VerifierConfig{Secret: config.Cfg.JWTSecret}), true))
+	e.Use(middlewares.ViewerCtxMiddleware(auth.SubjectViewer))
 
 	sv = services.New(db)
 	usecase := usecases.New(sv)
@@ -80,7 +83,10 @@ func NewRouter(db *gorm.DB) *echo.Echo {
 		Loaders: graph.NewLoaders(sv),
 	}
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Directives: graph.NewDirectives(sv),
+	}))
 
 	// GraphQL Complexity configuration
 	srv.Use(extension.FixedComplexityLimit(config.Cfg.GQLComplexity))
@@ -115,6 +121,54 @@ func NewRouter(db *gorm.DB) *echo.Echo {
 func testGraphQLQuery(t *testing.T, e *echo.Echo, jsonInput []byte, expected string, ignoreFields ...string) {
 	req := httptest.NewRequest(http.MethodPost, "/query", bytes.NewBuffer(jsonInput))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assertGraphQLResponse(t, e, req, expected, ignoreFields...)
+}
+
+// testGraphQLQueryAs performs the query logged in as the user with the JWT cookie
+func testGraphQLQueryAs(t *testing.T, e *echo.Echo, userID int64, jsonInput []byte, expected string, ignoreFields ...string) {
+	token, err := auth.NewToken(userID, config.Cfg.JWTSecret, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/query", bytes.NewBuffer(jsonInput))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.AddCookie(&http.Cookie{Name: "jwt", Value: token})
+	assertGraphQLResponse(t, e, req, expected, ignoreFields...)
+}
+
+// createAdmin creates a user with the admin role granted every permission, who passes @hasRole(role: ADMIN),
+// @hasPermission and @cardGroupMember
+func createAdmin(t *testing.T) int64 {
+	now := time.Now().UTC()
+
+	role := repository.Role{Name: "admin", Created: now, Updated: now}
+	if err := db.Where("name = ?", role.Name).FirstOrCreate(&role).Error; err != nil {
+		t.Fatalf("failed to create admin role: %v", err)
+	}
+
+	user := repository.User{
+		Name:     "Admin User",
+		Email:    testutils.GetRandomEmail(8),
+		GoogleID: testutils.GenerateUUIDv7(),
+		Created:  now,
+		Updated:  now,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create admin: %v", err)
+	}
+	if err := db.Model(&user).Association("Roles").Append(&role); err != nil {
+		t.Fatalf("failed to assign admin role: %v", err)
+	}
+	// Roles are cleaned up between tests while the seeded permissions stay
+	if err := db.Exec(`INSERT INTO role_permissions (role_id, permission_id)
+		SELECT ?, id FROM permissions ON CONFLICT DO NOTHING`, role.ID).Error; err != nil {
+		t.Fatalf("failed to grant permissions to admin role: %v", err)
+	}
+	return user.ID
+}
+
+func assertGraphQLResponse(t *testing.T, e *echo.Echo, req *http.Request, expected string, ignoreFields ...string) {
 	rec := httptest.NewRecorder()
 
 	e.ServeHTTP(rec, req)
@@ -171,7 +225,7 @@ func TestGraphQLQueries(t *testing.T) {
 	testutils.RunServersTest(t, db, func(t *testing.T) {
 		t.Run("Card Query", func(t *testing.T) {
 			t.Parallel()
-			createdCard, _, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+			createdCard, _, user, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -206,7 +260,76 @@ func TestGraphQLQueries(t *testing.T) {
 			})
 			expected := string(expectedData)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.card.created", "data.card.updated", "data.card.review_date")
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected, "data.card.created", "data.card.updated", "data.card.review_date")
+		})
+
+		t.Run("Error_Card Query by Non-Member", func(t *testing.T) {
+			t.Parallel()
+			createdCard, cardGroup, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, otherUser, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($id: ID!) {
+                    card(id: $id) {
+                        id
+                        front
+                        back
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"id": createdCard.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "not a member of card group : %d: forbidden",
+                    "path": ["card"]
+                }],
+                "data": {
+                    "card": null
+                }
+            }`, cardGroup.ID)
+
+			testGraphQLQueryAs(t, e, otherUser.ID, jsonInput, expected)
+		})
+
+		t.Run("Error_CheckAnswer Query by Non-Member", func(t *testing.T) {
+			t.Parallel()
+			createdCard, cardGroup, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, otherUser, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($cardID: ID!, $answer: String!) {
+                    checkAnswer(cardID: $cardID, answer: $answer)
+                }`,
+				"variables": map[string]interface{}{
+					"cardID": createdCard.ID,
+					"answer": createdCard.Back,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "not a member of card group : %d: forbidden",
+                    "path": ["checkAnswer"]
+                }],
+                "data": null
+            }`, cardGroup.ID)
+
+			testGraphQLQueryAs(t, e, otherUser.ID, jsonInput, expected)
 		})
 
 		t.Run("CardGroup Query", func(t *testing.T) {
@@ -243,7 +366,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, cardGroup.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.cardGroup.created", "data.cardGroup.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.cardGroup.created", "data.cardGroup.updated")
 		})
 
 		t.Run("User Query", func(t *testing.T) {
@@ -308,7 +431,44 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, user.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.user.created", "data.user.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.user.created", "data.user.updated")
+		})
+
+		t.Run("User Query by Another User", func(t *testing.T) {
+			t.Parallel()
+
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, otherUser, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($id: ID!) {
+                    user(id: $id) {
+                        id
+                        email
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"id": user.ID,
+				},
+			})
+
+			expected := `{
+                "errors": [{
+                    "message": "permission user:admin required: forbidden",
+                    "path": ["user"]
+                }],
+                "data": {
+                    "user": null
+                }
+            }`
+
+			testGraphQLQueryAs(t, e, otherUser.ID, jsonInput, expected)
 		})
 
 		t.Run("Role Query", func(t *testing.T) {
@@ -412,7 +572,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, card.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.cardsByCardGroup.nodes.created", "data.cardsByCardGroup.nodes.updated", "data.cardsByCardGroup.nodes.review_date")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.cardsByCardGroup.nodes.created", "data.cardsByCardGroup.nodes.updated", "data.cardsByCardGroup.nodes.review_date")
 		})
 
 		t.Run("UserRole Query", func(t *testing.T) {
@@ -475,7 +635,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, role.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("CardGroupsByUser Query", func(t *testing.T) {
@@ -527,8 +687,8 @@ func TestGraphQLQueries(t *testing.T) {
 			db.Model(&user).Association("CardGroups").Append(&cardGroup)
 
 			jsonInput, _ := json.Marshal(map[string]interface{}{
-				"query": `query ($userID: ID!, $first: Int) {
-                    cardGroupsByUser(userID: $userID, first: $first) {
+				"query": `query ($first: Int) {
+                    cardGroupsByUser(first: $first) {
                         nodes {
                             id
                             name
@@ -538,8 +698,7 @@ func TestGraphQLQueries(t *testing.T) {
                     }
                 }`,
 				"variables": map[string]interface{}{
-					"userID": user.ID,
-					"first":  nil,
+					"first": nil,
 				},
 			})
 
@@ -554,7 +713,84 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, cardGroup.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.cardGroupsByUser.nodes.created", "data.cardGroupsByUser.nodes.updated")
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected, "data.cardGroupsByUser.nodes.created", "data.cardGroupsByUser.nodes.updated")
+		})
+
+		t.Run("Me Query", func(t *testing.T) {
+			t.Parallel()
+
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query {
+                    me {
+                        id
+                        email
+                    }
+                }`,
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "me": {
+                        "id": %d,
+                        "email": "%s"
+                    }
+                }
+            }`, user.ID, user.Email)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+		})
+
+		t.Run("My Permissions Query", func(t *testing.T) {
+			t.Parallel()
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query {
+                    myPermissions
+                }`,
+			})
+
+			expected := `{
+                "data": {
+                    "myPermissions": [
+                        "cardgroup:admin",
+                        "cardgroup:write",
+                        "deck:publish",
+                        "organization:admin",
+                        "organization:write",
+                        "role:admin",
+                        "user:admin"
+                    ]
+                }
+            }`
+
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
+		})
+
+		t.Run("Me Query without Login", func(t *testing.T) {
+			t.Parallel()
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query {
+                    me {
+                        id
+                    }
+                }`,
+			})
+
+			expected := `{
+                "errors": [{
+                    "message": "failed to get viewer: no viewer in context: unauthenticated",
+                    "path": ["me"]
+                }],
+                "data": null
+            }`
+
+			testGraphQLQuery(t, e, jsonInput, expected)
 		})
 
 		t.Run("UsersByRole Query", func(t *testing.T) {
@@ -624,7 +860,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, user.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.usersByRole.nodes.created", "data.usersByRole.nodes.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.usersByRole.nodes.created", "data.usersByRole.nodes.updated")
 		})
 
 		t.Run("CreateCard Mutation", func(t *testing.T) {
@@ -677,7 +913,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.createCard.id", "data.createCard.created", "data.createCard.updated", "data.createCard.review_date")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.createCard.id", "data.createCard.created", "data.createCard.updated", "data.createCard.review_date")
 		})
 
 		t.Run("UpdateCard Mutation", func(t *testing.T) {
@@ -734,7 +970,59 @@ func TestGraphQLQueries(t *testing.T) {
         }
     }`, card.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.updateCard.created", "data.updateCard.updated", "data.updateCard.review_date", "data.updateCard.interval_days")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.updateCard.created", "data.updateCard.updated", "data.updateCard.review_date", "data.updateCard.interval_days")
+		})
+
+		t.Run("UpdateCard of Another Card Group", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			cardService := services.NewCardService(db, 20, 1000, 0.8)
+
+			ctx := context.Background()
+			ownGroup, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			otherGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			card, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "Front",
+				Back:        "Back",
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: otherGroup.ID,
+			})
+			if err != nil {
+				t.Fatalf("failed to create card: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!, $input: NewCard!) {
+                    updateCard(id: $id, input: $input) {
+                        id
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"id": card.ID,
+					"input": model.NewCard{
+						Front:       "Updated Front",
+						Back:        "Updated Back",
+						ReviewDate:  time.Now().UTC(),
+						CardgroupID: ownGroup.ID,
+					},
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "not a member of card group : %d: forbidden",
+                    "path": ["updateCard"]
+                }],
+                "data": {
+                    "updateCard": null
+                }
+            }`, otherGroup.ID)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
 		})
 
 		t.Run("DeleteCard Mutation", func(t *testing.T) {
@@ -774,7 +1062,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("CreateCardGroup Mutation", func(t *testing.T) {
@@ -784,7 +1072,6 @@ func TestGraphQLQueries(t *testing.T) {
 			input := model.NewCardGroup{
 				Name:    "New Card Group",
 				CardIds: nil,
-				UserIds: []int64{1, 2},
 				Created: now,
 				Updated: now,
 			}
@@ -812,7 +1099,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.createCardGroup.id", "data.createCardGroup.created", "data.createCardGroup.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.createCardGroup.id", "data.createCardGroup.created", "data.createCardGroup.updated")
 		})
 
 		t.Run("UpdateCardGroup Mutation", func(t *testing.T) {
@@ -872,7 +1159,6 @@ func TestGraphQLQueries(t *testing.T) {
 			// Input data for updating
 			input := model.NewCardGroup{
 				Name:    "Updated Group",
-				UserIds: []int64{user.ID},
 				Created: now,
 				Updated: now,
 			}
@@ -902,7 +1188,7 @@ func TestGraphQLQueries(t *testing.T) {
         }
     }`, cardGroup.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.updateCardGroup.created", "data.updateCardGroup.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.updateCardGroup.created", "data.updateCardGroup.updated")
 		})
 
 		t.Run("DeleteCardGroup Mutation", func(t *testing.T) {
@@ -931,7 +1217,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("CreateUser Mutation", func(t *testing.T) {
@@ -988,7 +1274,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.createUser.id", "data.createUser.created", "data.createUser.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.createUser.id", "data.createUser.created", "data.createUser.updated")
 		})
 
 		t.Run("UpdateUser Mutation", func(t *testing.T) {
@@ -1065,7 +1351,7 @@ func TestGraphQLQueries(t *testing.T) {
         }
     }`, user.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.updateUser.created", "data.updateUser.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.updateUser.created", "data.updateUser.updated")
 		})
 
 		t.Run("DeleteUser Mutation", func(t *testing.T) {
@@ -1096,7 +1382,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("CreateRole Mutation", func(t *testing.T) {
@@ -1132,7 +1418,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.createRole.id", "data.createRole.created", "data.createRole.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.createRole.id", "data.createRole.created", "data.createRole.updated")
 		})
 
 		t.Run("UpdateRole Mutation", func(t *testing.T) {
@@ -1176,7 +1462,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, role.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.updateRole.created", "data.updateRole.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.updateRole.created", "data.updateRole.updated")
 		})
 
 		t.Run("DeleteRole Mutation", func(t *testing.T) {
@@ -1217,7 +1503,7 @@ func TestGraphQLQueries(t *testing.T) {
         }
     }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("AddUserToCardGroup Mutation", func(t *testing.T) {
@@ -1291,7 +1577,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, cardGroup.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.addUserToCardGroup.created", "data.addUserToCardGroup.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.addUserToCardGroup.created", "data.addUserToCardGroup.updated")
 		})
 
 		t.Run("RemoveUserFromCardGroup Mutation", func(t *testing.T) {
@@ -1366,7 +1652,7 @@ func TestGraphQLQueries(t *testing.T) {
                 }
             }`, cardGroup.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.removeUserFromCardGroup.created", "data.removeUserFromCardGroup.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.removeUserFromCardGroup.created", "data.removeUserFromCardGroup.updated")
 		})
 
 		t.Run("AssignRoleToUser Mutation", func(t *testing.T) {
@@ -1433,7 +1719,7 @@ func TestGraphQLQueries(t *testing.T) {
         }
     }`, user.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.assignRoleToUser.created", "data.assignRoleToUser.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.assignRoleToUser.created", "data.assignRoleToUser.updated")
 		})
 
 		t.Run("RemoveRoleFromUser Mutation", func(t *testing.T) {
@@ -1500,7 +1786,7 @@ func TestGraphQLQueries(t *testing.T) {
         }
     }`, user.ID)
 
-			testGraphQLQuery(t, e, jsonInput, expected, "data.removeRoleFromUser.created", "data.removeRoleFromUser.updated")
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.removeRoleFromUser.created", "data.removeRoleFromUser.updated")
 		})
 	})
 }
@@ -1597,7 +1883,7 @@ func TestGraphQLErrors(t *testing.T) {
             "data": null
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("UpdateCard with Invalid ID", func(t *testing.T) {
@@ -1638,7 +1924,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("DeleteCard with Invalid ID", func(t *testing.T) {
@@ -1676,9 +1962,8 @@ func TestGraphQLErrors(t *testing.T) {
 			now := time.Now().UTC()
 			// Missing `name` field
 			input := map[string]interface{}{
-				"user_ids": []int64{1, 2},
-				"created":  now,
-				"updated":  now,
+				"created": now,
+				"updated": now,
 			}
 
 			jsonInput, _ := json.Marshal(map[string]interface{}{
@@ -1718,7 +2003,6 @@ func TestGraphQLErrors(t *testing.T) {
 			now := time.Now().UTC()
 			input := model.NewCardGroup{
 				Name:    "Updated Group",
-				UserIds: []int64{1, 2},
 				Created: now,
 				Updated: now,
 			}
@@ -1750,7 +2034,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("DeleteCardGroup with Invalid ID", func(t *testing.T) {
@@ -1779,7 +2063,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("CreateUser with Missing Name", func(t *testing.T) {
@@ -1820,7 +2104,7 @@ func TestGraphQLErrors(t *testing.T) {
             "data": null
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("UpdateUser with Invalid ID", func(t *testing.T) {
@@ -1864,7 +2148,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("DeleteUser with Invalid ID", func(t *testing.T) {
@@ -1893,7 +2177,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("CreateRole with Missing Name", func(t *testing.T) {
@@ -1933,7 +2217,7 @@ func TestGraphQLErrors(t *testing.T) {
             "data": null
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("UpdateRole with Invalid ID", func(t *testing.T) {
@@ -1974,7 +2258,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("DeleteRole with Invalid ID", func(t *testing.T) {
@@ -2003,7 +2287,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("AddUserToCardGroup with Invalid IDs", func(t *testing.T) {
@@ -2039,7 +2323,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("RemoveUserFromCardGroup with Invalid IDs", func(t *testing.T) {
@@ -2075,7 +2359,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("AssignRoleToUser with Invalid IDs", func(t *testing.T) {
@@ -2111,7 +2395,7 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
 		t.Run("RemoveRoleFromUser with Invalid IDs", func(t *testing.T) {
@@ -2147,10 +2431,10 @@ func TestGraphQLErrors(t *testing.T) {
             }
         }`
 
-			testGraphQLQuery(t, e, jsonInput, expected)
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
 		})
 
-		t.Run("Upsert Dictionary Smoke", func(t *testing.T) {
+		t.Run("DeleteUser without Admin Role", func(t *testing.T) {
 			t.Helper()
 			t.Parallel()
 
@@ -2159,83 +2443,1060 @@ func TestGraphQLErrors(t *testing.T) {
 			roleService := services.NewRoleService(db, 20)
 
 			ctx := context.Background()
-			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
-
-			input := model.UpsertDictionary{
-				Dictionary: base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`
-New Front 1 裏面１
-New Front 2 裏面２
-`))),
-				CardgroupID: createdGroup.ID,
-			}
+			_, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
 
-			// Serialize the input to JSON
 			jsonInput, _ := json.Marshal(map[string]interface{}{
-				"query": `mutation ($input: UpsertDictionary!) {
-	upsertDictionary(input: $input) {
-		nodes {
-			id
-			front
-			back
-			interval_days
-			cardGroupID
-		}
-	}
-}`,
+				"query": `mutation ($id: ID!) {
+                    deleteUser(id: $id)
+                }`,
 				"variables": map[string]interface{}{
-					"input": input,
+					"id": user.ID,
 				},
 			})
 
-			// Expected response structure
-			expected := fmt.Sprintf(`{
-    "data": {
-        "upsertDictionary": {
-            "nodes": [{
-                "id": "1",
-                "front": "New Front 1",
-                "back": "裏面１",
-                "interval_days": 1,
-                "cardGroupID": %d
-            }, {
-                "id": "2",
-                "front": "New Front 2",
-                "back": "裏面２",
-                "interval_days": 1,
-                "cardGroupID": %d
-            }]
-        }
-    }
-}`, createdGroup.ID, createdGroup.ID)
+			expected := `{
+                "errors": [{
+                    "message": "permission user:admin required: forbidden",
+                    "path": ["deleteUser"]
+                }],
+                "data": {
+                    "deleteUser": null
+                }
+            }`
 
-			// Perform the GraphQL query and check the results
-			testGraphQLQuery(t, e, jsonInput, expected, "data.upsertDictionary.nodes.id")
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
 		})
 
-		t.Run("Upsert Dictionary with Invalid Format", func(t *testing.T) {
+		t.Run("DeleteCardGroup by Non-member", func(t *testing.T) {
 			t.Helper()
 			t.Parallel()
 
-			// Generate dummy data
 			userService := services.NewUserService(db, 20)
 			cardGroupService := services.NewCardGroupService(db, 20)
 			roleService := services.NewRoleService(db, 20)
 
 			ctx := context.Background()
 			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, otherUser, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
 
-			// Create invalid dictionary data
-			invalidDictionary := base64.StdEncoding.EncodeToString([]byte(`Invalid Dictionary Data Without Correct Format`))
-
-			input := model.UpsertDictionary{
-				Dictionary:  invalidDictionary,
-				CardgroupID: createdGroup.ID,
-			}
-
-			// Create the GraphQL query
 			jsonInput, _ := json.Marshal(map[string]interface{}{
-				"query": `mutation ($input: UpsertDictionary!) {
-	upsertDictionary(input: $input) {
+				"query": `mutation ($id: ID!) {
+                    deleteCardGroup(id: $id)
+                }`,
+				"variables": map[string]interface{}{
+					"id": createdGroup.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "not a member of card group : %d: forbidden",
+                    "path": ["deleteCardGroup"]
+                }],
+                "data": {
+                    "deleteCardGroup": null
+                }
+            }`, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, otherUser.ID, jsonInput, expected)
+		})
+
+		t.Run("DeleteCardGroup by Editor", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, editor, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if _, err := cardGroupService.AddUserToCardGroup(ctx, editor.ID, createdGroup.ID, model.CardGroupRoleEditor); err != nil {
+				t.Fatalf("failed to add editor to card group: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    deleteCardGroup(id: $id)
+                }`,
+				"variables": map[string]interface{}{
+					"id": createdGroup.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "OWNER role required in card group : %d: forbidden",
+                    "path": ["deleteCardGroup"]
+                }],
+                "data": {
+                    "deleteCardGroup": null
+                }
+            }`, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, editor.ID, jsonInput, expected)
+		})
+
+		t.Run("DeleteCard by Viewer", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, viewer, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if _, err := cardGroupService.AddUserToCardGroup(ctx, viewer.ID, createdGroup.ID, model.CardGroupRoleViewer); err != nil {
+				t.Fatalf("failed to add viewer to card group: %v", err)
+			}
+
+			now := time.Now().UTC()
+			card := repository.Card{
+				Front:        "Viewer Card Front",
+				Back:         "Viewer Card Back",
+				ReviewDate:   now,
+				IntervalDays: 1,
+				CardGroupID:  createdGroup.ID,
+				Created:      now,
+				Updated:      now,
+			}
+			db.Create(&card)
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    deleteCard(id: $id)
+                }`,
+				"variables": map[string]interface{}{
+					"id": card.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "EDITOR role required in card group : %d: forbidden",
+                    "path": ["deleteCard"]
+                }],
+                "data": {
+                    "deleteCard": null
+                }
+            }`, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, viewer.ID, jsonInput, expected)
+		})
+
+		t.Run("DeleteCardGroup by Member", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    deleteCardGroup(id: $id)
+                }`,
+				"variables": map[string]interface{}{
+					"id": createdGroup.ID,
+				},
+			})
+
+			expected := `{
+                "data": {
+                    "deleteCardGroup": true
+                }
+            }`
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+		})
+
+		t.Run("CreateCardGroupInvite Mutation", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, owner, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: NewCardGroupInvite!) {
+                    createCardGroupInvite(input: $input) {
+                        cardGroupID
+                        role
+                        maxUses
+                        uses
+                        revoked
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"input": map[string]interface{}{
+						"cardGroupID": createdGroup.ID,
+						"role":        "EDITOR",
+						"expiresAt":   time.Now().UTC().Add(time.Hour),
+						"maxUses":     3,
+					},
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "createCardGroupInvite": {
+                        "cardGroupID": %d,
+                        "role": "EDITOR",
+                        "maxUses": 3,
+                        "uses": 0,
+                        "revoked": false
+                    }
+                }
+            }`, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, owner.ID, jsonInput, expected)
+		})
+
+		t.Run("AcceptInvite Mutation", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			inviteService := services.NewCardGroupInviteService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, owner, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			invite, err := inviteService.CreateCardGroupInvite(ctx, model.NewCardGroupInvite{
+				CardGroupID: createdGroup.ID,
+				Role:        model.CardGroupRoleViewer,
+				ExpiresAt:   time.Now().UTC().Add(time.Hour),
+				MaxUses:     1,
+			}, owner.ID)
+			if err != nil {
+				t.Fatalf("failed to create invite: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($token: String!) {
+                    acceptInvite(token: $token) {
+                        id
+                        name
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"token": *invite.Token,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "acceptInvite": {
+                        "id": %d,
+                        "name": "%s"
+                    }
+                }
+            }`, createdGroup.ID, createdGroup.Name)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+
+			role, err := cardGroupService.GetCardGroupRole(ctx, createdGroup.ID, user.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, model.CardGroupRoleViewer, *role)
+		})
+
+		t.Run("RevokeInvite by Non-owner", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			inviteService := services.NewCardGroupInviteService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, owner, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, otherUser, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			invite, err := inviteService.CreateCardGroupInvite(ctx, model.NewCardGroupInvite{
+				CardGroupID: createdGroup.ID,
+				Role:        model.CardGroupRoleViewer,
+				ExpiresAt:   time.Now().UTC().Add(time.Hour),
+				MaxUses:     1,
+			}, owner.ID)
+			if err != nil {
+				t.Fatalf("failed to create invite: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    revokeInvite(id: $id)
+                }`,
+				"variables": map[string]interface{}{
+					"id": invite.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "not a member of card group : %d: forbidden",
+                    "path": ["revokeInvite"]
+                }],
+                "data": {
+                    "revokeInvite": null
+                }
+            }`, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, otherUser.ID, jsonInput, expected)
+		})
+
+		t.Run("PublishCardGroup without Permission", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, owner, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: PublishCardGroup!) {
+                    publishCardGroup(input: $input) {
+                        id
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"input": map[string]interface{}{
+						"cardGroupID":    createdGroup.ID,
+						"description":    "Deck",
+						"sourceLanguage": "ja",
+						"targetLanguage": "en",
+					},
+				},
+			})
+
+			expected := `{
+                "errors": [{
+                    "message": "permission deck:publish required: forbidden",
+                    "path": ["publishCardGroup"]
+                }],
+                "data": {
+                    "publishCardGroup": null
+                }
+            }`
+
+			testGraphQLQueryAs(t, e, owner.ID, jsonInput, expected)
+		})
+
+		t.Run("PublicCardGroups and SubscribeCardGroup", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			catalogService := services.NewCatalogService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if _, err := catalogService.PublishCardGroup(ctx, model.PublishCardGroup{
+				CardGroupID:    createdGroup.ID,
+				Description:    "Greetings",
+				SourceLanguage: "ja",
+				TargetLanguage: "en",
+			}); err != nil {
+				t.Fatalf("failed to publish card group: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($search: String) {
+                    publicCardGroups(search: $search, language: "ja") {
+                        nodes {
+                            id
+                            name
+                            description
+                            sourceLanguage
+                            targetLanguage
+                        }
+                        totalCount
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"search": createdGroup.Name,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "publicCardGroups": {
+                        "nodes": [{
+                            "id": %d,
+                            "name": "%s",
+                            "description": "Greetings",
+                            "sourceLanguage": "ja",
+                            "targetLanguage": "en"
+                        }],
+                        "totalCount": 1
+                    }
+                }
+            }`, createdGroup.ID, createdGroup.Name)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+
+			jsonInput, _ = json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    subscribeCardGroup(id: $id) {
+                        name
+                        subscribed
+                        sourceCardGroupID
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"id": createdGroup.ID,
+				},
+			})
+
+			expected = fmt.Sprintf(`{
+                "data": {
+                    "subscribeCardGroup": {
+                        "name": "%s",
+                        "subscribed": true,
+                        "sourceCardGroupID": %d
+                    }
+                }
+            }`, createdGroup.Name, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+		})
+
+		t.Run("CardGroupUpdate and ApplyCardGroupUpdate", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			cardService := services.NewCardService(db, 20, 1000, 0.8)
+			catalogService := services.NewCatalogService(db, 20)
+			versionService := services.NewCardGroupVersionService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if _, err := catalogService.PublishCardGroup(ctx, model.PublishCardGroup{
+				CardGroupID:    createdGroup.ID,
+				Description:    "Greetings",
+				SourceLanguage: "ja",
+				TargetLanguage: "en",
+			}); err != nil {
+				t.Fatalf("failed to publish card group: %v", err)
+			}
+			subscription, err := catalogService.SubscribeCardGroup(ctx, createdGroup.ID, user.ID)
+			if err != nil {
+				t.Fatalf("failed to subscribe card group: %v", err)
+			}
+			card, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "こんにちは",
+				Back:        "Hello",
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: createdGroup.ID,
+			})
+			if err != nil {
+				t.Fatalf("failed to create card: %v", err)
+			}
+			if _, err := versionService.PublishCardGroupVersion(ctx, createdGroup.ID, "Add a greeting"); err != nil {
+				t.Fatalf("failed to publish card group version: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($cardGroupID: ID!) {
+                    cardGroupUpdate(cardGroupID: $cardGroupID) {
+                        currentVersion
+                        latestVersion
+                        added {
+                            sourceCardID
+                            front
+                            back
+                        }
+                        changed {
+                            after {
+                                front
+                            }
+                        }
+                        removed {
+                            front
+                        }
+                        conflicts {
+                            front
+                        }
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"cardGroupID": subscription.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "cardGroupUpdate": {
+                        "currentVersion": 1,
+                        "latestVersion": 2,
+                        "added": [{
+                            "sourceCardID": %d,
+                            "front": "こんにちは",
+                            "back": "Hello"
+                        }],
+                        "changed": [],
+                        "removed": [],
+                        "conflicts": []
+                    }
+                }
+            }`, card.ID)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+
+			jsonInput, _ = json.Marshal(map[string]interface{}{
+				"query": `mutation ($cardGroupID: ID!) {
+                    applyCardGroupUpdate(cardGroupID: $cardGroupID) {
+                        sourceVersion
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"cardGroupID": subscription.ID,
+				},
+			})
+
+			expected = `{
+                "data": {
+                    "applyCardGroupUpdate": {
+                        "sourceVersion": 2
+                    }
+                }
+            }`
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+		})
+
+		t.Run("SuggestCardEdit and ApproveCardSuggestion by Viewer", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			cardService := services.NewCardService(db, 20, 1000, 0.8)
+			suggestionService := services.NewCardSuggestionService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, viewer, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			if _, err := cardGroupService.AddUserToCardGroup(ctx, viewer.ID, createdGroup.ID, model.CardGroupRoleViewer); err != nil {
+				t.Fatalf("failed to add user to card group: %v", err)
+			}
+			card, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "Bonjuor",
+				Back:        "Hello",
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: createdGroup.ID,
+			})
+			if err != nil {
+				t.Fatalf("failed to create card: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: NewCardSuggestion!) {
+                    suggestCardEdit(input: $input) {
+                        id
+                        cardID
+                        front
+                        back
+                        comment
+                        status
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"input": map[string]interface{}{
+						"cardID":  card.ID,
+						"front":   "Bonjour",
+						"back":    "Hello",
+						"comment": "Typo",
+					},
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "suggestCardEdit": {
+                        "cardID": %d,
+                        "front": "Bonjour",
+                        "back": "Hello",
+                        "comment": "Typo",
+                        "status": "PENDING"
+                    }
+                }
+            }`, card.ID)
+
+			testGraphQLQueryAs(t, e, viewer.ID, jsonInput, expected, "data.suggestCardEdit.id")
+
+			suggestions, err := suggestionService.PendingCardSuggestions(ctx, createdGroup.ID)
+			if err != nil || len(suggestions) != 1 {
+				t.Fatalf("failed to get card suggestions: %v", err)
+			}
+
+			jsonInput, _ = json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    approveCardSuggestion(id: $id) {
+                        front
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"id": suggestions[0].ID,
+				},
+			})
+
+			expected = fmt.Sprintf(`{
+                "errors": [{
+                    "message": "OWNER role required in card group : %d: forbidden",
+                    "path": ["approveCardSuggestion"]
+                }],
+                "data": {
+                    "approveCardSuggestion": null
+                }
+            }`, createdGroup.ID)
+
+			testGraphQLQueryAs(t, e, viewer.ID, jsonInput, expected)
+		})
+
+		t.Run("CreateOrganization Mutation", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: NewOrganization!) {
+                    createOrganization(input: $input) {
+                        name
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"input": map[string]interface{}{
+						"name": "Language School",
+					},
+				},
+			})
+
+			expected := `{
+                "data": {
+                    "createOrganization": {
+                        "name": "Language School"
+                    }
+                }
+            }`
+
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
+		})
+
+		t.Run("ChangeOrganizationRole of Non-Member", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			organizationService := services.NewOrganizationService(db, 20)
+
+			ctx := context.Background()
+			_, teacher, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			organization, err := organizationService.CreateOrganization(ctx, model.NewOrganization{Name: "Language School"}, teacher.ID)
+			if err != nil {
+				t.Fatalf("failed to create organization: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($userID: ID!, $organizationID: ID!) {
+                    changeOrganizationRole(userID: $userID, organizationID: $organizationID, role: STUDENT) {
+                        id
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"userID":         user.ID,
+					"organizationID": organization.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "user is not a member of organization : %d",
+                    "path": ["changeOrganizationRole"]
+                }],
+                "data": {
+                    "changeOrganizationRole": null
+                }
+            }`, organization.ID)
+
+			testGraphQLQueryAs(t, e, teacher.ID, jsonInput, expected)
+		})
+
+		t.Run("AcceptOrganizationInvite Mutation", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			organizationService := services.NewOrganizationService(db, 20)
+			inviteService := services.NewOrganizationInviteService(db, 20)
+
+			ctx := context.Background()
+			_, teacher, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, student, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			organization, err := organizationService.CreateOrganization(ctx, model.NewOrganization{Name: "Language School"}, teacher.ID)
+			if err != nil {
+				t.Fatalf("failed to create organization: %v", err)
+			}
+			invite, err := inviteService.CreateOrganizationInvite(ctx, model.NewOrganizationInvite{
+				OrganizationID: organization.ID,
+				Role:           model.OrganizationRoleStudent,
+				ExpiresAt:      time.Now().UTC().Add(time.Hour),
+				MaxUses:        1,
+			}, teacher.ID)
+			if err != nil {
+				t.Fatalf("failed to create invite: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($token: String!) {
+                    acceptOrganizationInvite(token: $token) {
+                        id
+                        name
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"token": *invite.Token,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "data": {
+                    "acceptOrganizationInvite": {
+                        "id": %d,
+                        "name": "Language School"
+                    }
+                }
+            }`, organization.ID)
+
+			testGraphQLQueryAs(t, e, student.ID, jsonInput, expected)
+
+			role, err := organizationService.GetOrganizationRole(ctx, organization.ID, student.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, model.OrganizationRoleStudent, *role)
+		})
+
+		t.Run("ClassroomProgress by Student", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			organizationService := services.NewOrganizationService(db, 20)
+			classroomService := services.NewClassroomService(db, 20)
+
+			ctx := context.Background()
+			_, teacher, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, student, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			organization, err := organizationService.CreateOrganization(ctx, model.NewOrganization{Name: "Language School"}, teacher.ID)
+			if err != nil {
+				t.Fatalf("failed to create organization: %v", err)
+			}
+			if _, err := organizationService.AddUserToOrganization(ctx, student.ID, organization.ID, model.OrganizationRoleStudent); err != nil {
+				t.Fatalf("failed to add user to organization: %v", err)
+			}
+			classroom, err := classroomService.CreateClassroom(ctx, model.NewClassroom{
+				OrganizationID: organization.ID,
+				Name:           "Japanese 101",
+			})
+			if err != nil {
+				t.Fatalf("failed to create classroom: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($classroomID: ID!) {
+                    classroomProgress(classroomID: $classroomID) {
+                        userID
+                        cardsReviewed
+                        accuracy
+                        overdueCount
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"classroomID": classroom.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "TEACHER role required in organization : %d: forbidden",
+                    "path": ["classroomProgress"]
+                }],
+                "data": null
+            }`, organization.ID)
+
+			testGraphQLQueryAs(t, e, student.ID, jsonInput, expected)
+		})
+
+		t.Run("DeleteSwipeRecord of Another User", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			cardService := services.NewCardService(db, 20, 1000, 0.8)
+			swipeRecordService := services.NewSwipeRecordService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, owner, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			_, otherUser, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			card, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "Front",
+				Back:        "Back",
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: createdGroup.ID,
+			})
+			if err != nil {
+				t.Fatalf("failed to create card: %v", err)
+			}
+			swipeRecord, err := swipeRecordService.CreateSwipeRecord(ctx, model.NewSwipeRecord{
+				UserID:      owner.ID,
+				CardID:      card.ID,
+				CardGroupID: createdGroup.ID,
+				Mode:        services.KNOWN,
+				Created:     time.Now().UTC(),
+				Updated:     time.Now().UTC(),
+			})
+			if err != nil {
+				t.Fatalf("failed to create swipe record: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($id: ID!) {
+                    deleteSwipeRecord(id: $id)
+                }`,
+				"variables": map[string]interface{}{
+					"id": swipeRecord.ID,
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "swipe record of another user : %d: forbidden",
+                    "path": ["deleteSwipeRecord"]
+                }],
+                "data": {
+                    "deleteSwipeRecord": null
+                }
+            }`, swipeRecord.ID)
+
+			testGraphQLQueryAs(t, e, otherUser.ID, jsonInput, expected)
+		})
+
+		t.Run("CreateSwipeRecord with Card of Another Card Group", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+			cardService := services.NewCardService(db, 20, 1000, 0.8)
+
+			ctx := context.Background()
+			ownGroup, user, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			otherGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			card, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "Front",
+				Back:        "Back",
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: otherGroup.ID,
+			})
+			if err != nil {
+				t.Fatalf("failed to create card: %v", err)
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: NewSwipeRecord!) {
+                    handleSwipe(input: $input) {
+                        id
+                    }
+                }`,
+				"variables": map[string]interface{}{
+					"input": map[string]interface{}{
+						"userId":      user.ID,
+						"cardId":      card.ID,
+						"cardGroupID": ownGroup.ID,
+						"mode":        services.KNOWN,
+						"created":     time.Now().UTC(),
+						"updated":     time.Now().UTC(),
+					},
+				},
+			})
+
+			expected := fmt.Sprintf(`{
+                "errors": [{
+                    "message": "card %d does not belong to card group : %d: forbidden",
+                    "path": ["handleSwipe"]
+                }],
+                "data": null
+            }`, card.ID, ownGroup.ID)
+
+			testGraphQLQueryAs(t, e, user.ID, jsonInput, expected)
+		})
+
+		t.Run("Upsert Dictionary Smoke", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			input := model.UpsertDictionary{
+				Dictionary: base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`
+New Front 1 裏面１
+New Front 2 裏面２
+`))),
+				CardgroupID: createdGroup.ID,
+			}
+
+			// Serialize the input to JSON
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: UpsertDictionary!) {
+	upsertDictionary(input: $input) {
+		nodes {
+			id
+			front
+			back
+			interval_days
+			cardGroupID
+		}
+	}
+}`,
+				"variables": map[string]interface{}{
+					"input": input,
+				},
+			})
+
+			// Expected response structure
+			expected := fmt.Sprintf(`{
+    "data": {
+        "upsertDictionary": {
+            "nodes": [{
+                "id": "1",
+                "front": "New Front 1",
+                "back": "裏面１",
+                "interval_days": 1,
+                "cardGroupID": %d
+            }, {
+                "id": "2",
+                "front": "New Front 2",
+                "back": "裏面２",
+                "interval_days": 1,
+                "cardGroupID": %d
+            }]
+        }
+    }
+}`, createdGroup.ID, createdGroup.ID)
+
+			// Perform the GraphQL query and check the results
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected, "data.upsertDictionary.nodes.id")
+		})
+
+		t.Run("Upsert Dictionary with Readings", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			input := model.UpsertDictionary{
+				Dictionary: base64.StdEncoding.EncodeToString([]byte(`
+study 勉強[べんきょう]
+`)),
+				CardgroupID: createdGroup.ID,
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: UpsertDictionary!) {
+	upsertDictionary(input: $input) {
+		nodes {
+			front
+			back
+			reading
+			ruby
+		}
+	}
+}`,
+				"variables": map[string]interface{}{
+					"input": input,
+				},
+			})
+
+			expected := `{
+    "data": {
+        "upsertDictionary": {
+            "nodes": [{
+                "front": "study",
+                "back": "勉強",
+                "reading": "勉強[べんきょう]",
+                "ruby": "<ruby>勉強<rt>べんきょう</rt></ruby>"
+            }]
+        }
+    }
+}`
+
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
+		})
+
+		t.Run("Upsert Dictionary with Invalid Format", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			// Generate dummy data
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			// Create invalid dictionary data
+			invalidDictionary := base64.StdEncoding.EncodeToString([]byte(`Invalid Dictionary Data Without Correct Format`))
+
+			input := model.UpsertDictionary{
+				Dictionary:  invalidDictionary,
+				CardgroupID: createdGroup.ID,
+			}
+
+			// Create the GraphQL query
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `mutation ($input: UpsertDictionary!) {
+	upsertDictionary(input: $input) {
 		nodes {
 			id
 			front
@@ -2264,7 +3525,91 @@ New Front 2 裏面２
 }`
 
 			// Execute the GraphQL query and verify the result
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
+		})
+
+		t.Run("Lookup Word without Dictionary", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($term: String!) {
+	lookupWord(term: $term) {
+		kanji
+		meanings
+	}
+}`,
+				"variables": map[string]interface{}{
+					"term": "run",
+				},
+			})
+
+			// FL_JMDICT_PATH is not set in tests
+			expected := `{
+	"errors": [
+		{
+			"message": "failed to look up word: failed to load dictionary: : dictionary is not configured",
+			"path": ["lookupWord"]
+		}
+	],
+	"data": null
+}`
+
 			testGraphQLQuery(t, e, jsonInput, expected)
 		})
+
+		t.Run("Preview Dictionary", func(t *testing.T) {
+			t.Helper()
+			t.Parallel()
+
+			userService := services.NewUserService(db, 20)
+			cardGroupService := services.NewCardGroupService(db, 20)
+			roleService := services.NewRoleService(db, 20)
+
+			ctx := context.Background()
+			createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+
+			input := model.UpsertDictionary{
+				Dictionary: base64.StdEncoding.EncodeToString([]byte(`
+New Front 1 裏面１
+`)),
+				CardgroupID: createdGroup.ID,
+			}
+
+			jsonInput, _ := json.Marshal(map[string]interface{}{
+				"query": `query ($input: UpsertDictionary!) {
+	previewDictionary(input: $input) {
+		creates {
+			front
+			back
+		}
+		updates {
+			front
+		}
+		unchanged {
+			front
+		}
+	}
+}`,
+				"variables": map[string]interface{}{
+					"input": input,
+				},
+			})
+
+			expected := `{
+    "data": {
+        "previewDictionary": {
+            "creates": [{
+                "front": "New Front 1",
+                "back": "裏面１"
+            }],
+            "updates": [],
+            "unchanged": []
+        }
+    }
+}`
+
+			testGraphQLQueryAs(t, e, createAdmin(t), jsonInput, expected)
+		})
 	})
 }
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"backend/pkg/auth"
+	"context"
+	"fmt"
+
+	"github.com/m-mizutani/goerr"
+	"gorm.io/gorm"
+)
+
+// Authorizer is the central place to consult the permissions granted to users through their roles
+type Authorizer interface {
+	UserPermissions(ctx context.Context, userID int64) ([]string, error)
+	HasPermission(ctx context.Context, userID int64, permission string) (bool, error)
+	Authorize(ctx context.Context, userID int64, permission string) error
+}
+
+type authorizer struct {
+	db *gorm.DB
+}
+
+func NewAuthorizer(db *gorm.DB) Authorizer {
+	return &authorizer{db: db}
+}
+
+// permissionsOfUser joins the permissions of all roles of the user
+func (a *authorizer) permissionsOfUser(ctx context.Context, userID int64) *gorm.DB {
+	return a.db.WithContext(ctx).
+		Table("permissions").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ?", userID)
+}
+
+// UserPermissions returns the names of the effective permissions of the user, sorted by name
+func (a *authorizer) UserPermissions(ctx context.Context, userID int64) ([]string, error) {
+	permissions := []string{}
+	if err := a.permissionsOfUser(ctx, userID).
+		Distinct("permissions.name").
+		Order("permissions.name").
+		Pluck("permissions.name", &permissions).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to get permissions of user : %d", userID))
+	}
+	return permissions, nil
+}
+
+func (a *authorizer) HasPermission(ctx context.Context, userID int64, permission string) (bool, error) {
+	var count int64
+	if err := a.permissionsOfUser(ctx, userID).
+		Where("permissions.name = ?", permission).
+		Count(&count).Error; err != nil {
+		return false, goerr.Wrap(err, fmt.Errorf("failed to check permission %s of user : %d", permission, userID))
+	}
+	return count > 0, nil
+}
+
+// Authorize returns an error wrapping auth.ErrForbidden unless the user has the permission
+func (a *authorizer) Authorize(ctx context.Context, userID int64, permission string) error {
+	ok, err := a.HasPermission(ctx, userID, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return goerr.Wrap(auth.ErrForbidden, fmt.Sprintf("permission %s required", permission))
+	}
+	return nil
+}
@@ -3,23 +3,30 @@ package services
 import (
 	repository "backend/graph/db"
 	"backend/graph/model"
+	"backend/pkg/furigana"
 	"backend/pkg/logger"
 	repo "backend/pkg/repository"
+	"backend/pkg/textdic"
 	"backend/pkg/utils"
 	"context"
 	"errors"
 	"fmt"
+	"github.com/lib/pq"
 	"github.com/m-mizutani/goerr"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 )
 
 type cardService struct {
-	db           *gorm.DB
-	defaultLimit int
+	db              *gorm.DB
+	defaultLimit    int
+	chunkSize       int
+	renameThreshold float64
 }
 
 type CardService interface {
@@ -31,24 +38,42 @@ type CardService interface {
 	GetCardsByIDs(ctx context.Context, ids []int64) ([]*model.Card, error)
 	FetchAllCardsByCardGroup(ctx context.Context, cardGroupID int64, first *int) ([]*model.Card, error)
 	AddNewCards(ctx context.Context, targetCards []model.Card, cardGroupID int64) ([]*model.Card, error)
+	MirrorCards(ctx context.Context, targetCards []model.Card, cardGroupID int64) ([]*model.Card, error)
+	PreviewNewCards(ctx context.Context, targetCards []model.Card, cardGroupID int64, mirror bool) (*model.DictionaryPreview, error)
 	GetCardsByUserAndCardGroup(ctx context.Context, cardGroupID int64,
 		order string, limit int) ([]*repository.Card, error)
 	ShuffleCards(cards []repository.Card, limit int) []*model.Card
 	GetRandomCardsFromRecentUpdates(ctx context.Context, cardGroupID int64,
 		limit int, updatedSortOrder string, intervalDaysSortOrder string) ([]*model.Card, error)
+	GetNewCardsFromRecentUpdates(ctx context.Context, cardGroupID int64,
+		limit int, updatedSortOrder string, intervalDaysSortOrder string) ([]*model.Card, error)
+	SetFrequencyRanks(ctx context.Context, cardGroupID int64, ranks map[int64]int) error
+	SetReadings(ctx context.Context, cardGroupID int64, readings map[int64]string) error
 	GetCardsByDefaultLogic(ctx context.Context, cardGroupID int64,
 		limit int) ([]*repository.Card, error)
+	CheckAnswer(ctx context.Context, id int64, answer string) (bool, error)
+	FindDuplicateCards(ctx context.Context, cardGroupID int64, threshold float64) ([]*model.DuplicateCardCluster, error)
+	MergeCards(ctx context.Context, targetCardID int64, sourceCardIDs []int64) (*model.Card, error)
 }
 
-func NewCardService(db *gorm.DB, defaultLimit int) CardService {
-	return &cardService{db: db, defaultLimit: defaultLimit}
+func NewCardService(db *gorm.DB, defaultLimit int, chunkSize int, renameThreshold float64) CardService {
+	return &cardService{db: db, defaultLimit: defaultLimit, chunkSize: chunkSize, renameThreshold: renameThreshold}
 }
 
 func ConvertToGormCardFromNew(input model.NewCard) *repository.Card {
 	return &repository.Card{
-		Front:      input.Front,
-		Back:       input.Back,
-		ReviewDate: input.ReviewDate,
+		Front: input.Front,
+		Back:  input.Back,
+		Reading: func() string {
+			if input.Reading != nil {
+				return *input.Reading
+			}
+			return ""
+		}(),
+		Senses:        convertToSenses(input.Senses),
+		Tags:          convertToSenses(input.Tags),
+		FrequencyRank: input.FrequencyRank,
+		ReviewDate:    input.ReviewDate,
 		IntervalDays: func() int {
 			if input.IntervalDays != nil {
 				return *input.IntervalDays
@@ -63,17 +88,30 @@ func ConvertToGormCardFromNew(input model.NewCard) *repository.Card {
 
 func ConvertToCard(card repository.Card) *model.Card {
 	return &model.Card{
-		ID:           card.ID,
-		Front:        card.Front,
-		Back:         card.Back,
-		ReviewDate:   card.ReviewDate,
-		IntervalDays: card.IntervalDays,
-		CardGroupID:  card.CardGroupID,
-		Created:      card.Created,
-		Updated:      card.Updated,
+		ID:            card.ID,
+		Front:         card.Front,
+		Back:          card.Back,
+		Reading:       card.Reading,
+		Ruby:          furigana.Ruby(card.Reading),
+		Senses:        convertToSenses(card.Senses),
+		Tags:          convertToSenses(card.Tags),
+		FrequencyRank: card.FrequencyRank,
+		ReviewDate:    card.ReviewDate,
+		IntervalDays:  card.IntervalDays,
+		CardGroupID:   card.CardGroupID,
+		Created:       card.Created,
+		Updated:       card.Updated,
 	}
 }
 
+// convertToSenses returns an empty list instead of nil since senses and tags are non-nullable in the schema.
+func convertToSenses(senses []string) []string {
+	if senses == nil {
+		return []string{}
+	}
+	return senses
+}
+
 func ConvertToCards(cards []repository.Card) []*model.Card {
 	var result []*model.Card
 	for _, card := range cards {
@@ -125,19 +163,38 @@ func (s *cardService) CreateCard(ctx context.Context, input model.NewCard) (*mod
 		if strings.Contains(result.Error.Error(), "foreign key constraint") {
 			return nil, goerr.Wrap(fmt.Errorf("invalid card group ID"))
 		}
+		if strings.Contains(result.Error.Error(), "duplicate key") {
+			return nil, goerr.Wrap(fmt.Errorf("card already exists in the card group : %s", input.Front))
+		}
 		return nil, goerr.Wrap(result.Error, fmt.Errorf("failed to create card"))
 	}
 	return ConvertToCard(*gormCard), nil
 }
 
+// UpdateCard updates the card. Senses and tags are kept when not given, except that the senses are
+// cleared when the back changes without new senses, so that CheckAnswer compares the new back.
 func (s *cardService) UpdateCard(ctx context.Context, id int64, input model.NewCard) (*model.Card, error) {
 	var card repository.Card
 	if err := s.db.WithContext(ctx).First(&card, id).Error; err != nil {
 		return nil, goerr.Wrap(fmt.Errorf("card does not exist : %d", id))
 	}
 
+	if input.Senses != nil {
+		card.Senses = input.Senses
+	} else if input.Back != card.Back {
+		card.Senses = nil
+	}
 	card.Front = input.Front
 	card.Back = input.Back
+	if input.Tags != nil {
+		card.Tags = input.Tags
+	}
+	if input.Reading != nil {
+		card.Reading = *input.Reading
+	}
+	if input.FrequencyRank != nil {
+		card.FrequencyRank = input.FrequencyRank
+	}
 	card.ReviewDate = input.ReviewDate
 	card.IntervalDays = func() int {
 		if input.IntervalDays != nil {
@@ -148,6 +205,9 @@ func (s *cardService) UpdateCard(ctx context.Context, id int64, input model.NewC
 	card.Updated = time.Now().UTC()
 
 	if err := s.db.WithContext(ctx).Save(&card).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, goerr.Wrap(fmt.Errorf("card already exists in the card group : %s", input.Front))
+		}
 		return nil, goerr.Wrap(err, "Failed to save card")
 	}
 	return ConvertToCard(card), nil
@@ -257,70 +317,339 @@ func (s *cardService) FetchAllCardsByCardGroup(ctx context.Context, cardGroupID
 	return allCards, nil
 }
 
-func (s *cardService) AddNewCards(ctx context.Context, targetCards []model.Card, cardGroupID int64) ([]*model.Card, error) {
-	// Use FetchAllCardsByCardGroup to retrieve all cards
-	existingCards, err := s.FetchAllCardsByCardGroup(ctx, cardGroupID, nil)
-	if err != nil {
-		return nil, goerr.Wrap(err)
-	}
+// cardUpdatePlan pairs an existing card with the card overwriting it
+type cardUpdatePlan struct {
+	existing   *model.Card
+	target     model.Card
+	similarity float64
+	// renamed is true when the Front of the existing card is replaced by a similar one
+	renamed bool
+}
+
+// cardPlan describes what AddNewCards or MirrorCards does for a list of target cards
+type cardPlan struct {
+	creates   []model.Card
+	updates   []cardUpdatePlan
+	unchanged []*model.Card
+	deletes   []*model.Card
+}
 
+// planCards compares the target cards with the existing cards by Front.
+// A card is updated when the similarity of the Back values is less than 1.0.
+// In mirror mode, existing cards missing from the target cards are deleted unless
+// the Front of a new card is similar enough to be taken as a rename of them.
+func planCards(existingCards []*model.Card, targetCards []model.Card, mirror bool, renameThreshold float64) *cardPlan {
 	// Create a hashmap to manage existing cards by Front value
 	existingCardsMap := make(map[string]*model.Card)
 	for _, card := range existingCards {
 		existingCardsMap[card.Front] = card
 	}
 
-	// Slice to hold the modified or newly created cards
-	var modifiedCards []*model.Card
-
-	// Process to add or update cards
+	plan := &cardPlan{}
+	targetFronts := make(map[string]bool)
 	for _, targetCard := range targetCards {
+		targetFronts[targetCard.Front] = true
 		existingCard, exists := existingCardsMap[targetCard.Front]
 		if !exists {
 			// If Front doesn't match, add as a new card
-			newCard := model.NewCard{
-				Front:        targetCard.Front,
-				Back:         targetCard.Back,
-				ReviewDate:   targetCard.ReviewDate,
-				IntervalDays: &targetCard.IntervalDays,
-				CardgroupID:  targetCard.CardGroupID,
-				Created:      time.Now().UTC(),
-				Updated:      time.Now().UTC(),
-			}
-			createdCard, err := s.CreateCard(ctx, newCard)
-			if err != nil {
-				return nil, goerr.Wrap(err, "Failed to add card")
-			}
-			modifiedCards = append(modifiedCards, createdCard)
+			plan.creates = append(plan.creates, targetCard)
 			continue
 		}
 
 		// If Front matches, check the similarity of the Back value
-		if utils.Similarity(existingCard.Back, targetCard.Back) >= 1.0 {
-			// Skip if similarity is 1.0
+		similarity := utils.Similarity(existingCard.Back, targetCard.Back)
+		if similarity >= 1.0 {
+			plan.unchanged = append(plan.unchanged, existingCard)
 			continue
 		}
+		plan.updates = append(plan.updates, cardUpdatePlan{
+			existing:   existingCard,
+			target:     targetCard,
+			similarity: similarity,
+		})
+	}
 
-		// Update the card if the Back similarity is not 1.0
-		newCard := model.NewCard{
-			Front:        targetCard.Front,
-			Back:         targetCard.Back,
-			ReviewDate:   targetCard.ReviewDate,
-			IntervalDays: &targetCard.IntervalDays,
-			CardgroupID:  targetCard.CardGroupID,
-			Created:      time.Now().UTC(),
-			Updated:      time.Now().UTC(),
+	if !mirror {
+		return plan
+	}
+
+	// Existing cards missing from the target cards
+	var missingCards []*model.Card
+	for _, card := range existingCards {
+		if !targetFronts[card.Front] {
+			missingCards = append(missingCards, card)
+		}
+	}
+
+	// Take the most similar missing card as renamed to keep its progress
+	creates := plan.creates
+	plan.creates = nil
+	for _, targetCard := range creates {
+		best := -1
+		bestSimilarity := renameThreshold
+		for i, card := range missingCards {
+			if card == nil {
+				continue
+			}
+			if similarity := utils.Similarity(card.Front, targetCard.Front); similarity >= bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+		if best < 0 {
+			plan.creates = append(plan.creates, targetCard)
+			continue
+		}
+		plan.updates = append(plan.updates, cardUpdatePlan{
+			existing:   missingCards[best],
+			target:     targetCard,
+			similarity: utils.Similarity(missingCards[best].Back, targetCard.Back),
+			renamed:    true,
+		})
+		missingCards[best] = nil
+	}
+
+	for _, card := range missingCards {
+		if card != nil {
+			plan.deletes = append(plan.deletes, card)
 		}
-		updatedCard, err := s.UpdateCard(ctx, existingCard.ID, newCard)
+	}
+	return plan
+}
+
+func (s *cardService) planNewCards(ctx context.Context, targetCards []model.Card, cardGroupID int64, mirror bool) (*cardPlan, error) {
+	// Use FetchAllCardsByCardGroup to retrieve all cards
+	existingCards, err := s.FetchAllCardsByCardGroup(ctx, cardGroupID, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err)
+	}
+	return planCards(existingCards, uniqueCardsByFront(targetCards), mirror, s.renameThreshold), nil
+}
+
+// AddNewCards creates the target cards, or updates the Back of the cards sharing their Front.
+// The cards are upserted in chunks, relying on the unique index on (cardgroup_id, front),
+// so that concurrent imports never duplicate a Front.
+func (s *cardService) AddNewCards(ctx context.Context, targetCards []model.Card, cardGroupID int64) ([]*model.Card, error) {
+	cards := make([]repository.Card, 0, len(targetCards))
+	for _, targetCard := range uniqueCardsByFront(targetCards) {
+		cards = append(cards, *ConvertToGormCardFromNew(convertToNewCard(targetCard)))
+	}
+
+	modifiedCards, err := s.upsertCardsInChunks(ctx, cards)
+	if err != nil {
+		return nil, goerr.Wrap(err, "Failed to add card")
+	}
+	return modifiedCards, nil
+}
+
+// MirrorCards makes the cards of the card group mirror the target cards.
+// Cards missing from the target cards are deleted, while renamed cards keep their progress.
+func (s *cardService) MirrorCards(ctx context.Context, targetCards []model.Card, cardGroupID int64) ([]*model.Card, error) {
+	plan, err := s.planNewCards(ctx, targetCards, cardGroupID, true)
+	if err != nil {
+		return nil, goerr.Wrap(err)
+	}
+	return s.applyCardPlan(ctx, plan)
+}
+
+// applyCardPlan writes the plan and returns the updated and created cards
+func (s *cardService) applyCardPlan(ctx context.Context, plan *cardPlan) ([]*model.Card, error) {
+	// Delete the cards missing from the dictionary
+	if len(plan.deletes) > 0 {
+		ids := make([]int64, 0, len(plan.deletes))
+		for _, card := range plan.deletes {
+			ids = append(ids, card.ID)
+		}
+		if err := s.db.WithContext(ctx).Where("id IN ?", ids).Delete(&repository.Card{}).Error; err != nil {
+			return nil, goerr.Wrap(err, "Failed to delete cards")
+		}
+	}
+
+	// Slice to hold the modified or newly created cards
+	var modifiedCards []*model.Card
+	// Cards to be created or whose Back is changed are upserted in chunks
+	var upsertCards []repository.Card
+
+	for _, update := range plan.updates {
+		if !update.renamed {
+			upsertCards = append(upsertCards, *ConvertToGormCardFromNew(convertToNewCard(update.target)))
+			continue
+		}
+
+		// Keep the progress of the renamed card
+		newCard := convertToNewCard(update.target)
+		newCard.ReviewDate = update.existing.ReviewDate
+		newCard.IntervalDays = &update.existing.IntervalDays
+		updatedCard, err := s.UpdateCard(ctx, update.existing.ID, newCard)
 		if err != nil {
 			return nil, goerr.Wrap(err, "Failed to update card")
 		}
 		modifiedCards = append(modifiedCards, updatedCard)
 	}
 
+	for _, targetCard := range plan.creates {
+		upsertCards = append(upsertCards, *ConvertToGormCardFromNew(convertToNewCard(targetCard)))
+	}
+	upsertedCards, err := s.upsertCardsInChunks(ctx, upsertCards)
+	if err != nil {
+		return nil, goerr.Wrap(err, "Failed to add card")
+	}
+	modifiedCards = append(modifiedCards, upsertedCards...)
+
 	return modifiedCards, nil
 }
 
+// PreviewNewCards returns what AddNewCards, or MirrorCards in mirror mode, would do without writing anything
+func (s *cardService) PreviewNewCards(ctx context.Context, targetCards []model.Card, cardGroupID int64, mirror bool) (*model.DictionaryPreview, error) {
+	plan, err := s.planNewCards(ctx, targetCards, cardGroupID, mirror)
+	if err != nil {
+		return nil, goerr.Wrap(err)
+	}
+
+	preview := &model.DictionaryPreview{
+		Creates:   []*model.CardCreatePreview{},
+		Updates:   []*model.CardUpdatePreview{},
+		Unchanged: []*model.Card{},
+		Deletes:   []*model.Card{},
+	}
+	for _, targetCard := range plan.creates {
+		preview.Creates = append(preview.Creates, &model.CardCreatePreview{
+			Front:  targetCard.Front,
+			Back:   targetCard.Back,
+			Senses: convertToSenses(targetCard.Senses),
+		})
+	}
+	for _, update := range plan.updates {
+		preview.Updates = append(preview.Updates, &model.CardUpdatePreview{
+			ID:         update.existing.ID,
+			Front:      update.existing.Front,
+			NewFront:   update.target.Front,
+			OldBack:    update.existing.Back,
+			NewBack:    update.target.Back,
+			Similarity: update.similarity,
+		})
+	}
+	preview.Unchanged = append(preview.Unchanged, plan.unchanged...)
+	preview.Deletes = append(preview.Deletes, plan.deletes...)
+
+	return preview, nil
+}
+
+// convertToNewCard converts a target card of AddNewCards into the input of CreateCard or UpdateCard
+func convertToNewCard(targetCard model.Card) model.NewCard {
+	intervalDays := targetCard.IntervalDays
+	return model.NewCard{
+		Front:        targetCard.Front,
+		Back:         targetCard.Back,
+		Reading:      &targetCard.Reading,
+		Senses:       targetCard.Senses,
+		Tags:         targetCard.Tags,
+		ReviewDate:   targetCard.ReviewDate,
+		IntervalDays: &intervalDays,
+		CardgroupID:  targetCard.CardGroupID,
+		Created:      time.Now().UTC(),
+		Updated:      time.Now().UTC(),
+	}
+}
+
+// upsertCardColumns are the columns written by upsertCardsInChunks
+var upsertCardColumns = []string{"front", "back", "reading", "senses", "tags", "review_date", "interval_days", "cardgroup_id", "created", "updated"}
+
+// upsertCardsInChunks inserts the cards with one statement per chunk. A card whose Front already
+// exists in the card group is updated only when its Back or, if given, its reading or tags differ, and
+// unchanged cards are not returned. Cards without a reading or tags keep those of the existing card.
+func (s *cardService) upsertCardsInChunks(ctx context.Context, cards []repository.Card) ([]*model.Card, error) {
+	if len(cards) == 0 {
+		return nil, nil
+	}
+
+	// Validate the cards since hooks are not called for raw queries
+	for i := range cards {
+		if err := cards[i].Validate(); err != nil {
+			return nil, goerr.Wrap(err)
+		}
+	}
+
+	chunkSize := s.chunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(cards)
+	}
+
+	var result []repository.Card
+	for start := 0; start < len(cards); start += chunkSize {
+		end := start + chunkSize
+		if end > len(cards) {
+			end = len(cards)
+		}
+
+		var upserted []repository.Card
+		sql, args := buildUpsertCardsSQL(cards[start:end])
+		if err := s.db.WithContext(ctx).Raw(sql, args...).Scan(&upserted).Error; err != nil {
+			if strings.Contains(err.Error(), "foreign key constraint") {
+				return nil, goerr.Wrap(fmt.Errorf("invalid card group ID"))
+			}
+			return nil, goerr.Wrap(err, fmt.Errorf("failed to upsert cards"))
+		}
+		result = append(result, upserted...)
+	}
+	return ConvertToCards(result), nil
+}
+
+// buildUpsertCardsSQL builds an INSERT ... ON CONFLICT statement for the cards
+func buildUpsertCardsSQL(cards []repository.Card) (string, []interface{}) {
+	var sql strings.Builder
+	args := make([]interface{}, 0, len(cards)*len(upsertCardColumns))
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(upsertCardColumns)), ", ") + ")"
+
+	sql.WriteString("INSERT INTO cards (" + strings.Join(upsertCardColumns, ", ") + ") VALUES ")
+	for i, card := range cards {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(placeholders)
+		senses := card.Senses
+		if senses == nil {
+			senses = pq.StringArray{}
+		}
+		tags := card.Tags
+		if tags == nil {
+			tags = pq.StringArray{}
+		}
+		args = append(args, card.Front, card.Back, card.Reading, senses, tags, card.ReviewDate,
+			card.IntervalDays, card.CardGroupID, card.Created, card.Updated)
+	}
+	sql.WriteString(` ON CONFLICT (cardgroup_id, front) DO UPDATE SET
+		back = EXCLUDED.back,
+		reading = CASE WHEN EXCLUDED.reading = '' THEN cards.reading ELSE EXCLUDED.reading END,
+		senses = EXCLUDED.senses,
+		tags = CASE WHEN cardinality(EXCLUDED.tags) = 0 THEN cards.tags ELSE EXCLUDED.tags END,
+		review_date = EXCLUDED.review_date,
+		interval_days = EXCLUDED.interval_days,
+		updated = EXCLUDED.updated
+	WHERE cards.back IS DISTINCT FROM EXCLUDED.back
+		OR (EXCLUDED.reading <> '' AND cards.reading IS DISTINCT FROM EXCLUDED.reading)
+		OR (cardinality(EXCLUDED.tags) > 0 AND cards.tags IS DISTINCT FROM EXCLUDED.tags)
+	RETURNING *`)
+
+	return sql.String(), args
+}
+
+// uniqueCardsByFront drops the cards whose Front appears again later, since a
+// single INSERT ... ON CONFLICT statement cannot affect the same row twice.
+func uniqueCardsByFront(cards []model.Card) []model.Card {
+	lastIndex := make(map[string]int, len(cards))
+	for i, card := range cards {
+		lastIndex[card.Front] = i
+	}
+
+	unique := make([]model.Card, 0, len(lastIndex))
+	for i, card := range cards {
+		if lastIndex[card.Front] == i {
+			unique = append(unique, card)
+		}
+	}
+	return unique
+}
+
 func (s *cardService) GetCardsByUserAndCardGroup(
 	ctx context.Context, cardGroupID int64, order string,
 	limit int) ([]*repository.Card, error) {
@@ -355,6 +684,51 @@ func (s *cardService) ShuffleCards(cards []repository.Card,
 
 func (s *cardService) GetRandomCardsFromRecentUpdates(ctx context.Context,
 	cardGroupID int64, limit int, updatedSortOrder string, intervalDaysSortOrder string) ([]*model.Card, error) {
+	cards, err := s.recentCards(ctx, cardGroupID, limit, updatedSortOrder, intervalDaysSortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shuffle the cards
+	return s.ShuffleCards(cards, limit), nil
+}
+
+// GetNewCardsFromRecentUpdates picks new cards in the new card order of the card group. Cards are picked
+// as GetRandomCardsFromRecentUpdates does in RANDOM order. In FREQUENCY and IMPORT_ORDER the whole card group
+// is ordered before the limit, by frequency rank with unranked cards last or as imported, and cards which have
+// never been swiped come before the others.
+func (s *cardService) GetNewCardsFromRecentUpdates(ctx context.Context,
+	cardGroupID int64, limit int, updatedSortOrder string, intervalDaysSortOrder string) ([]*model.Card, error) {
+	var cardGroup repository.Cardgroup
+	if err := s.db.WithContext(ctx).Select("new_card_order").First(&cardGroup, cardGroupID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("card group not found : %d", cardGroupID))
+	}
+
+	var order string
+	switch model.NewCardOrder(cardGroup.NewCardOrder) {
+	case model.NewCardOrderFrequency:
+		order = "frequency_rank ASC NULLS LAST, id ASC"
+	case model.NewCardOrderImportOrder:
+		order = "created ASC, id ASC"
+	default:
+		return s.GetRandomCardsFromRecentUpdates(ctx, cardGroupID, limit, updatedSortOrder, intervalDaysSortOrder)
+	}
+
+	var cards []repository.Card
+	if err := s.db.WithContext(ctx).
+		Where("cardgroup_id = ?", cardGroupID).
+		Order("EXISTS (SELECT 1 FROM swipe_records WHERE swipe_records.card_id = cards.id) ASC").
+		Order(order).
+		Limit(limit).
+		Find(&cards).Error; err != nil {
+		return nil, goerr.Wrap(err, "Failed to retrieve new cards")
+	}
+	return ConvertToCards(cards), nil
+}
+
+// recentCards fetches cards by cardGroupID ordered independently by updated and interval_days
+func (s *cardService) recentCards(ctx context.Context,
+	cardGroupID int64, limit int, updatedSortOrder string, intervalDaysSortOrder string) ([]repository.Card, error) {
 	var cards []repository.Card
 
 	// Validate sortOrder for updated and intervalDays
@@ -376,9 +750,56 @@ func (s *cardService) GetRandomCardsFromRecentUpdates(ctx context.Context,
 	if err != nil {
 		return nil, goerr.Wrap(err, "Failed to retrieve recent cards")
 	}
+	return cards, nil
+}
 
-	// Shuffle the cards
-	return s.ShuffleCards(cards, limit), nil
+// SetFrequencyRanks stores the frequency ranks of the cards of the card group by card ID.
+// Cards of the card group missing from ranks lose their rank.
+func (s *cardService) SetFrequencyRanks(ctx context.Context, cardGroupID int64, ranks map[int64]int) error {
+	ids := make(pq.Int64Array, 0, len(ranks))
+	values := make(pq.Int64Array, 0, len(ranks))
+	for id, rank := range ranks {
+		ids = append(ids, id)
+		values = append(values, int64(rank))
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&repository.Card{}).
+			Where("cardgroup_id = ?", cardGroupID).
+			Update("frequency_rank", nil).Error; err != nil {
+			return goerr.Wrap(err, "failed to clear frequency ranks")
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Exec(`UPDATE cards SET frequency_rank = ranks.rank
+		FROM unnest(?::bigint[], ?::bigint[]) AS ranks(id, rank)
+		WHERE cards.id = ranks.id AND cards.cardgroup_id = ?`, ids, values, cardGroupID).Error; err != nil {
+			return goerr.Wrap(err, "failed to set frequency ranks")
+		}
+		return nil
+	})
+}
+
+// SetReadings stores the readings of the cards of the card group by card ID
+func (s *cardService) SetReadings(ctx context.Context, cardGroupID int64, readings map[int64]string) error {
+	if len(readings) == 0 {
+		return nil
+	}
+
+	ids := make(pq.Int64Array, 0, len(readings))
+	values := make(pq.StringArray, 0, len(readings))
+	for id, reading := range readings {
+		ids = append(ids, id)
+		values = append(values, reading)
+	}
+
+	if err := s.db.WithContext(ctx).Exec(`UPDATE cards SET reading = readings.reading
+	FROM unnest(?::bigint[], ?::text[]) AS readings(id, reading)
+	WHERE cards.id = readings.id AND cards.cardgroup_id = ?`, ids, values, cardGroupID).Error; err != nil {
+		return goerr.Wrap(err, "failed to set readings")
+	}
+	return nil
 }
 
 func (s *cardService) GetCardsByDefaultLogic(ctx context.Context,
@@ -398,3 +819,164 @@ func (s *cardService) GetCardsByDefaultLogic(ctx context.Context,
 
 	return cards, nil
 }
+
+// CheckAnswer checks the answer against each sense of the card.
+// When the card has no senses, the whole back is compared instead.
+func (s *cardService) CheckAnswer(ctx context.Context, id int64, answer string) (bool, error) {
+	card, err := s.GetCardByID(ctx, id)
+	if err != nil {
+		return false, goerr.Wrap(err, "Failed to get card")
+	}
+
+	senses := card.Senses
+	if len(senses) == 0 {
+		senses = []string{card.Back}
+	}
+	return textdic.MatchesAnySense(senses, answer), nil
+}
+
+// FindDuplicateCards clusters the cards of the card group whose normalized Front and Back
+// are both similar to each other at least by the threshold. Clusters of a single card are omitted.
+func (s *cardService) FindDuplicateCards(ctx context.Context, cardGroupID int64, threshold float64) ([]*model.DuplicateCardCluster, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, goerr.Wrap(fmt.Errorf("threshold must be between 0 and 1 : %f", threshold))
+	}
+
+	cards, err := s.FetchAllCardsByCardGroup(ctx, cardGroupID, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err, "Failed to fetch cards")
+	}
+
+	fronts := make([]string, len(cards))
+	backs := make([]string, len(cards))
+	lengths := make([]int, len(cards))
+	order := make([]int, len(cards))
+	for i, card := range cards {
+		fronts[i] = utils.Normalize(card.Front)
+		backs[i] = utils.Normalize(card.Back)
+		lengths[i] = utf8.RuneCountInString(fronts[i])
+		order[i] = i
+	}
+	// Sort by the length of Front, so that the comparison stops once the lengths differ too much
+	sort.SliceStable(order, func(a, b int) bool { return lengths[order[a]] < lengths[order[b]] })
+
+	clusters := newDisjointSet(len(cards))
+	for a, i := range order {
+		for _, j := range order[a+1:] {
+			// The similarity can't exceed 1 - (difference of the lengths / longer length)
+			if lengths[j] > 0 && 1.0-float64(lengths[j]-lengths[i])/float64(lengths[j]) < threshold {
+				break
+			}
+			if utils.Similarity(fronts[i], fronts[j]) >= threshold && utils.Similarity(backs[i], backs[j]) >= threshold {
+				clusters.union(i, j)
+			}
+		}
+	}
+
+	// Cards are fetched in the order of ID, so clusters are ordered by their oldest card
+	members := make(map[int][]*model.Card)
+	var roots []int
+	for i, card := range cards {
+		root := clusters.find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], card)
+	}
+
+	result := []*model.DuplicateCardCluster{}
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			result = append(result, &model.DuplicateCardCluster{Cards: members[root]})
+		}
+	}
+	return result, nil
+}
+
+// MergeCards moves the swipe records of the source cards to the target card and deletes the source cards.
+// All the cards must belong to the same card group. Repeated source cards are merged once.
+func (s *cardService) MergeCards(ctx context.Context, targetCardID int64, sourceCardIDs []int64) (*model.Card, error) {
+	seen := make(map[int64]bool, len(sourceCardIDs))
+	uniqueIDs := make([]int64, 0, len(sourceCardIDs))
+	for _, id := range sourceCardIDs {
+		if id == targetCardID {
+			return nil, goerr.Wrap(fmt.Errorf("card can not be merged into itself : %d", id))
+		}
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+	sourceCardIDs = uniqueIDs
+
+	var target repository.Card
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&target, targetCardID).Error; err != nil {
+			return goerr.Wrap(fmt.Errorf("card does not exist : %d", targetCardID))
+		}
+
+		var sources []repository.Card
+		if err := tx.Where("id IN ?", sourceCardIDs).Find(&sources).Error; err != nil {
+			return goerr.Wrap(err, "Failed to get source cards")
+		}
+		if len(sources) != len(sourceCardIDs) {
+			return goerr.Wrap(fmt.Errorf("some of the source cards do not exist : %v", sourceCardIDs))
+		}
+		for _, source := range sources {
+			if source.CardGroupID != target.CardGroupID {
+				return goerr.Wrap(fmt.Errorf("card belongs to another card group : %d", source.ID))
+			}
+		}
+
+		// Combine the swipe history into the target card
+		if err := tx.Model(&repository.SwipeRecord{}).
+			Where("card_id IN ?", sourceCardIDs).
+			Update("card_id", target.ID).Error; err != nil {
+			return goerr.Wrap(err, "Failed to move swipe records")
+		}
+
+		if err := tx.Where("id IN ?", sourceCardIDs).Delete(&repository.Card{}).Error; err != nil {
+			return goerr.Wrap(err, "Failed to delete source cards")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "Failed to merge cards")
+	}
+
+	return ConvertToCard(target), nil
+}
+
+// disjointSet is a union-find structure to cluster cards
+type disjointSet struct {
+	parent []int
+}
+
+func newDisjointSet(size int) *disjointSet {
+	parent := make([]int, size)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &disjointSet{parent: parent}
+}
+
+func (d *disjointSet) find(i int) int {
+	for d.parent[i] != i {
+		// Path halving
+		d.parent[i] = d.parent[d.parent[i]]
+		i = d.parent[i]
+	}
+	return i
+}
+
+func (d *disjointSet) union(i, j int) {
+	rootI, rootJ := d.find(i), d.find(j)
+	if rootI == rootJ {
+		return
+	}
+	// Keep the smaller index as the root
+	if rootJ < rootI {
+		rootI, rootJ = rootJ, rootI
+	}
+	d.parent[rootJ] = rootI
+}
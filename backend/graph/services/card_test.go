@@ -9,6 +9,7 @@ import (
 	"context"
 	"math/rand"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -65,6 +66,7 @@ func (suite *CardTestSuite) TestCardService() {
 	cardGroupService := suite.sv.(services.CardGroupService)
 	userService := suite.sv.(services.UserService)
 	roleService := suite.sv.(services.RoleService)
+	swipeRecordService := suite.sv.(services.SwipeRecordService)
 	ctx := context.Background()
 	t := suite.T()
 	t.Helper()
@@ -178,6 +180,117 @@ func (suite *CardTestSuite) TestCardService() {
 		assert.Nil(suite.T(), updatedCard)
 	})
 
+	suite.Run("Normal_UpdateCard_KeepSenses", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		input := model.NewCard{
+			Front:       "run",
+			Back:        "走る；駆ける",
+			Senses:      []string{"走る", "駆ける"},
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+		createdCard, _ := cardService.CreateCard(ctx, input)
+
+		updateInput := model.NewCard{
+			Front:       "run",
+			Back:        "走る；駆ける",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+
+		// Act
+		updatedCard, err := cardService.UpdateCard(ctx, createdCard.ID, updateInput)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), []string{"走る", "駆ける"}, updatedCard.Senses)
+	})
+
+	suite.Run("Normal_UpdateCard_ChangedBackClearsSenses", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		input := model.NewCard{
+			Front:       "run",
+			Back:        "走る；駆ける",
+			Senses:      []string{"走る", "駆ける"},
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+		createdCard, _ := cardService.CreateCard(ctx, input)
+
+		updateInput := model.NewCard{
+			Front:       "run",
+			Back:        "運行する",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+
+		// Act
+		updatedCard, err := cardService.UpdateCard(ctx, createdCard.ID, updateInput)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Empty(suite.T(), updatedCard.Senses)
+		matched, err := cardService.CheckAnswer(ctx, createdCard.ID, "運行する")
+		assert.NoError(suite.T(), err)
+		assert.True(suite.T(), matched)
+		matched, err = cardService.CheckAnswer(ctx, createdCard.ID, "駆ける")
+		assert.NoError(suite.T(), err)
+		assert.False(suite.T(), matched)
+	})
+
+	suite.Run("Normal_CheckAnswer", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		input := model.NewCard{
+			Front:       "run",
+			Back:        "走る；駆ける；運行する",
+			Senses:      []string{"走る", "駆ける", "運行する"},
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+		createdCard, _ := cardService.CreateCard(ctx, input)
+
+		// Act
+		matched, err := cardService.CheckAnswer(ctx, createdCard.ID, "駆ける")
+		assert.NoError(suite.T(), err)
+		unmatched, err := cardService.CheckAnswer(ctx, createdCard.ID, "歩く")
+		assert.NoError(suite.T(), err)
+
+		// Assert
+		assert.True(suite.T(), matched)
+		assert.False(suite.T(), unmatched)
+	})
+
+	suite.Run("Normal_CheckAnswer_WithoutSenses", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		input := model.NewCard{
+			Front:       "rube",
+			Back:        "田舎者",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+		createdCard, _ := cardService.CreateCard(ctx, input)
+
+		// Act
+		matched, err := cardService.CheckAnswer(ctx, createdCard.ID, "田舎者")
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.True(suite.T(), matched)
+	})
+
+	suite.Run("Error_CheckAnswer_CardNotFound", func() {
+		// Act
+		matched, err := cardService.CheckAnswer(ctx, -1, "走る")
+
+		// Assert
+		assert.Error(suite.T(), err)
+		assert.False(suite.T(), matched)
+	})
+
 	suite.Run("Normal_DeleteCard", func() {
 		// Arrange
 		cardGroup := model.NewCardGroup{Name: "Test Group"}
@@ -428,6 +541,417 @@ func (suite *CardTestSuite) TestCardService() {
 		assert.Equal(t, createdCard.ID, updatedCard.ID) // Ensure the same card ID is retained
 	})
 
+	suite.Run("Error_CreateCard_DuplicateFront", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		input := model.NewCard{
+			Front:       "Test Front",
+			Back:        "Test Back",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		}
+		_, err := cardService.CreateCard(ctx, input)
+		assert.NoError(t, err)
+
+		// Act
+		createdCard, err := cardService.CreateCard(ctx, input)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "card already exists in the card group")
+		assert.Nil(t, createdCard)
+	})
+
+	suite.Run("Normal_AddNewCards_SkipUnchanged", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		targetCards := []model.Card{
+			{Front: "rube", Back: "田舎者", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+			{Front: "opaque", Back: "不透明な", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+			{Front: "opaque", Back: "不透明", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+		}
+		createdCards, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+		assert.NoError(t, err)
+		assert.Len(t, createdCards, 2) // The last "opaque" wins
+
+		// Act
+		targetCards[0].Back = "田舎の人"
+		modifiedCards, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, modifiedCards, 1)
+		assert.Equal(t, "rube", modifiedCards[0].Front)
+		assert.Equal(t, "田舎の人", modifiedCards[0].Back)
+		allCards, err := cardService.FetchAllCardsByCardGroup(ctx, createdGroup.ID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, allCards, 2)
+	})
+
+	suite.Run("Normal_AddNewCards_Tags", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		targetCards := []model.Card{
+			{Front: "rube", Back: "田舎者", Tags: []string{"noun"}, ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+		}
+		_, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+		assert.NoError(t, err)
+
+		// Act
+		targetCards[0].Tags = []string{"noun", "slang"}
+		modifiedCards, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+		assert.NoError(t, err)
+		targetCards[0].Tags = nil
+		unchangedCards, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, modifiedCards, 1)
+		assert.Equal(t, []string{"noun", "slang"}, modifiedCards[0].Tags)
+		assert.Empty(t, unchangedCards) // Cards without tags keep the existing tags
+		allCards, err := cardService.FetchAllCardsByCardGroup(ctx, createdGroup.ID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"noun", "slang"}, allCards[0].Tags)
+	})
+
+	suite.Run("Normal_AddNewCards_Reading", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		targetCards := []model.Card{
+			{Front: "study", Back: "勉強", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+		}
+		_, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+		assert.NoError(t, err)
+
+		// Act
+		targetCards[0].Reading = "勉強[べんきょう]"
+		modifiedCards, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+		assert.NoError(t, err)
+		targetCards[0].Reading = ""
+		unchangedCards, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, modifiedCards, 1)
+		assert.Equal(t, "勉強[べんきょう]", modifiedCards[0].Reading)
+		assert.Equal(t, "<ruby>勉強<rt>べんきょう</rt></ruby>", modifiedCards[0].Ruby)
+		assert.Empty(t, unchangedCards) // Cards without a reading keep the existing reading
+	})
+
+	suite.Run("Normal_SetReadings", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		card, err := cardService.CreateCard(ctx, model.NewCard{
+			Front:       "study",
+			Back:        "勉強",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+
+		// Act
+		err = cardService.SetReadings(ctx, createdGroup.ID, map[int64]string{card.ID: "勉強[べんきょう]"})
+
+		// Assert
+		assert.NoError(t, err)
+		cards, err := cardService.GetCardsByIDs(ctx, []int64{card.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, "勉強[べんきょう]", cards[0].Reading)
+		assert.True(t, card.Updated.Equal(cards[0].Updated)) // The schedule is not affected
+	})
+
+	suite.Run("Normal_AddNewCards_Concurrent", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		var targetCards []model.Card
+		for i := 0; i < 100; i++ {
+			targetCards = append(targetCards, model.Card{
+				Front:        "Front " + strconv.Itoa(i),
+				Back:         "Back " + strconv.Itoa(i),
+				ReviewDate:   time.Now().UTC(),
+				IntervalDays: 1,
+				CardGroupID:  createdGroup.ID,
+			})
+		}
+
+		// Act
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := cardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		// Assert
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		allCards, err := cardService.FetchAllCardsByCardGroup(ctx, createdGroup.ID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, allCards, 100)
+	})
+
+	suite.Run("Normal_PreviewNewCards", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		for _, input := range []model.NewCard{
+			{Front: "unchanged", Back: "同じ", ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID},
+			{Front: "updated", Back: "Test Back", ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID},
+		} {
+			_, err := cardService.CreateCard(ctx, input)
+			assert.NoError(t, err)
+		}
+
+		targetCards := []model.Card{
+			{Front: "unchanged", Back: "同じ", IntervalDays: 1, CardGroupID: createdGroup.ID},
+			{Front: "updated", Back: "Test BackX", IntervalDays: 1, CardGroupID: createdGroup.ID},
+			{Front: "created", Back: "新しい", IntervalDays: 1, CardGroupID: createdGroup.ID},
+		}
+
+		// Act
+		preview, err := cardService.PreviewNewCards(ctx, targetCards, createdGroup.ID, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, preview.Creates, 1)
+		assert.Equal(t, "created", preview.Creates[0].Front)
+		assert.Len(t, preview.Updates, 1)
+		assert.Equal(t, "Test Back", preview.Updates[0].OldBack)
+		assert.Equal(t, "Test BackX", preview.Updates[0].NewBack)
+		assert.InDelta(t, 0.9, preview.Updates[0].Similarity, 1e-9)
+		assert.Len(t, preview.Unchanged, 1)
+		assert.Equal(t, "unchanged", preview.Unchanged[0].Front)
+
+		// Nothing is written
+		allCards, err := cardService.FetchAllCardsByCardGroup(ctx, createdGroup.ID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, allCards, 2)
+		for _, card := range allCards {
+			assert.NotEqual(t, "Test BackX", card.Back)
+		}
+	})
+
+	suite.Run("Normal_MirrorCards", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		intervalDays := 5
+		reviewDate := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+		renamedCard, err := cardService.CreateCard(ctx, model.NewCard{
+			Front: "trot out", Back: "自慢げに話題に持ち出す", ReviewDate: reviewDate,
+			IntervalDays: &intervalDays, CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+		unchangedCard, err := cardService.CreateCard(ctx, model.NewCard{
+			Front: "rube", Back: "田舎者", ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+		deletedCard, err := cardService.CreateCard(ctx, model.NewCard{
+			Front: "opaque", Back: "不透明な", ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+
+		targetCards := []model.Card{
+			{Front: "trot-out", Back: "自慢げに話題に持ち出す", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+			{Front: "rube", Back: "田舎者", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+			{Front: "jarring", Back: "気に障る", ReviewDate: time.Now().UTC(), IntervalDays: 1, CardGroupID: createdGroup.ID},
+		}
+
+		// Act
+		preview, err := cardService.PreviewNewCards(ctx, targetCards, createdGroup.ID, true)
+		assert.NoError(t, err)
+		_, err = cardService.MirrorCards(ctx, targetCards, createdGroup.ID)
+		assert.NoError(t, err)
+
+		// Assert
+		assert.Len(t, preview.Deletes, 1)
+		assert.Equal(t, deletedCard.ID, preview.Deletes[0].ID)
+		assert.Len(t, preview.Updates, 1)
+		assert.Equal(t, "trot out", preview.Updates[0].Front)
+		assert.Equal(t, "trot-out", preview.Updates[0].NewFront)
+		assert.Len(t, preview.Creates, 1)
+		assert.Equal(t, "jarring", preview.Creates[0].Front)
+
+		_, err = cardService.GetCardByID(ctx, deletedCard.ID)
+		assert.Error(t, err)
+
+		fetchedCard, err := cardService.GetCardByID(ctx, renamedCard.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "trot-out", fetchedCard.Front)
+		assert.Equal(t, 5, fetchedCard.IntervalDays)
+		assert.True(t, reviewDate.Equal(fetchedCard.ReviewDate))
+
+		fetchedCard, err = cardService.GetCardByID(ctx, unchangedCard.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "rube", fetchedCard.Front)
+
+		allCards, err := cardService.FetchAllCardsByCardGroup(ctx, createdGroup.ID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, allCards, 3)
+	})
+
+	suite.Run("Normal_FindDuplicateCards", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		var createdCards []*model.Card
+		for _, input := range []model.NewCard{
+			{Front: "receive", Back: "受け取る"},
+			{Front: "recieve", Back: "受け取る"},
+			{Front: "Receive ", Back: "受け取る。"},
+			{Front: "rube", Back: "田舎者"},
+			{Front: "ＲＵＢＥ", Back: "田舎者"},
+			{Front: "opaque", Back: "不透明な"},
+		} {
+			input.ReviewDate = time.Now().UTC()
+			input.CardgroupID = createdGroup.ID
+			createdCard, err := cardService.CreateCard(ctx, input)
+			assert.NoError(t, err)
+			createdCards = append(createdCards, createdCard)
+		}
+
+		// Act
+		clusters, err := cardService.FindDuplicateCards(ctx, createdGroup.ID, 0.7)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, clusters, 2)
+		assert.Len(t, clusters[0].Cards, 3)
+		assert.Equal(t, createdCards[0].ID, clusters[0].Cards[0].ID)
+		assert.Len(t, clusters[1].Cards, 2)
+		assert.Equal(t, createdCards[3].ID, clusters[1].Cards[0].ID)
+	})
+
+	suite.Run("Error_FindDuplicateCards_InvalidThreshold", func() {
+		// Act
+		clusters, err := cardService.FindDuplicateCards(ctx, 1, 1.5)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, clusters)
+	})
+
+	suite.Run("Normal_MergeCards", func() {
+		// Arrange
+		targetCard, createdGroup, createdUser, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+		assert.NoError(t, err)
+		sourceCard, err := cardService.CreateCard(ctx, model.NewCard{
+			Front: targetCard.Front + "x", Back: targetCard.Back, ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+		for _, cardID := range []int64{targetCard.ID, sourceCard.ID, sourceCard.ID} {
+			_, err := swipeRecordService.CreateSwipeRecord(ctx, model.NewSwipeRecord{
+				UserID: createdUser.ID, CardID: cardID, CardGroupID: createdGroup.ID, Mode: services.KNOWN,
+				Created: time.Now().UTC(), Updated: time.Now().UTC(),
+			})
+			assert.NoError(t, err)
+		}
+
+		// Act
+		mergedCard, err := cardService.MergeCards(ctx, targetCard.ID, []int64{sourceCard.ID})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, targetCard.ID, mergedCard.ID)
+		_, err = cardService.GetCardByID(ctx, sourceCard.ID)
+		assert.Error(t, err)
+
+		var count int64
+		err = suite.db.Model(&repository.SwipeRecord{}).Where("card_id = ?", targetCard.ID).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	suite.Run("Normal_MergeCards_DuplicateSources", func() {
+		// Arrange
+		targetCard, createdGroup, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+		assert.NoError(t, err)
+		sourceCard, err := cardService.CreateCard(ctx, model.NewCard{
+			Front: targetCard.Front + "x", Back: targetCard.Back, ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+
+		// Act
+		mergedCard, err := cardService.MergeCards(ctx, targetCard.ID, []int64{sourceCard.ID, sourceCard.ID})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, targetCard.ID, mergedCard.ID)
+		_, err = cardService.GetCardByID(ctx, sourceCard.ID)
+		assert.Error(t, err)
+	})
+
+	suite.Run("Error_MergeCards_Itself", func() {
+		// Arrange
+		targetCard, createdGroup, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+		assert.NoError(t, err)
+		sourceCard, err := cardService.CreateCard(ctx, model.NewCard{
+			Front: targetCard.Front + "x", Back: targetCard.Back, ReviewDate: time.Now().UTC(), CardgroupID: createdGroup.ID,
+		})
+		assert.NoError(t, err)
+
+		// Act
+		mergedCard, err := cardService.MergeCards(ctx, targetCard.ID, []int64{sourceCard.ID, targetCard.ID})
+
+		// Assert
+		assert.ErrorContains(t, err, "card can not be merged into itself")
+		assert.Nil(t, mergedCard)
+		_, err = cardService.GetCardByID(ctx, sourceCard.ID)
+		assert.NoError(t, err)
+	})
+
+	suite.Run("Error_MergeCards_AnotherCardGroup", func() {
+		// Arrange
+		targetCard, _, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+		assert.NoError(t, err)
+		sourceCard, _, _, err := testutils.CreateUserCardAndCardGroup(ctx, userService, cardGroupService, roleService, cardService)
+		assert.NoError(t, err)
+
+		// Act
+		mergedCard, err := cardService.MergeCards(ctx, targetCard.ID, []int64{sourceCard.ID})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, mergedCard)
+		_, err = cardService.GetCardByID(ctx, sourceCard.ID)
+		assert.NoError(t, err)
+	})
+
+	suite.Run("Normal_AddNewCards_InChunks", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		chunkedCardService := services.NewCardService(suite.db, 100, 2, 0.8)
+
+		var targetCards []model.Card
+		for i := 0; i < 5; i++ {
+			targetCards = append(targetCards, model.Card{
+				Front:        "Front " + strconv.Itoa(i),
+				Back:         "Back " + strconv.Itoa(i),
+				ReviewDate:   time.Now().UTC(),
+				IntervalDays: 1,
+				CardGroupID:  createdGroup.ID,
+			})
+		}
+
+		// Act
+		createdCards, err := chunkedCardService.AddNewCards(ctx, targetCards, createdGroup.ID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, createdCards, 5)
+		for i, card := range createdCards {
+			assert.NotZero(t, card.ID)
+			assert.Equal(t, "Front "+strconv.Itoa(i), card.Front)
+		}
+		allCards, err := cardService.FetchAllCardsByCardGroup(ctx, createdGroup.ID, nil)
+		assert.NoError(t, err)
+		assert.Len(t, allCards, 5)
+	})
+
 	suite.Run("Normal_GetCardsByUserAndCardGroup", func() {
 		// Create a user and a card group
 		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
@@ -556,6 +1080,90 @@ func (suite *CardTestSuite) TestCardService() {
 		assert.Len(suite.T(), randomCards, limit) // Ensure that 10 cards are returned
 	})
 
+	suite.Run("Normal_GetNewCardsFromRecentUpdates_Frequency", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		order := model.NewCardOrderFrequency
+		_, err := cardGroupService.UpdateCardGroup(ctx, createdGroup.ID, model.NewCardGroup{Name: createdGroup.Name, NewCardOrder: &order})
+		assert.NoError(suite.T(), err)
+
+		ranks := make(map[int64]int)
+		for i := 0; i < 5; i++ {
+			card, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "Front " + strconv.Itoa(i),
+				Back:        "Back " + strconv.Itoa(i),
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: createdGroup.ID,
+			})
+			assert.NoError(suite.T(), err)
+			if i > 0 {
+				ranks[card.ID] = 10 - i // The first card has no rank
+			}
+		}
+		assert.NoError(suite.T(), cardService.SetFrequencyRanks(ctx, createdGroup.ID, ranks))
+
+		// Act
+		cards, err := cardService.GetNewCardsFromRecentUpdates(ctx, createdGroup.ID, 10, repo.DESC, repo.ASC)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		fronts := make([]string, 0, len(cards))
+		for _, card := range cards {
+			fronts = append(fronts, card.Front)
+		}
+		assert.Equal(suite.T(), []string{"Front 4", "Front 3", "Front 2", "Front 1", "Front 0"}, fronts)
+		assert.Equal(suite.T(), 6, *cards[0].FrequencyRank)
+		assert.Nil(suite.T(), cards[4].FrequencyRank)
+
+		// The limit applies after ordering the whole card group
+		cards, err = cardService.GetNewCardsFromRecentUpdates(ctx, createdGroup.ID, 2, repo.DESC, repo.ASC)
+		assert.NoError(suite.T(), err)
+		if assert.Len(suite.T(), cards, 2) {
+			assert.Equal(suite.T(), "Front 4", cards[0].Front)
+			assert.Equal(suite.T(), "Front 3", cards[1].Front)
+		}
+	})
+
+	suite.Run("Normal_GetNewCardsFromRecentUpdates_ImportOrder", func() {
+		// Arrange
+		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		order := model.NewCardOrderImportOrder
+		_, err := cardGroupService.UpdateCardGroup(ctx, createdGroup.ID, model.NewCardGroup{Name: createdGroup.Name, NewCardOrder: &order})
+		assert.NoError(suite.T(), err)
+
+		now := time.Now().UTC()
+		for i := 0; i < 5; i++ {
+			_, err := cardService.CreateCard(ctx, model.NewCard{
+				Front:       "Front " + strconv.Itoa(i),
+				Back:        "Back " + strconv.Itoa(i),
+				ReviewDate:  now,
+				CardgroupID: createdGroup.ID,
+				Created:     now.Add(time.Duration(i) * time.Minute),
+				Updated:     now,
+			})
+			assert.NoError(suite.T(), err)
+		}
+
+		// Act
+		cards, err := cardService.GetNewCardsFromRecentUpdates(ctx, createdGroup.ID, 10, repo.DESC, repo.ASC)
+
+		// Assert
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), cards, 5)
+		for i, card := range cards {
+			assert.Equal(suite.T(), "Front "+strconv.Itoa(i), card.Front)
+		}
+	})
+
+	suite.Run("Error_GetNewCardsFromRecentUpdates_CardGroupNotFound", func() {
+		// Act
+		cards, err := cardService.GetNewCardsFromRecentUpdates(ctx, -1, 10, repo.DESC, repo.ASC)
+
+		// Assert
+		assert.Error(suite.T(), err)
+		assert.Nil(suite.T(), cards)
+	})
+
 	suite.Run("Normal_GetCardsForReview", func() {
 		// Arrange
 		createdGroup, _, _ := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
@@ -11,6 +11,7 @@ import (
 	"github.com/m-mizutani/goerr"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // cardGroupService provides methods to manage card groups in the database.
@@ -25,7 +26,7 @@ type CardGroupService interface {
 	CardGroups(ctx context.Context) ([]*model.CardGroup, error)
 	UpdateCardGroup(ctx context.Context, id int64, input model.NewCardGroup) (*model.CardGroup, error)
 	DeleteCardGroup(ctx context.Context, id int64) (*bool, error)
-	AddUserToCardGroup(ctx context.Context, userID int64, cardGroupID int64) (*model.CardGroup, error)
+	AddUserToCardGroup(ctx context.Context, userID int64, cardGroupID int64, role model.CardGroupRole) (*model.CardGroup, error)
 	RemoveUserFromCardGroup(ctx context.Context, userID int64, cardGroupID int64) (*model.CardGroup, error)
 	GetCardGroupsByUser(ctx context.Context, userID int64) ([]*model.CardGroup, error)
 	PaginatedCardGroupsByUser(ctx context.Context, userID int64, first *int, after *int64, last *int, before *int64) (*model.CardGroupConnection, error)
@@ -33,6 +34,21 @@ type CardGroupService interface {
 	UpdateCardGroupUserState(ctx context.Context, cardGroupID int64, userID int64, newState int) error
 	GetLatestCardgroupUsers(ctx context.Context, cardGroupID int64, limit int, sortOrder string) ([]*repository.CardgroupUser, error)
 	GetCardgroupUser(ctx context.Context, cardGroupID int64, userID int64) (*repository.CardgroupUser, error)
+	IsCardGroupMember(ctx context.Context, cardGroupID int64, userID int64) (bool, error)
+	GetCardGroupRole(ctx context.Context, cardGroupID int64, userID int64) (*model.CardGroupRole, error)
+	HasCardGroupRole(ctx context.Context, cardGroupID int64, userID int64, role model.CardGroupRole) (bool, error)
+}
+
+// cardGroupRoleRanks orders the membership roles. A role can do everything the roles of lower ranks can.
+var cardGroupRoleRanks = map[model.CardGroupRole]int{
+	model.CardGroupRoleViewer: 1,
+	model.CardGroupRoleEditor: 2,
+	model.CardGroupRoleOwner:  3,
+}
+
+// CardGroupRoleAtLeast reports whether the membership role is the required role or a higher one
+func CardGroupRoleAtLeast(role model.CardGroupRole, required model.CardGroupRole) bool {
+	return cardGroupRoleRanks[role] >= cardGroupRoleRanks[required]
 }
 
 // NewCardGroupService creates a new CardGroupService instance.
@@ -42,23 +58,44 @@ func NewCardGroupService(db *gorm.DB, defaultLimit int) CardGroupService {
 
 // ConvertToGormCardGroupFromNew converts a NewCardGroup input to a GORM-compatible Cardgroup model.
 func ConvertToGormCardGroupFromNew(input model.NewCardGroup) *repository.Cardgroup {
+	newCardOrder := model.NewCardOrderRandom
+	if input.NewCardOrder != nil {
+		newCardOrder = *input.NewCardOrder
+	}
 	return &repository.Cardgroup{
-		Name:    input.Name,
-		Created: time.Now().UTC(),
-		Updated: time.Now().UTC(),
+		Name:         input.Name,
+		NewCardOrder: newCardOrder.String(),
+		Created:      time.Now().UTC(),
+		Updated:      time.Now().UTC(),
 	}
 }
 
 // ConvertToCardGroup converts a Cardgroup repository model to a GraphQL-compatible CardGroup model.
 func ConvertToCardGroup(cardGroup repository.Cardgroup) *model.CardGroup {
 	return &model.CardGroup{
-		ID:      cardGroup.ID,
-		Name:    cardGroup.Name,
-		Created: cardGroup.Created,
-		Updated: cardGroup.Updated,
+		ID:                cardGroup.ID,
+		Name:              cardGroup.Name,
+		NewCardOrder:      convertToNewCardOrder(cardGroup.NewCardOrder),
+		IsPublic:          cardGroup.IsPublic,
+		Description:       cardGroup.Description,
+		SourceLanguage:    cardGroup.SourceLanguage,
+		TargetLanguage:    cardGroup.TargetLanguage,
+		SourceCardGroupID: cardGroup.SourceCardGroupID,
+		SourceVersion:     cardGroup.SourceVersion,
+		Subscribed:        cardGroup.Subscribed,
+		Created:           cardGroup.Created,
+		Updated:           cardGroup.Updated,
 	}
 }
 
+// convertToNewCardOrder falls back to RANDOM for card groups without a valid order
+func convertToNewCardOrder(order string) model.NewCardOrder {
+	if newCardOrder := model.NewCardOrder(order); newCardOrder.IsValid() {
+		return newCardOrder
+	}
+	return model.NewCardOrderRandom
+}
+
 // GetCardGroupByID retrieves a card group by its ID from the database.
 func (s *cardGroupService) GetCardGroupByID(ctx context.Context, id int64) (*model.CardGroup, error) {
 	var cardGroup repository.Cardgroup
@@ -101,6 +138,9 @@ func (s *cardGroupService) UpdateCardGroup(ctx context.Context, id int64, input
 		return nil, goerr.Wrap(err, fmt.Errorf("card group not found for update : %d", id))
 	}
 	cardGroup.Name = input.Name
+	if input.NewCardOrder != nil {
+		cardGroup.NewCardOrder = input.NewCardOrder.String()
+	}
 	cardGroup.Updated = time.Now().UTC()
 	if err := s.db.WithContext(ctx).Save(&cardGroup).Error; err != nil {
 		return nil, goerr.Wrap(err, "failed to update card group")
@@ -122,8 +162,13 @@ func (s *cardGroupService) DeleteCardGroup(ctx context.Context, id int64) (*bool
 	return &success, nil
 }
 
-// AddUserToCardGroup adds a user to a card group in the database.
-func (s *cardGroupService) AddUserToCardGroup(ctx context.Context, userID int64, cardGroupID int64) (*model.CardGroup, error) {
+// AddUserToCardGroup adds a user to a card group in the database with the membership role,
+// or changes the role of the user when already a member.
+func (s *cardGroupService) AddUserToCardGroup(ctx context.Context, userID int64, cardGroupID int64, role model.CardGroupRole) (*model.CardGroup, error) {
+	if !role.IsValid() {
+		return nil, goerr.New(fmt.Sprintf("invalid card group role : %s", role))
+	}
+
 	var user repository.User
 	var cardGroup repository.Cardgroup
 	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
@@ -132,12 +177,45 @@ func (s *cardGroupService) AddUserToCardGroup(ctx context.Context, userID int64,
 	if err := s.db.WithContext(ctx).First(&cardGroup, cardGroupID).Error; err != nil {
 		return nil, goerr.Wrap(err, fmt.Errorf("card group not found : %d", cardGroupID))
 	}
-	if err := s.db.Model(&cardGroup).Association("Users").Append(&user); err != nil {
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if role != model.CardGroupRoleOwner {
+			if err := ensureAnotherOwner(tx, cardGroupID, userID); err != nil {
+				return err
+			}
+		}
+		member := repository.CardgroupUser{
+			CardGroupID: cardGroupID,
+			UserID:      userID,
+			Role:        role.String(),
+			Updated:     time.Now().UTC(),
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cardgroup_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated"}),
+		}).Create(&member).Error
+	}); err != nil {
 		return nil, goerr.Wrap(err, "failed to add user to card group")
 	}
 	return ConvertToCardGroup(cardGroup), nil
 }
 
+// ensureAnotherOwner fails when the user is the last owner of the card group, which would leave
+// the card group without anyone to delete or share it.
+func ensureAnotherOwner(tx *gorm.DB, cardGroupID int64, userID int64) error {
+	var owners []int64
+	if err := tx.Model(&repository.CardgroupUser{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("cardgroup_id = ? AND role = ?", cardGroupID, model.CardGroupRoleOwner.String()).
+		Pluck("user_id", &owners).Error; err != nil {
+		return goerr.Wrap(err, "failed to get owners of card group")
+	}
+	if len(owners) == 1 && owners[0] == userID {
+		return goerr.New(fmt.Sprintf("the last owner cannot leave card group : %d", cardGroupID))
+	}
+	return nil
+}
+
 // RemoveUserFromCardGroup removes a user from a card group in the database.
 func (s *cardGroupService) RemoveUserFromCardGroup(ctx context.Context, userID int64, cardGroupID int64) (*model.CardGroup, error) {
 	var user repository.User
@@ -148,7 +226,12 @@ func (s *cardGroupService) RemoveUserFromCardGroup(ctx context.Context, userID i
 	if err := s.db.WithContext(ctx).First(&cardGroup, cardGroupID).Error; err != nil {
 		return nil, goerr.Wrap(err, fmt.Errorf("card group not found : %d", cardGroupID))
 	}
-	if err := s.db.Model(&cardGroup).Association("Users").Delete(&user); err != nil {
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureAnotherOwner(tx, cardGroupID, userID); err != nil {
+			return err
+		}
+		return tx.Model(&cardGroup).Association("Users").Delete(&user)
+	}); err != nil {
 		return nil, goerr.Wrap(err, "failed to remove user from card group")
 	}
 	return ConvertToCardGroup(cardGroup), nil
@@ -297,3 +380,43 @@ func (s *cardGroupService) GetCardgroupUser(ctx context.Context, cardGroupID int
 	}
 	return &cardgroupUser, nil
 }
+
+// IsCardGroupMember reports whether the user is attached to the card group in cardgroup_users
+func (s *cardGroupService) IsCardGroupMember(ctx context.Context, cardGroupID int64, userID int64) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).
+		Model(&repository.CardgroupUser{}).
+		Where("cardgroup_id = ? AND user_id = ?", cardGroupID, userID).
+		Count(&count).Error; err != nil {
+		return false, goerr.Wrap(err, fmt.Errorf("failed to check membership of card group %d for user : %d", cardGroupID, userID))
+	}
+	return count > 0, nil
+}
+
+// GetCardGroupRole returns the membership role of the user in the card group, or nil when not a member
+func (s *cardGroupService) GetCardGroupRole(ctx context.Context, cardGroupID int64, userID int64) (*model.CardGroupRole, error) {
+	var roles []string
+	if err := s.db.WithContext(ctx).
+		Model(&repository.CardgroupUser{}).
+		Where("cardgroup_id = ? AND user_id = ?", cardGroupID, userID).
+		Pluck("role", &roles).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to get role in card group %d for user : %d", cardGroupID, userID))
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	role := model.CardGroupRole(roles[0])
+	return &role, nil
+}
+
+// HasCardGroupRole reports whether the user is a member of the card group with the role or a higher one
+func (s *cardGroupService) HasCardGroupRole(ctx context.Context, cardGroupID int64, userID int64, role model.CardGroupRole) (bool, error) {
+	memberRole, err := s.GetCardGroupRole(ctx, cardGroupID, userID)
+	if err != nil {
+		return false, err
+	}
+	if memberRole == nil {
+		return false, nil
+	}
+	return CardGroupRoleAtLeast(*memberRole, role), nil
+}
@@ -89,14 +89,13 @@ func (suite *CardGroupTestSuite) TestCardGroupService() {
 			Updated: time.Now().UTC(),
 			RoleIds: []int64{createdRole.ID}, // Assign the new role to the user
 		}
-		createdUser, err := userService.CreateUser(ctx, newUser)
+		_, err = userService.CreateUser(ctx, newUser)
 
 		// Create a card group
 		input := model.NewCardGroup{
 			Name:    "",
 			Created: time.Now().UTC(),
 			Updated: time.Now().UTC(),
-			UserIds: []int64{createdUser.ID},
 		}
 
 		createdGroup, err := cardGroupService.CreateCardGroup(ctx, input)
@@ -135,6 +134,20 @@ func (suite *CardGroupTestSuite) TestCardGroupService() {
 
 		assert.NoError(t, err)
 		assert.Equal(t, "Updated Group", updatedGroup.Name)
+		assert.Equal(t, model.NewCardOrderRandom, updatedGroup.NewCardOrder)
+	})
+
+	suite.Run("Normal_UpdateCardGroup_NewCardOrder", func() {
+		order := model.NewCardOrderFrequency
+		createdGroup, err := cardGroupService.CreateCardGroup(context.Background(), model.NewCardGroup{Name: "Test Group", NewCardOrder: &order})
+		assert.NoError(t, err)
+		assert.Equal(t, model.NewCardOrderFrequency, createdGroup.NewCardOrder)
+
+		order = model.NewCardOrderImportOrder
+		updatedGroup, err := cardGroupService.UpdateCardGroup(context.Background(), createdGroup.ID, model.NewCardGroup{Name: "Test Group", NewCardOrder: &order})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.NewCardOrderImportOrder, updatedGroup.NewCardOrder)
 	})
 
 	suite.Run("Error_UpdateCardGroup", func() {
@@ -207,7 +220,7 @@ func (suite *CardGroupTestSuite) TestCardGroupService() {
 		assert.NotNil(t, createdCardGroup)
 
 		// Add user to card group
-		group, err := cardGroupService.AddUserToCardGroup(ctx, createdUser.ID, createdCardGroup.ID)
+		group, err := cardGroupService.AddUserToCardGroup(ctx, createdUser.ID, createdCardGroup.ID, model.CardGroupRoleOwner)
 		assert.NoError(t, err)
 		assert.NotNil(t, group)
 	})
@@ -217,7 +230,7 @@ func (suite *CardGroupTestSuite) TestCardGroupService() {
 		userID := int64(-1)      // Invalid user ID
 		cardGroupID := int64(-1) // Invalid card group ID
 
-		group, err := cardGroupService.AddUserToCardGroup(context.Background(), userID, cardGroupID)
+		group, err := cardGroupService.AddUserToCardGroup(context.Background(), userID, cardGroupID, model.CardGroupRoleViewer)
 
 		assert.Error(t, err)
 		assert.Nil(t, group)
@@ -250,14 +263,13 @@ func (suite *CardGroupTestSuite) TestCardGroupService() {
 			Name:    "Test Group",
 			Created: time.Now().UTC(),
 			Updated: time.Now().UTC(),
-			UserIds: []int64{createdUser.ID},
 		}
 		createdGroup, err := cardGroupService.CreateCardGroup(ctx, input)
 		assert.NoError(t, err)
 		assert.NotNil(t, createdGroup)
 
 		// Add user to card group
-		_, err = cardGroupService.AddUserToCardGroup(ctx, createdUser.ID, createdGroup.ID)
+		_, err = cardGroupService.AddUserToCardGroup(ctx, createdUser.ID, createdGroup.ID, model.CardGroupRoleViewer)
 		assert.NoError(t, err)
 
 		// Now remove the user from the card group
@@ -352,6 +364,79 @@ func (suite *CardGroupTestSuite) TestCardGroupService() {
 		}
 	})
 
+	suite.Run("Normal_IsCardGroupMember", func() {
+		createdGroup, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		assert.NoError(t, err)
+		_, otherUser, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		assert.NoError(t, err)
+
+		member, err := cardGroupService.IsCardGroupMember(ctx, createdGroup.ID, user.ID)
+		assert.NoError(t, err)
+		assert.True(t, member)
+
+		member, err = cardGroupService.IsCardGroupMember(ctx, createdGroup.ID, otherUser.ID)
+		assert.NoError(t, err)
+		assert.False(t, member)
+	})
+
+	suite.Run("Normal_CardGroupRole", func() {
+		createdGroup, owner, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		assert.NoError(t, err)
+		_, editor, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		assert.NoError(t, err)
+		_, stranger, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		assert.NoError(t, err)
+
+		_, err = cardGroupService.AddUserToCardGroup(ctx, editor.ID, createdGroup.ID, model.CardGroupRoleEditor)
+		assert.NoError(t, err)
+
+		role, err := cardGroupService.GetCardGroupRole(ctx, createdGroup.ID, editor.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleEditor, *role)
+		role, err = cardGroupService.GetCardGroupRole(ctx, createdGroup.ID, stranger.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, role)
+
+		cases := []struct {
+			userID   int64
+			role     model.CardGroupRole
+			expected bool
+		}{
+			{owner.ID, model.CardGroupRoleOwner, true},
+			{owner.ID, model.CardGroupRoleViewer, true},
+			{editor.ID, model.CardGroupRoleEditor, true},
+			{editor.ID, model.CardGroupRoleViewer, true},
+			{editor.ID, model.CardGroupRoleOwner, false},
+			{stranger.ID, model.CardGroupRoleViewer, false},
+		}
+		for _, c := range cases {
+			ok, err := cardGroupService.HasCardGroupRole(ctx, createdGroup.ID, c.userID, c.role)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, ok, "user %d with role %s", c.userID, c.role)
+		}
+
+		// Sharing again changes the role of the member
+		_, err = cardGroupService.AddUserToCardGroup(ctx, editor.ID, createdGroup.ID, model.CardGroupRoleViewer)
+		assert.NoError(t, err)
+		role, err = cardGroupService.GetCardGroupRole(ctx, createdGroup.ID, editor.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleViewer, *role)
+	})
+
+	suite.Run("Error_LastOwner", func() {
+		createdGroup, owner, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+		assert.NoError(t, err)
+
+		_, err = cardGroupService.AddUserToCardGroup(ctx, owner.ID, createdGroup.ID, model.CardGroupRoleEditor)
+		assert.ErrorContains(t, err, "the last owner cannot leave card group")
+		_, err = cardGroupService.RemoveUserFromCardGroup(ctx, owner.ID, createdGroup.ID)
+		assert.ErrorContains(t, err, "the last owner cannot leave card group")
+
+		role, err := cardGroupService.GetCardGroupRole(ctx, createdGroup.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleOwner, *role)
+	})
+
 	suite.Run("Error_GetLatestCardgroupUsers_InvalidCardGroupID", func() {
 		// Attempt to retrieve records using an invalid CardGroupID
 		limit := 3
@@ -0,0 +1,253 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// catalogService manages the public card groups and the copies users make of them
+type catalogService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type CatalogService interface {
+	PublicCardGroups(ctx context.Context, search *string, language *string, first *int, after *int64) (*model.CardGroupConnection, error)
+	PublishCardGroup(ctx context.Context, input model.PublishCardGroup) (*model.CardGroup, error)
+	UnpublishCardGroup(ctx context.Context, id int64) (*model.CardGroup, error)
+	SubscribeCardGroup(ctx context.Context, id int64, userID int64) (*model.CardGroup, error)
+	UnsubscribeCardGroup(ctx context.Context, id int64) (*bool, error)
+	ForkCardGroup(ctx context.Context, id int64, userID int64) (*model.CardGroup, error)
+}
+
+func NewCatalogService(db *gorm.DB, defaultLimit int) CatalogService {
+	return &catalogService{db: db, defaultLimit: defaultLimit}
+}
+
+// escapeLike escapes the wildcards of LIKE patterns
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// PublicCardGroups returns a page of the public card groups ordered by ID
+func (s *catalogService) PublicCardGroups(ctx context.Context, search *string, language *string, first *int, after *int64) (*model.CardGroupConnection, error) {
+	query := s.db.WithContext(ctx).Model(&repository.Cardgroup{}).Where("is_public")
+	if search != nil && strings.TrimSpace(*search) != "" {
+		pattern := "%" + escapeLike(strings.TrimSpace(*search)) + "%"
+		query = query.Where("(name ILIKE ? OR description ILIKE ?)", pattern, pattern)
+	}
+	if language != nil && *language != "" {
+		query = query.Where("(source_language = ? OR target_language = ?)", *language, *language)
+	}
+
+	var totalCount int64
+	if err := query.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to count public card groups")
+	}
+
+	limit := s.defaultLimit
+	if first != nil {
+		limit = *first
+	}
+	if after != nil {
+		query = query.Where("id > ?", *after)
+	}
+	var cardGroups []repository.Cardgroup
+	if err := query.Order("id asc").Limit(limit + 1).Find(&cardGroups).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve public card groups")
+	}
+
+	pageInfo := &model.PageInfo{
+		HasNextPage:     len(cardGroups) > limit,
+		HasPreviousPage: after != nil,
+	}
+	if len(cardGroups) > limit {
+		cardGroups = cardGroups[:limit]
+	}
+
+	edges := make([]*model.CardGroupEdge, 0, len(cardGroups))
+	nodes := make([]*model.CardGroup, 0, len(cardGroups))
+	for _, cardGroup := range cardGroups {
+		node := ConvertToCardGroup(cardGroup)
+		edges = append(edges, &model.CardGroupEdge{Cursor: cardGroup.ID, Node: node})
+		nodes = append(nodes, node)
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = &edges[0].Cursor
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.CardGroupConnection{
+		Edges:      edges,
+		Nodes:      nodes,
+		PageInfo:   pageInfo,
+		TotalCount: int(totalCount),
+	}, nil
+}
+
+// PublishCardGroup lists the card group in the catalog with the description and the language pair.
+// The cards are published as the first version unless the card group has been published before.
+func (s *catalogService) PublishCardGroup(ctx context.Context, input model.PublishCardGroup) (*model.CardGroup, error) {
+	var published *model.CardGroup
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cardGroup repository.Cardgroup
+		if err := tx.First(&cardGroup, input.CardGroupID).Error; err != nil {
+			return goerr.Wrap(err, fmt.Errorf("card group not found : %d", input.CardGroupID))
+		}
+		if cardGroup.Subscribed {
+			return goerr.New(fmt.Sprintf("subscription cannot be published : %d", cardGroup.ID))
+		}
+
+		cardGroup.IsPublic = true
+		cardGroup.Description = input.Description
+		cardGroup.SourceLanguage = input.SourceLanguage
+		cardGroup.TargetLanguage = input.TargetLanguage
+		cardGroup.Updated = time.Now().UTC()
+		if err := tx.Save(&cardGroup).Error; err != nil {
+			return goerr.Wrap(err, "failed to publish card group")
+		}
+
+		latest, err := latestVersion(tx, cardGroup.ID)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			if _, _, err := snapshotCardGroup(tx, cardGroup.ID, ""); err != nil {
+				return err
+			}
+		}
+		published = ConvertToCardGroup(cardGroup)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+// UnpublishCardGroup removes the card group from the catalog. Existing copies are kept.
+func (s *catalogService) UnpublishCardGroup(ctx context.Context, id int64) (*model.CardGroup, error) {
+	var cardGroup repository.Cardgroup
+	if err := s.db.WithContext(ctx).First(&cardGroup, id).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("card group not found : %d", id))
+	}
+
+	cardGroup.IsPublic = false
+	cardGroup.Updated = time.Now().UTC()
+	if err := s.db.WithContext(ctx).Save(&cardGroup).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to unpublish card group")
+	}
+	return ConvertToCardGroup(cardGroup), nil
+}
+
+// SubscribeCardGroup copies the public card group with fresh progress, where the user is a viewer
+func (s *catalogService) SubscribeCardGroup(ctx context.Context, id int64, userID int64) (*model.CardGroup, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).
+		Model(&repository.Cardgroup{}).
+		Joins("JOIN cardgroup_users ON cardgroup_users.cardgroup_id = cardgroups.id").
+		Where("cardgroups.source_cardgroup_id = ? AND cardgroups.subscribed AND cardgroup_users.user_id = ?", id, userID).
+		Count(&count).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to check subscription")
+	}
+	if count > 0 {
+		return nil, goerr.New(fmt.Sprintf("already subscribed to card group : %d", id))
+	}
+
+	cardGroup, err := s.copyPublicCardGroup(ctx, id, userID, true)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to subscribe card group")
+	}
+	return cardGroup, nil
+}
+
+// UnsubscribeCardGroup deletes the subscription, which belongs to its subscriber alone
+func (s *catalogService) UnsubscribeCardGroup(ctx context.Context, id int64) (*bool, error) {
+	success := false
+	result := s.db.WithContext(ctx).Where("subscribed").Delete(&repository.Cardgroup{}, id)
+	if result.Error != nil {
+		return &success, goerr.Wrap(result.Error, "failed to unsubscribe card group")
+	}
+	if result.RowsAffected == 0 {
+		return &success, goerr.Wrap(fmt.Errorf("no subscription found : %d", id))
+	}
+	success = true
+	return &success, nil
+}
+
+// ForkCardGroup copies the public card group with fresh progress into a card group owned by the user
+func (s *catalogService) ForkCardGroup(ctx context.Context, id int64, userID int64) (*model.CardGroup, error) {
+	cardGroup, err := s.copyPublicCardGroup(ctx, id, userID, false)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fork card group")
+	}
+	return cardGroup, nil
+}
+
+// copyPublicCardGroup copies the latest version of the public card group, whose cards remember the
+// cards they are copied from. The user becomes a viewer of a subscription and the owner of a fork.
+func (s *catalogService) copyPublicCardGroup(ctx context.Context, id int64, userID int64, subscribed bool) (*model.CardGroup, error) {
+	var copied *model.CardGroup
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source repository.Cardgroup
+		if err := tx.Where("is_public").First(&source, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return goerr.Wrap(err, fmt.Errorf("public card group not found : %d", id))
+			}
+			return goerr.Wrap(err, "failed to retrieve public card group")
+		}
+		latest, err := latestVersion(tx, source.ID)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			return goerr.New(fmt.Sprintf("no version published for card group : %d", source.ID))
+		}
+
+		now := time.Now().UTC()
+		cardGroup := repository.Cardgroup{
+			Name:              source.Name,
+			NewCardOrder:      source.NewCardOrder,
+			Description:       source.Description,
+			SourceLanguage:    source.SourceLanguage,
+			TargetLanguage:    source.TargetLanguage,
+			SourceCardGroupID: &source.ID,
+			SourceVersion:     &latest.Version,
+			Subscribed:        subscribed,
+			Created:           now,
+			Updated:           now,
+		}
+		if err := tx.Create(&cardGroup).Error; err != nil {
+			return goerr.Wrap(err, "failed to create card group")
+		}
+
+		if err := tx.Exec(`INSERT INTO cards (front, back, reading, senses, tags, frequency_rank,
+				review_date, interval_days, cardgroup_id, source_card_id, created, updated)
+			SELECT front, back, reading, senses, tags, frequency_rank, ?, 1, ?, card_id, ?, ?
+			FROM cardgroup_version_cards WHERE version_id = ? ORDER BY card_id`,
+			now, cardGroup.ID, now, now, latest.ID).Error; err != nil {
+			return goerr.Wrap(err, "failed to copy cards")
+		}
+
+		role := model.CardGroupRoleOwner
+		if subscribed {
+			role = model.CardGroupRoleViewer
+		}
+		if _, err := NewCardGroupService(tx, s.defaultLimit).AddUserToCardGroup(ctx, userID, cardGroup.ID, role); err != nil {
+			return err
+		}
+
+		copied = ConvertToCardGroup(cardGroup)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type CatalogTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *CatalogTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *CatalogTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *CatalogTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+func (suite *CatalogTestSuite) TestCatalogService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	// publishDeck creates a public card group with the cards
+	publishDeck := func(description string, sourceLanguage string, cards int) *model.CardGroup {
+		cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		for i := 0; i < cards; i++ {
+			_, err := suite.sv.CreateCard(ctx, model.NewCard{
+				Front:       "Front " + strconv.Itoa(i),
+				Back:        "Back " + strconv.Itoa(i),
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: cardGroup.ID,
+			})
+			suite.Require().NoError(err)
+		}
+		published, err := suite.sv.PublishCardGroup(ctx, model.PublishCardGroup{
+			CardGroupID:    cardGroup.ID,
+			Description:    description,
+			SourceLanguage: sourceLanguage,
+			TargetLanguage: "en",
+		})
+		suite.Require().NoError(err)
+		return published
+	}
+	newUser := func() *model.User {
+		_, user, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		return user
+	}
+
+	suite.Run("Normal_PublicCardGroups", func() {
+		jlpt := publishDeck("JLPT N5 vocabulary", "ja", 0)
+		publishDeck("Korean basics", "ko", 0)
+		_, _, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv) // Private
+		suite.Require().NoError(err)
+
+		all, err := suite.sv.PublicCardGroups(ctx, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, all.TotalCount)
+
+		search := "n5"
+		found, err := suite.sv.PublicCardGroups(ctx, &search, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, found.Nodes, 1)
+		assert.Equal(t, jlpt.ID, found.Nodes[0].ID)
+
+		language := "en" // Either side of the language pair
+		found, err = suite.sv.PublicCardGroups(ctx, nil, &language, nil, nil)
+		assert.NoError(t, err)
+		assert.Len(t, found.Nodes, 2)
+
+		first := 1
+		page, err := suite.sv.PublicCardGroups(ctx, nil, nil, &first, nil)
+		assert.NoError(t, err)
+		assert.Len(t, page.Nodes, 1)
+		assert.True(t, page.PageInfo.HasNextPage)
+		page, err = suite.sv.PublicCardGroups(ctx, nil, nil, &first, page.PageInfo.EndCursor)
+		assert.NoError(t, err)
+		assert.Len(t, page.Nodes, 1)
+		assert.False(t, page.PageInfo.HasNextPage)
+	})
+
+	suite.Run("Normal_UnpublishCardGroup", func() {
+		deck := publishDeck("Deck", "ja", 0)
+
+		_, err := suite.sv.UnpublishCardGroup(ctx, deck.ID)
+
+		assert.NoError(t, err)
+		all, err := suite.sv.PublicCardGroups(ctx, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, all.Nodes)
+	})
+
+	suite.Run("Normal_SubscribeCardGroup", func() {
+		deck := publishDeck("Deck", "ja", 3)
+		user := newUser()
+
+		subscription, err := suite.sv.SubscribeCardGroup(ctx, deck.ID, user.ID)
+
+		assert.NoError(t, err)
+		assert.True(t, subscription.Subscribed)
+		assert.False(t, subscription.IsPublic)
+		assert.Equal(t, deck.ID, *subscription.SourceCardGroupID)
+		role, err := suite.sv.GetCardGroupRole(ctx, subscription.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleViewer, *role) // Read-only
+
+		var cards []repository.Card
+		suite.db.Where("cardgroup_id = ?", subscription.ID).Order("id").Find(&cards)
+		assert.Len(t, cards, 3)
+		for i, card := range cards {
+			assert.Equal(t, "Front "+strconv.Itoa(i), card.Front)
+			assert.NotNil(t, card.SourceCardID)
+			assert.Equal(t, 1, card.IntervalDays)
+		}
+
+		_, err = suite.sv.SubscribeCardGroup(ctx, deck.ID, user.ID)
+		assert.ErrorContains(t, err, "already subscribed")
+
+		success, err := suite.sv.UnsubscribeCardGroup(ctx, subscription.ID)
+		assert.NoError(t, err)
+		assert.True(t, *success)
+	})
+
+	suite.Run("Normal_ForkCardGroup", func() {
+		deck := publishDeck("Deck", "ja", 2)
+		user := newUser()
+
+		fork, err := suite.sv.ForkCardGroup(ctx, deck.ID, user.ID)
+
+		assert.NoError(t, err)
+		assert.False(t, fork.Subscribed)
+		assert.Equal(t, deck.ID, *fork.SourceCardGroupID)
+		role, err := suite.sv.GetCardGroupRole(ctx, fork.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleOwner, *role)
+
+		// A fork is not a subscription
+		_, err = suite.sv.UnsubscribeCardGroup(ctx, fork.ID)
+		assert.Error(t, err)
+	})
+
+	suite.Run("Error_SubscribePrivateCardGroup", func() {
+		cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+
+		_, err = suite.sv.SubscribeCardGroup(ctx, cardGroup.ID, newUser().ID)
+		assert.ErrorContains(t, err, "public card group not found")
+		_, err = suite.sv.ForkCardGroup(ctx, cardGroup.ID, newUser().ID)
+		assert.ErrorContains(t, err, "public card group not found")
+	})
+}
+
+func TestCatalogTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogTestSuite))
+}
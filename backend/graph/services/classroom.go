@@ -0,0 +1,308 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type classroomService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type ClassroomService interface {
+	CreateClassroom(ctx context.Context, input model.NewClassroom) (*model.Classroom, error)
+	GetClassroomByID(ctx context.Context, id int64) (*model.Classroom, error)
+	Classrooms(ctx context.Context, organizationID int64) ([]*model.Classroom, error)
+	AddStudentToClassroom(ctx context.Context, classroomID int64, userID int64) (*model.Classroom, error)
+	RemoveStudentFromClassroom(ctx context.Context, classroomID int64, userID int64) (*model.Classroom, error)
+	AssignCardGroupToClassroom(ctx context.Context, classroomID int64, cardGroupID int64) (*model.Classroom, error)
+	UnassignCardGroupFromClassroom(ctx context.Context, classroomID int64, cardGroupID int64) (*model.Classroom, error)
+	ClassroomProgress(ctx context.Context, classroomID int64) ([]*model.StudentProgress, error)
+}
+
+func NewClassroomService(db *gorm.DB, defaultLimit int) ClassroomService {
+	return &classroomService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToClassroom(classroom repository.Classroom, studentIDs []int64, cardGroupIDs []int64) *model.Classroom {
+	return &model.Classroom{
+		ID:             classroom.ID,
+		OrganizationID: classroom.OrganizationID,
+		Name:           classroom.Name,
+		StudentIDs:     studentIDs,
+		CardGroupIDs:   cardGroupIDs,
+		Created:        classroom.Created,
+		Updated:        classroom.Updated,
+	}
+}
+
+// convertToClassrooms converts the classrooms with the IDs of their students and card groups
+func convertToClassrooms(tx *gorm.DB, classrooms []repository.Classroom) ([]*model.Classroom, error) {
+	ids := make([]int64, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		ids = append(ids, classroom.ID)
+	}
+
+	var students []repository.ClassroomStudent
+	if err := tx.Where("classroom_id IN ?", ids).Order("user_id").Find(&students).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve students of classrooms")
+	}
+	var cardGroups []repository.ClassroomCardgroup
+	if err := tx.Where("classroom_id IN ?", ids).Order("cardgroup_id").Find(&cardGroups).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve card groups of classrooms")
+	}
+
+	studentIDs := make(map[int64][]int64, len(classrooms))
+	for _, student := range students {
+		studentIDs[student.ClassroomID] = append(studentIDs[student.ClassroomID], student.UserID)
+	}
+	cardGroupIDs := make(map[int64][]int64, len(classrooms))
+	for _, cardGroup := range cardGroups {
+		cardGroupIDs[cardGroup.ClassroomID] = append(cardGroupIDs[cardGroup.ClassroomID], cardGroup.CardGroupID)
+	}
+
+	gqlClassrooms := make([]*model.Classroom, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		gqlClassrooms = append(gqlClassrooms, ConvertToClassroom(classroom,
+			append([]int64{}, studentIDs[classroom.ID]...), append([]int64{}, cardGroupIDs[classroom.ID]...)))
+	}
+	return gqlClassrooms, nil
+}
+
+func (s *classroomService) CreateClassroom(ctx context.Context, input model.NewClassroom) (*model.Classroom, error) {
+	now := time.Now().UTC()
+	classroom := repository.Classroom{
+		OrganizationID: input.OrganizationID,
+		Name:           input.Name,
+		Created:        now,
+		Updated:        now,
+	}
+	if err := s.db.WithContext(ctx).Create(&classroom).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to create classroom")
+	}
+	return ConvertToClassroom(classroom, []int64{}, []int64{}), nil
+}
+
+func (s *classroomService) GetClassroomByID(ctx context.Context, id int64) (*model.Classroom, error) {
+	var classroom repository.Classroom
+	if err := s.db.WithContext(ctx).First(&classroom, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, goerr.Wrap(err, fmt.Errorf("classroom not found : %d", id))
+		}
+		return nil, goerr.Wrap(err, "failed to retrieve classroom by ID")
+	}
+
+	classrooms, err := convertToClassrooms(s.db.WithContext(ctx), []repository.Classroom{classroom})
+	if err != nil {
+		return nil, err
+	}
+	return classrooms[0], nil
+}
+
+// Classrooms returns the classrooms of the organization
+func (s *classroomService) Classrooms(ctx context.Context, organizationID int64) ([]*model.Classroom, error) {
+	var classrooms []repository.Classroom
+	if err := s.db.WithContext(ctx).
+		Where("organization_id = ?", organizationID).
+		Order("id").
+		Limit(s.defaultLimit).
+		Find(&classrooms).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve classrooms")
+	}
+	return convertToClassrooms(s.db.WithContext(ctx), classrooms)
+}
+
+// enrollClassroom enrolls every student of the classroom in every card group assigned to it as a viewer.
+// Students who are already members of a card group keep their role.
+func enrollClassroom(tx *gorm.DB, classroomID int64) error {
+	if err := tx.Exec(`INSERT INTO cardgroup_users (cardgroup_id, user_id, role, updated)
+		SELECT classroom_cardgroups.cardgroup_id, classroom_students.user_id, ?, ?
+		FROM classroom_cardgroups
+		JOIN classroom_students ON classroom_students.classroom_id = classroom_cardgroups.classroom_id
+		WHERE classroom_cardgroups.classroom_id = ?
+		ON CONFLICT (cardgroup_id, user_id) DO NOTHING`,
+		model.CardGroupRoleViewer.String(), time.Now().UTC(), classroomID).Error; err != nil {
+		return goerr.Wrap(err, fmt.Errorf("failed to enroll students of classroom : %d", classroomID))
+	}
+	return nil
+}
+
+// AddStudentToClassroom adds the member of the organization of the classroom to the classroom and enrolls
+// them in the card groups assigned to it
+func (s *classroomService) AddStudentToClassroom(ctx context.Context, classroomID int64, userID int64) (*model.Classroom, error) {
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var classroom repository.Classroom
+		if err := tx.First(&classroom, classroomID).Error; err != nil {
+			return goerr.Wrap(err, fmt.Errorf("classroom not found : %d", classroomID))
+		}
+		role, err := NewOrganizationService(tx, s.defaultLimit).GetOrganizationRole(ctx, classroom.OrganizationID, userID)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return goerr.New(fmt.Sprintf("user is not a member of organization : %d", classroom.OrganizationID))
+		}
+
+		student := repository.ClassroomStudent{
+			ClassroomID: classroomID,
+			UserID:      userID,
+			Created:     time.Now().UTC(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&student).Error; err != nil {
+			return goerr.Wrap(err, "failed to add student to classroom")
+		}
+		return enrollClassroom(tx, classroomID)
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to add student to classroom")
+	}
+	return s.GetClassroomByID(ctx, classroomID)
+}
+
+// RemoveStudentFromClassroom removes the student from the classroom. The enrollments are kept.
+func (s *classroomService) RemoveStudentFromClassroom(ctx context.Context, classroomID int64, userID int64) (*model.Classroom, error) {
+	result := s.db.WithContext(ctx).
+		Where("classroom_id = ? AND user_id = ?", classroomID, userID).
+		Delete(&repository.ClassroomStudent{})
+	if result.Error != nil {
+		return nil, goerr.Wrap(result.Error, "failed to remove student from classroom")
+	}
+	if result.RowsAffected == 0 {
+		return nil, goerr.Wrap(fmt.Errorf("student not found in classroom : %d", classroomID))
+	}
+	return s.GetClassroomByID(ctx, classroomID)
+}
+
+// AssignCardGroupToClassroom assigns the card group to the classroom and enrolls its students in it
+func (s *classroomService) AssignCardGroupToClassroom(ctx context.Context, classroomID int64, cardGroupID int64) (*model.Classroom, error) {
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cardGroup repository.Cardgroup
+		if err := tx.First(&cardGroup, cardGroupID).Error; err != nil {
+			return goerr.Wrap(err, fmt.Errorf("card group not found : %d", cardGroupID))
+		}
+
+		assignment := repository.ClassroomCardgroup{
+			ClassroomID: classroomID,
+			CardGroupID: cardGroupID,
+			Created:     time.Now().UTC(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignment).Error; err != nil {
+			return goerr.Wrap(err, "failed to assign card group to classroom")
+		}
+		return enrollClassroom(tx, classroomID)
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to assign card group to classroom")
+	}
+	return s.GetClassroomByID(ctx, classroomID)
+}
+
+// UnassignCardGroupFromClassroom removes the card group from the classroom. The enrollments are kept.
+func (s *classroomService) UnassignCardGroupFromClassroom(ctx context.Context, classroomID int64, cardGroupID int64) (*model.Classroom, error) {
+	result := s.db.WithContext(ctx).
+		Where("classroom_id = ? AND cardgroup_id = ?", classroomID, cardGroupID).
+		Delete(&repository.ClassroomCardgroup{})
+	if result.Error != nil {
+		return nil, goerr.Wrap(result.Error, "failed to unassign card group from classroom")
+	}
+	if result.RowsAffected == 0 {
+		return nil, goerr.Wrap(fmt.Errorf("card group not assigned to classroom : %d", classroomID))
+	}
+	return s.GetClassroomByID(ctx, classroomID)
+}
+
+// studentSwipe is a swipe of a student on a card assigned to the classroom
+type studentSwipe struct {
+	UserID  int64     `gorm:"column:user_id"`
+	CardID  int64     `gorm:"column:card_id"`
+	Mode    int       `gorm:"column:mode"`
+	Created time.Time `gorm:"column:created"`
+}
+
+// overdueCounts counts the cards of each student past the review date of their own swipes, ordered by
+// student, card and time. The interval grows with each known swipe in a row and resets otherwise, so
+// that the shared review date of the card, which every student moves, is not used.
+func overdueCounts(swipes []studentSwipe, now time.Time) map[int64]int {
+	counts := map[int64]int{}
+	knownInRow := 0
+	for i, swipe := range swipes {
+		if i > 0 && (swipes[i-1].UserID != swipe.UserID || swipes[i-1].CardID != swipe.CardID) {
+			knownInRow = 0
+		}
+		if swipe.Mode == KNOWN {
+			knownInRow++
+		} else {
+			knownInRow = 0
+		}
+
+		last := i == len(swipes)-1 || swipes[i+1].UserID != swipe.UserID || swipes[i+1].CardID != swipe.CardID
+		if !last {
+			continue
+		}
+		intervalDays := ReviewIntervalDays[min(knownInRow, len(ReviewIntervalDays)-1)]
+		if swipe.Created.AddDate(0, 0, intervalDays).Before(now) {
+			counts[swipe.UserID]++
+		}
+	}
+	return counts
+}
+
+// ClassroomProgress returns the progress of each student of the classroom on the card groups assigned to it
+func (s *classroomService) ClassroomProgress(ctx context.Context, classroomID int64) ([]*model.StudentProgress, error) {
+	var progress []struct {
+		UserID        int64 `gorm:"column:user_id"`
+		CardsReviewed int   `gorm:"column:cards_reviewed"`
+		Swipes        int   `gorm:"column:swipes"`
+		Known         int   `gorm:"column:known"`
+	}
+	if err := s.db.WithContext(ctx).Raw(`SELECT classroom_students.user_id,
+			COUNT(DISTINCT swipe_records.card_id) AS cards_reviewed,
+			COUNT(swipe_records.id) FILTER (WHERE swipe_records.mode <> ?) AS swipes,
+			COUNT(swipe_records.id) FILTER (WHERE swipe_records.mode = ?) AS known
+		FROM classroom_students
+		LEFT JOIN swipe_records ON swipe_records.user_id = classroom_students.user_id
+			AND swipe_records.cardgroup_id IN (SELECT cardgroup_id FROM classroom_cardgroups WHERE classroom_id = ?)
+		WHERE classroom_students.classroom_id = ?
+		GROUP BY classroom_students.classroom_id, classroom_students.user_id
+		ORDER BY classroom_students.user_id`,
+		UNDEFINED, KNOWN, classroomID, classroomID).
+		Scan(&progress).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to get classroom progress")
+	}
+
+	var swipes []studentSwipe
+	if err := s.db.WithContext(ctx).Raw(`SELECT swipe_records.user_id, swipe_records.card_id, swipe_records.mode, swipe_records.created
+		FROM swipe_records
+		JOIN classroom_students ON classroom_students.user_id = swipe_records.user_id
+		WHERE classroom_students.classroom_id = ?
+		  AND swipe_records.cardgroup_id IN (SELECT cardgroup_id FROM classroom_cardgroups WHERE classroom_id = ?)
+		  AND swipe_records.mode <> ?
+		ORDER BY swipe_records.user_id, swipe_records.card_id, swipe_records.created, swipe_records.id`,
+		classroomID, classroomID, UNDEFINED).
+		Scan(&swipes).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to get swipes of classroom")
+	}
+	overdue := overdueCounts(swipes, time.Now().UTC())
+
+	gqlProgress := make([]*model.StudentProgress, 0, len(progress))
+	for _, student := range progress {
+		accuracy := 0.0
+		if student.Swipes > 0 {
+			accuracy = float64(student.Known) / float64(student.Swipes)
+		}
+		gqlProgress = append(gqlProgress, &model.StudentProgress{
+			UserID:        student.UserID,
+			CardsReviewed: student.CardsReviewed,
+			Accuracy:      accuracy,
+			OverdueCount:  overdue[student.UserID],
+		})
+	}
+	return gqlProgress, nil
+}
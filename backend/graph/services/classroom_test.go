@@ -0,0 +1,201 @@
+package services_test
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type ClassroomTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *ClassroomTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *ClassroomTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *ClassroomTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+func (suite *ClassroomTestSuite) TestClassroomService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	newUser := func() *model.User {
+		_, user, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		return user
+	}
+	// createClassroom creates an organization of the teacher with a classroom of the students
+	createClassroom := func(teacher *model.User, students ...*model.User) *model.Classroom {
+		organization, err := suite.sv.CreateOrganization(ctx, model.NewOrganization{Name: "Language School"}, teacher.ID)
+		suite.Require().NoError(err)
+		classroom, err := suite.sv.CreateClassroom(ctx, model.NewClassroom{
+			OrganizationID: organization.ID,
+			Name:           "Japanese 101",
+		})
+		suite.Require().NoError(err)
+		for _, student := range students {
+			_, err := suite.sv.AddUserToOrganization(ctx, student.ID, organization.ID, model.OrganizationRoleStudent)
+			suite.Require().NoError(err)
+			classroom, err = suite.sv.AddStudentToClassroom(ctx, classroom.ID, student.ID)
+			suite.Require().NoError(err)
+		}
+		return classroom
+	}
+
+	suite.Run("Normal_AssignCardGroupToClassroom", func() {
+		cardGroup, teacher, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		student := newUser()
+		classroom := createClassroom(teacher, student)
+
+		classroom, err = suite.sv.AssignCardGroupToClassroom(ctx, classroom.ID, cardGroup.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{cardGroup.ID}, classroom.CardGroupIDs)
+		role, err := suite.sv.GetCardGroupRole(ctx, cardGroup.ID, student.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleViewer, *role)
+
+		// A student added later is enrolled too, and the teacher keeps the OWNER role
+		lateStudent := newUser()
+		_, err = suite.sv.AddUserToOrganization(ctx, lateStudent.ID, classroom.OrganizationID, model.OrganizationRoleStudent)
+		suite.Require().NoError(err)
+		_, err = suite.sv.AddUserToOrganization(ctx, teacher.ID, classroom.OrganizationID, model.OrganizationRoleTeacher)
+		suite.Require().NoError(err)
+		for _, user := range []*model.User{lateStudent, teacher} {
+			classroom, err = suite.sv.AddStudentToClassroom(ctx, classroom.ID, user.ID)
+			assert.NoError(t, err)
+		}
+		assert.Len(t, classroom.StudentIDs, 3)
+		role, err = suite.sv.GetCardGroupRole(ctx, cardGroup.ID, lateStudent.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleViewer, *role)
+		role, err = suite.sv.GetCardGroupRole(ctx, cardGroup.ID, teacher.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleOwner, *role)
+	})
+
+	suite.Run("Normal_ClassroomProgress", func() {
+		cardGroup, teacher, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		student, otherStudent := newUser(), newUser()
+		classroom := createClassroom(teacher, student, otherStudent)
+		_, err = suite.sv.AssignCardGroupToClassroom(ctx, classroom.ID, cardGroup.ID)
+		suite.Require().NoError(err)
+
+		var cards []*model.Card
+		for i, reviewDate := range []time.Time{
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		} {
+			card, err := suite.sv.CreateCard(ctx, model.NewCard{
+				Front:       "Front " + string(rune('A'+i)),
+				Back:        "Back",
+				ReviewDate:  reviewDate,
+				CardgroupID: cardGroup.ID,
+			})
+			suite.Require().NoError(err)
+			cards = append(cards, card)
+		}
+		now := time.Now().UTC()
+		for _, swipe := range []struct {
+			user    *model.User
+			card    *model.Card
+			mode    int
+			created time.Time
+		}{
+			{student, cards[0], services.KNOWN, now.AddDate(0, 0, -10)},
+			{student, cards[0], services.UNKNOWN, now.AddDate(0, 0, -2)}, // Due 1 day after
+			{student, cards[2], services.KNOWN, now.AddDate(0, 0, -2)},   // Due 3 days after
+			{otherStudent, cards[1], services.KNOWN, now.AddDate(0, 0, -3)},
+			{otherStudent, cards[1], services.KNOWN, now.AddDate(0, 0, -2)}, // Due 7 days after
+		} {
+			_, err := suite.sv.CreateSwipeRecord(ctx, model.NewSwipeRecord{
+				UserID:      swipe.user.ID,
+				CardID:      swipe.card.ID,
+				CardGroupID: cardGroup.ID,
+				Mode:        swipe.mode,
+				Created:     swipe.created,
+				Updated:     swipe.created,
+			})
+			suite.Require().NoError(err)
+		}
+
+		progress, err := suite.sv.ClassroomProgress(ctx, classroom.ID)
+
+		assert.NoError(t, err)
+		if assert.Len(t, progress, 2) {
+			assert.Equal(t, &model.StudentProgress{
+				UserID:        student.ID,
+				CardsReviewed: 2,
+				Accuracy:      2.0 / 3.0,
+				OverdueCount:  1, // The card reset by the unknown swipe
+			}, progress[0])
+			assert.Equal(t, &model.StudentProgress{
+				UserID:        otherStudent.ID,
+				CardsReviewed: 1,
+				Accuracy:      1,
+				OverdueCount:  0, // The swipes of the other student do not make the card due
+			}, progress[1])
+		}
+	})
+
+	suite.Run("Error_LastTeacher", func() {
+		teacher := newUser()
+		classroom := createClassroom(teacher)
+
+		_, err := suite.sv.AddUserToOrganization(ctx, teacher.ID, classroom.OrganizationID, model.OrganizationRoleStudent)
+		assert.ErrorContains(t, err, "the last teacher cannot leave organization")
+		_, err = suite.sv.RemoveUserFromOrganization(ctx, teacher.ID, classroom.OrganizationID)
+		assert.ErrorContains(t, err, "the last teacher cannot leave organization")
+	})
+
+	suite.Run("Error_AddNonMemberToClassroom", func() {
+		classroom := createClassroom(newUser())
+
+		_, err := suite.sv.AddStudentToClassroom(ctx, classroom.ID, newUser().ID)
+		assert.ErrorContains(t, err, "user is not a member of organization")
+	})
+}
+
+func TestClassroomTestSuite(t *testing.T) {
+	suite.Run(t, new(ClassroomTestSuite))
+}
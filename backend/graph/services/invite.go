@@ -0,0 +1,202 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// inviteTokenBytes is the number of random bytes of an invite token
+const inviteTokenBytes = 32
+
+type cardGroupInviteService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type CardGroupInviteService interface {
+	CreateCardGroupInvite(ctx context.Context, input model.NewCardGroupInvite, createdBy int64) (*model.CardGroupInvite, error)
+	GetCardGroupInviteByID(ctx context.Context, id int64) (*model.CardGroupInvite, error)
+	CardGroupInvites(ctx context.Context, cardGroupID int64) ([]*model.CardGroupInvite, error)
+	AcceptInvite(ctx context.Context, token string, userID int64) (*model.CardGroup, error)
+	RevokeInvite(ctx context.Context, id int64) (*bool, error)
+}
+
+func NewCardGroupInviteService(db *gorm.DB, defaultLimit int) CardGroupInviteService {
+	return &cardGroupInviteService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToCardGroupInvite(invite repository.CardgroupInvite) *model.CardGroupInvite {
+	return &model.CardGroupInvite{
+		ID:          invite.ID,
+		CardGroupID: invite.CardGroupID,
+		Role:        model.CardGroupRole(invite.Role),
+		ExpiresAt:   invite.ExpiresAt,
+		MaxUses:     invite.MaxUses,
+		Uses:        invite.Uses,
+		Revoked:     invite.RevokedAt != nil,
+		Created:     invite.Created,
+	}
+}
+
+// newInviteToken returns a random URL-safe token and the hash stored in place of it
+func newInviteToken() (string, string, error) {
+	b := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", goerr.Wrap(err, "failed to generate invite token")
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashInviteToken(token), nil
+}
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateCardGroupInvite creates an invite to the card group. The token is only returned here.
+func (s *cardGroupInviteService) CreateCardGroupInvite(ctx context.Context, input model.NewCardGroupInvite, createdBy int64) (*model.CardGroupInvite, error) {
+	if !input.Role.IsValid() {
+		return nil, goerr.New(fmt.Sprintf("invalid card group role : %s", input.Role))
+	}
+	if input.MaxUses < 1 {
+		return nil, goerr.New(fmt.Sprintf("max uses must be at least 1 : %d", input.MaxUses))
+	}
+	now := time.Now().UTC()
+	if !input.ExpiresAt.After(now) {
+		return nil, goerr.New("invite must expire in the future")
+	}
+
+	token, tokenHash, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invite := repository.CardgroupInvite{
+		CardGroupID: input.CardGroupID,
+		TokenHash:   tokenHash,
+		Role:        input.Role.String(),
+		ExpiresAt:   input.ExpiresAt.UTC(),
+		MaxUses:     input.MaxUses,
+		CreatedBy:   &createdBy,
+		Created:     now,
+		Updated:     now,
+	}
+	if err := s.db.WithContext(ctx).Create(&invite).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to create card group invite")
+	}
+
+	gqlInvite := ConvertToCardGroupInvite(invite)
+	gqlInvite.Token = &token
+	return gqlInvite, nil
+}
+
+func (s *cardGroupInviteService) GetCardGroupInviteByID(ctx context.Context, id int64) (*model.CardGroupInvite, error) {
+	var invite repository.CardgroupInvite
+	if err := s.db.WithContext(ctx).First(&invite, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, goerr.Wrap(err, fmt.Errorf("invite not found : %d", id))
+		}
+		return nil, goerr.Wrap(err, "failed to retrieve invite by ID")
+	}
+	return ConvertToCardGroupInvite(invite), nil
+}
+
+// CardGroupInvites returns the invites of the card group, newest first
+func (s *cardGroupInviteService) CardGroupInvites(ctx context.Context, cardGroupID int64) ([]*model.CardGroupInvite, error) {
+	var invites []repository.CardgroupInvite
+	if err := s.db.WithContext(ctx).
+		Where("cardgroup_id = ?", cardGroupID).
+		Order("created DESC, id DESC").
+		Limit(s.defaultLimit).
+		Find(&invites).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve card group invites")
+	}
+
+	gqlInvites := make([]*model.CardGroupInvite, 0, len(invites))
+	for _, invite := range invites {
+		gqlInvites = append(gqlInvites, ConvertToCardGroupInvite(invite))
+	}
+	return gqlInvites, nil
+}
+
+// AcceptInvite adds the user to the card group of the invite with its role through AddUserToCardGroup.
+// A member whose role is already at least the role of the invite is left as is without using the invite.
+func (s *cardGroupInviteService) AcceptInvite(ctx context.Context, token string, userID int64) (*model.CardGroup, error) {
+	var cardGroup *model.CardGroup
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invite repository.CardgroupInvite
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashInviteToken(token)).
+			First(&invite).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return goerr.Wrap(err, "invite not found")
+			}
+			return goerr.Wrap(err, "failed to find invite")
+		}
+
+		now := time.Now().UTC()
+		switch {
+		case invite.RevokedAt != nil:
+			return goerr.New(fmt.Sprintf("invite has been revoked : %d", invite.ID))
+		case !invite.ExpiresAt.After(now):
+			return goerr.New(fmt.Sprintf("invite has expired : %d", invite.ID))
+		case invite.Uses >= invite.MaxUses:
+			return goerr.New(fmt.Sprintf("invite has been used up : %d", invite.ID))
+		}
+
+		cardGroups := NewCardGroupService(tx, s.defaultLimit)
+		role := model.CardGroupRole(invite.Role)
+		current, err := cardGroups.GetCardGroupRole(ctx, invite.CardGroupID, userID)
+		if err != nil {
+			return err
+		}
+		if current != nil && CardGroupRoleAtLeast(*current, role) {
+			cardGroup, err = cardGroups.GetCardGroupByID(ctx, invite.CardGroupID)
+			return err
+		}
+
+		if err := tx.Model(&invite).Updates(map[string]interface{}{
+			"uses":    gorm.Expr("uses + 1"),
+			"updated": now,
+		}).Error; err != nil {
+			return goerr.Wrap(err, "failed to use invite")
+		}
+		cardGroup, err = cardGroups.AddUserToCardGroup(ctx, userID, invite.CardGroupID, role)
+		return err
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to accept invite")
+	}
+	return cardGroup, nil
+}
+
+// RevokeInvite makes the invite unusable. Revoking a revoked invite succeeds.
+func (s *cardGroupInviteService) RevokeInvite(ctx context.Context, id int64) (*bool, error) {
+	success := false
+	result := s.db.WithContext(ctx).
+		Model(&repository.CardgroupInvite{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"revoked_at": gorm.Expr("COALESCE(revoked_at, ?)", time.Now().UTC()),
+			"updated":    time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return &success, goerr.Wrap(result.Error, "failed to revoke invite")
+	}
+	if result.RowsAffected == 0 {
+		return &success, goerr.Wrap(fmt.Errorf("no invite found for revocation : %d", id))
+	}
+	success = true
+	return &success, nil
+}
@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type CardGroupInviteTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *CardGroupInviteTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *CardGroupInviteTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *CardGroupInviteTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+func (suite *CardGroupInviteTestSuite) TestCardGroupInviteService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	createInvite := func(role model.CardGroupRole, maxUses int, expiresAt time.Time) (*model.CardGroupInvite, *model.CardGroup, *model.User) {
+		cardGroup, owner, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		invite, err := suite.sv.CreateCardGroupInvite(ctx, model.NewCardGroupInvite{
+			CardGroupID: cardGroup.ID,
+			Role:        role,
+			ExpiresAt:   expiresAt,
+			MaxUses:     maxUses,
+		}, owner.ID)
+		suite.Require().NoError(err)
+		return invite, cardGroup, owner
+	}
+	newUser := func() *model.User {
+		_, user, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		return user
+	}
+
+	suite.Run("Normal_AcceptInvite", func() {
+		invite, cardGroup, _ := createInvite(model.CardGroupRoleEditor, 2, time.Now().Add(time.Hour))
+		assert.NotEmpty(t, *invite.Token)
+		user := newUser()
+
+		group, err := suite.sv.AcceptInvite(ctx, *invite.Token, user.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, cardGroup.ID, group.ID)
+		role, err := suite.sv.GetCardGroupRole(ctx, cardGroup.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleEditor, *role)
+		stored, err := suite.sv.GetCardGroupInviteByID(ctx, invite.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, stored.Uses)
+		assert.Nil(t, stored.Token) // Only the hash of the token is stored
+	})
+
+	suite.Run("Normal_AcceptInviteAsMember", func() {
+		invite, cardGroup, owner := createInvite(model.CardGroupRoleViewer, 1, time.Now().Add(time.Hour))
+
+		_, err := suite.sv.AcceptInvite(ctx, *invite.Token, owner.ID)
+
+		// The owner is not demoted and the invite is left for someone else
+		assert.NoError(t, err)
+		role, err := suite.sv.GetCardGroupRole(ctx, cardGroup.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardGroupRoleOwner, *role)
+		stored, err := suite.sv.GetCardGroupInviteByID(ctx, invite.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, stored.Uses)
+	})
+
+	suite.Run("Error_AcceptInviteUsedUp", func() {
+		invite, _, _ := createInvite(model.CardGroupRoleViewer, 1, time.Now().Add(time.Hour))
+
+		_, err := suite.sv.AcceptInvite(ctx, *invite.Token, newUser().ID)
+		assert.NoError(t, err)
+		_, err = suite.sv.AcceptInvite(ctx, *invite.Token, newUser().ID)
+
+		assert.ErrorContains(t, err, "invite has been used up")
+	})
+
+	suite.Run("Error_AcceptInviteExpired", func() {
+		invite, _, _ := createInvite(model.CardGroupRoleViewer, 1, time.Now().Add(time.Hour))
+		suite.db.Exec("UPDATE cardgroup_invites SET expires_at = ? WHERE id = ?", time.Now().UTC().Add(-time.Minute), invite.ID)
+
+		_, err := suite.sv.AcceptInvite(ctx, *invite.Token, newUser().ID)
+
+		assert.ErrorContains(t, err, "invite has expired")
+	})
+
+	suite.Run("Error_AcceptRevokedInvite", func() {
+		invite, _, _ := createInvite(model.CardGroupRoleViewer, 1, time.Now().Add(time.Hour))
+
+		success, err := suite.sv.RevokeInvite(ctx, invite.ID)
+		assert.NoError(t, err)
+		assert.True(t, *success)
+		_, err = suite.sv.AcceptInvite(ctx, *invite.Token, newUser().ID)
+
+		assert.ErrorContains(t, err, "invite has been revoked")
+	})
+
+	suite.Run("Error_AcceptUnknownInvite", func() {
+		_, err := suite.sv.AcceptInvite(ctx, "unknown", newUser().ID)
+
+		assert.ErrorContains(t, err, "invite not found")
+	})
+
+	suite.Run("Error_CreateInvite", func() {
+		cardGroup, owner, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+
+		_, err = suite.sv.CreateCardGroupInvite(ctx, model.NewCardGroupInvite{
+			CardGroupID: cardGroup.ID,
+			Role:        model.CardGroupRoleViewer,
+			ExpiresAt:   time.Now().Add(-time.Hour),
+			MaxUses:     1,
+		}, owner.ID)
+		assert.ErrorContains(t, err, "invite must expire in the future")
+
+		_, err = suite.sv.CreateCardGroupInvite(ctx, model.NewCardGroupInvite{
+			CardGroupID: cardGroup.ID,
+			Role:        model.CardGroupRoleViewer,
+			ExpiresAt:   time.Now().Add(time.Hour),
+			MaxUses:     0,
+		}, owner.ID)
+		assert.ErrorContains(t, err, "max uses must be at least 1")
+	})
+
+	suite.Run("Normal_CardGroupInvites", func() {
+		invite, cardGroup, owner := createInvite(model.CardGroupRoleViewer, 1, time.Now().Add(time.Hour))
+		_, err := suite.sv.CreateCardGroupInvite(ctx, model.NewCardGroupInvite{
+			CardGroupID: cardGroup.ID,
+			Role:        model.CardGroupRoleEditor,
+			ExpiresAt:   time.Now().Add(time.Hour),
+			MaxUses:     5,
+		}, owner.ID)
+		assert.NoError(t, err)
+
+		invites, err := suite.sv.CardGroupInvites(ctx, cardGroup.ID)
+
+		assert.NoError(t, err)
+		assert.Len(t, invites, 2)
+		assert.Equal(t, invite.ID, invites[1].ID)
+	})
+}
+
+func TestCardGroupInviteTestSuite(t *testing.T) {
+	suite.Run(t, new(CardGroupInviteTestSuite))
+}
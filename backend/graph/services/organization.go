@@ -0,0 +1,228 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type organizationService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, input model.NewOrganization, userID int64) (*model.Organization, error)
+	GetOrganizationByID(ctx context.Context, id int64) (*model.Organization, error)
+	OrganizationsByUser(ctx context.Context, userID int64) ([]*model.Organization, error)
+	GetOrganizationRole(ctx context.Context, organizationID int64, userID int64) (*model.OrganizationRole, error)
+	AddUserToOrganization(ctx context.Context, userID int64, organizationID int64, role model.OrganizationRole) (*model.Organization, error)
+	ChangeOrganizationRole(ctx context.Context, userID int64, organizationID int64, role model.OrganizationRole) (*model.Organization, error)
+	RemoveUserFromOrganization(ctx context.Context, userID int64, organizationID int64) (*model.Organization, error)
+}
+
+// organizationRoleRanks orders the organization roles. A role can do everything the roles of lower ranks can.
+var organizationRoleRanks = map[model.OrganizationRole]int{
+	model.OrganizationRoleStudent: 1,
+	model.OrganizationRoleTeacher: 2,
+}
+
+// OrganizationRoleAtLeast reports whether the organization role is the required role or a higher one
+func OrganizationRoleAtLeast(role model.OrganizationRole, required model.OrganizationRole) bool {
+	return organizationRoleRanks[role] >= organizationRoleRanks[required]
+}
+
+func NewOrganizationService(db *gorm.DB, defaultLimit int) OrganizationService {
+	return &organizationService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToOrganization(organization repository.Organization) *model.Organization {
+	return &model.Organization{
+		ID:      organization.ID,
+		Name:    organization.Name,
+		Created: organization.Created,
+		Updated: organization.Updated,
+	}
+}
+
+// CreateOrganization creates an organization where the user is a teacher
+func (s *organizationService) CreateOrganization(ctx context.Context, input model.NewOrganization, userID int64) (*model.Organization, error) {
+	now := time.Now().UTC()
+	organization := repository.Organization{
+		Name:    input.Name,
+		Created: now,
+		Updated: now,
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&organization).Error; err != nil {
+			return goerr.Wrap(err, "failed to create organization")
+		}
+		member := repository.OrganizationUser{
+			OrganizationID: organization.ID,
+			UserID:         userID,
+			Role:           model.OrganizationRoleTeacher.String(),
+			Created:        now,
+			Updated:        now,
+		}
+		if err := tx.Create(&member).Error; err != nil {
+			return goerr.Wrap(err, "failed to add teacher to organization")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return ConvertToOrganization(organization), nil
+}
+
+func (s *organizationService) GetOrganizationByID(ctx context.Context, id int64) (*model.Organization, error) {
+	var organization repository.Organization
+	if err := s.db.WithContext(ctx).First(&organization, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, goerr.Wrap(err, fmt.Errorf("organization not found : %d", id))
+		}
+		return nil, goerr.Wrap(err, "failed to retrieve organization by ID")
+	}
+	return ConvertToOrganization(organization), nil
+}
+
+// OrganizationsByUser returns the organizations the user is a member of
+func (s *organizationService) OrganizationsByUser(ctx context.Context, userID int64) ([]*model.Organization, error) {
+	var organizations []repository.Organization
+	if err := s.db.WithContext(ctx).
+		Joins("JOIN organization_users ON organization_users.organization_id = organizations.id").
+		Where("organization_users.user_id = ?", userID).
+		Order("organizations.id").
+		Limit(s.defaultLimit).
+		Find(&organizations).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve organizations")
+	}
+
+	gqlOrganizations := make([]*model.Organization, 0, len(organizations))
+	for _, organization := range organizations {
+		gqlOrganizations = append(gqlOrganizations, ConvertToOrganization(organization))
+	}
+	return gqlOrganizations, nil
+}
+
+// GetOrganizationRole returns the role of the user in the organization, or nil when the user is not a member
+func (s *organizationService) GetOrganizationRole(ctx context.Context, organizationID int64, userID int64) (*model.OrganizationRole, error) {
+	var member repository.OrganizationUser
+	if err := s.db.WithContext(ctx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, goerr.Wrap(err, "failed to get organization role")
+	}
+	role := model.OrganizationRole(member.Role)
+	return &role, nil
+}
+
+// AddUserToOrganization adds the user to the organization with the role, or changes the role of a member.
+// Users join through AcceptOrganizationInvite, so that nobody becomes a member without consent.
+func (s *organizationService) AddUserToOrganization(ctx context.Context, userID int64, organizationID int64, role model.OrganizationRole) (*model.Organization, error) {
+	if !role.IsValid() {
+		return nil, goerr.New(fmt.Sprintf("invalid organization role : %s", role))
+	}
+
+	var user repository.User
+	var organization repository.Organization
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("user not found : %d", userID))
+	}
+	if err := s.db.WithContext(ctx).First(&organization, organizationID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("organization not found : %d", organizationID))
+	}
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if role != model.OrganizationRoleTeacher {
+			if err := ensureAnotherTeacher(tx, organizationID, userID); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UTC()
+		member := repository.OrganizationUser{
+			OrganizationID: organizationID,
+			UserID:         userID,
+			Role:           role.String(),
+			Created:        now,
+			Updated:        now,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated"}),
+		}).Create(&member).Error
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to add user to organization")
+	}
+	return ConvertToOrganization(organization), nil
+}
+
+// ChangeOrganizationRole changes the role of a member of the organization. Unlike AddUserToOrganization,
+// it never adds a user who is not a member.
+func (s *organizationService) ChangeOrganizationRole(ctx context.Context, userID int64, organizationID int64, role model.OrganizationRole) (*model.Organization, error) {
+	current, err := s.GetOrganizationRole(ctx, organizationID, userID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to change organization role")
+	}
+	if current == nil {
+		return nil, goerr.New(fmt.Sprintf("user is not a member of organization : %d", organizationID))
+	}
+	return s.AddUserToOrganization(ctx, userID, organizationID, role)
+}
+
+// ensureAnotherTeacher fails when the user is the last teacher of the organization, which would leave
+// the organization without anyone to manage it.
+func ensureAnotherTeacher(tx *gorm.DB, organizationID int64, userID int64) error {
+	var teachers []int64
+	if err := tx.Model(&repository.OrganizationUser{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND role = ?", organizationID, model.OrganizationRoleTeacher.String()).
+		Pluck("user_id", &teachers).Error; err != nil {
+		return goerr.Wrap(err, "failed to get teachers of organization")
+	}
+	if len(teachers) == 1 && teachers[0] == userID {
+		return goerr.New(fmt.Sprintf("the last teacher cannot leave organization : %d", organizationID))
+	}
+	return nil
+}
+
+// RemoveUserFromOrganization removes the user from the organization and its classrooms. The enrollments
+// in the card groups of the classrooms are kept.
+func (s *organizationService) RemoveUserFromOrganization(ctx context.Context, userID int64, organizationID int64) (*model.Organization, error) {
+	var organization repository.Organization
+	if err := s.db.WithContext(ctx).First(&organization, organizationID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("organization not found : %d", organizationID))
+	}
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureAnotherTeacher(tx, organizationID, userID); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND classroom_id IN (?)", userID,
+			tx.Model(&repository.Classroom{}).Select("id").Where("organization_id = ?", organizationID)).
+			Delete(&repository.ClassroomStudent{}).Error; err != nil {
+			return goerr.Wrap(err, "failed to remove user from classrooms")
+		}
+		result := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Delete(&repository.OrganizationUser{})
+		if result.Error != nil {
+			return goerr.Wrap(result.Error, "failed to remove user from organization")
+		}
+		if result.RowsAffected == 0 {
+			return goerr.New(fmt.Sprintf("user is not a member of organization : %d", organizationID))
+		}
+		return nil
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to remove user from organization")
+	}
+	return ConvertToOrganization(organization), nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type organizationInviteService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type OrganizationInviteService interface {
+	CreateOrganizationInvite(ctx context.Context, input model.NewOrganizationInvite, createdBy int64) (*model.OrganizationInvite, error)
+	GetOrganizationInviteByID(ctx context.Context, id int64) (*model.OrganizationInvite, error)
+	OrganizationInvites(ctx context.Context, organizationID int64) ([]*model.OrganizationInvite, error)
+	AcceptOrganizationInvite(ctx context.Context, token string, userID int64) (*model.Organization, error)
+	RevokeOrganizationInvite(ctx context.Context, id int64) (*bool, error)
+}
+
+func NewOrganizationInviteService(db *gorm.DB, defaultLimit int) OrganizationInviteService {
+	return &organizationInviteService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToOrganizationInvite(invite repository.OrganizationInvite) *model.OrganizationInvite {
+	return &model.OrganizationInvite{
+		ID:             invite.ID,
+		OrganizationID: invite.OrganizationID,
+		Role:           model.OrganizationRole(invite.Role),
+		ExpiresAt:      invite.ExpiresAt,
+		MaxUses:        invite.MaxUses,
+		Uses:           invite.Uses,
+		Revoked:        invite.RevokedAt != nil,
+		Created:        invite.Created,
+	}
+}
+
+// CreateOrganizationInvite creates an invite to the organization. The token is only returned here.
+func (s *organizationInviteService) CreateOrganizationInvite(ctx context.Context, input model.NewOrganizationInvite, createdBy int64) (*model.OrganizationInvite, error) {
+	if !input.Role.IsValid() {
+		return nil, goerr.New(fmt.Sprintf("invalid organization role : %s", input.Role))
+	}
+	if input.MaxUses < 1 {
+		return nil, goerr.New(fmt.Sprintf("max uses must be at least 1 : %d", input.MaxUses))
+	}
+	now := time.Now().UTC()
+	if !input.ExpiresAt.After(now) {
+		return nil, goerr.New("invite must expire in the future")
+	}
+
+	token, tokenHash, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invite := repository.OrganizationInvite{
+		OrganizationID: input.OrganizationID,
+		TokenHash:      tokenHash,
+		Role:           input.Role.String(),
+		ExpiresAt:      input.ExpiresAt.UTC(),
+		MaxUses:        input.MaxUses,
+		CreatedBy:      &createdBy,
+		Created:        now,
+		Updated:        now,
+	}
+	if err := s.db.WithContext(ctx).Create(&invite).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to create organization invite")
+	}
+
+	gqlInvite := ConvertToOrganizationInvite(invite)
+	gqlInvite.Token = &token
+	return gqlInvite, nil
+}
+
+func (s *organizationInviteService) GetOrganizationInviteByID(ctx context.Context, id int64) (*model.OrganizationInvite, error) {
+	var invite repository.OrganizationInvite
+	if err := s.db.WithContext(ctx).First(&invite, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, goerr.Wrap(err, fmt.Errorf("invite not found : %d", id))
+		}
+		return nil, goerr.Wrap(err, "failed to retrieve invite by ID")
+	}
+	return ConvertToOrganizationInvite(invite), nil
+}
+
+// OrganizationInvites returns the invites of the organization, newest first
+func (s *organizationInviteService) OrganizationInvites(ctx context.Context, organizationID int64) ([]*model.OrganizationInvite, error) {
+	var invites []repository.OrganizationInvite
+	if err := s.db.WithContext(ctx).
+		Where("organization_id = ?", organizationID).
+		Order("created DESC, id DESC").
+		Limit(s.defaultLimit).
+		Find(&invites).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve organization invites")
+	}
+
+	gqlInvites := make([]*model.OrganizationInvite, 0, len(invites))
+	for _, invite := range invites {
+		gqlInvites = append(gqlInvites, ConvertToOrganizationInvite(invite))
+	}
+	return gqlInvites, nil
+}
+
+// AcceptOrganizationInvite adds the user to the organization of the invite with its role through
+// AddUserToOrganization. A member whose role is already at least the role of the invite is left as is
+// without using the invite.
+func (s *organizationInviteService) AcceptOrganizationInvite(ctx context.Context, token string, userID int64) (*model.Organization, error) {
+	var organization *model.Organization
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invite repository.OrganizationInvite
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashInviteToken(token)).
+			First(&invite).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return goerr.Wrap(err, "invite not found")
+			}
+			return goerr.Wrap(err, "failed to find invite")
+		}
+
+		now := time.Now().UTC()
+		switch {
+		case invite.RevokedAt != nil:
+			return goerr.New(fmt.Sprintf("invite has been revoked : %d", invite.ID))
+		case !invite.ExpiresAt.After(now):
+			return goerr.New(fmt.Sprintf("invite has expired : %d", invite.ID))
+		case invite.Uses >= invite.MaxUses:
+			return goerr.New(fmt.Sprintf("invite has been used up : %d", invite.ID))
+		}
+
+		organizations := NewOrganizationService(tx, s.defaultLimit)
+		role := model.OrganizationRole(invite.Role)
+		current, err := organizations.GetOrganizationRole(ctx, invite.OrganizationID, userID)
+		if err != nil {
+			return err
+		}
+		if current != nil && OrganizationRoleAtLeast(*current, role) {
+			organization, err = organizations.GetOrganizationByID(ctx, invite.OrganizationID)
+			return err
+		}
+
+		if err := tx.Model(&invite).Updates(map[string]interface{}{
+			"uses":    gorm.Expr("uses + 1"),
+			"updated": now,
+		}).Error; err != nil {
+			return goerr.Wrap(err, "failed to use invite")
+		}
+		organization, err = organizations.AddUserToOrganization(ctx, userID, invite.OrganizationID, role)
+		return err
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to accept invite")
+	}
+	return organization, nil
+}
+
+// RevokeOrganizationInvite makes the invite unusable. Revoking a revoked invite succeeds.
+func (s *organizationInviteService) RevokeOrganizationInvite(ctx context.Context, id int64) (*bool, error) {
+	success := false
+	result := s.db.WithContext(ctx).
+		Model(&repository.OrganizationInvite{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"revoked_at": gorm.Expr("COALESCE(revoked_at, ?)", time.Now().UTC()),
+			"updated":    time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return &success, goerr.Wrap(result.Error, "failed to revoke invite")
+	}
+	if result.RowsAffected == 0 {
+		return &success, goerr.Wrap(fmt.Errorf("no invite found for revocation : %d", id))
+	}
+	success = true
+	return &success, nil
+}
@@ -0,0 +1,162 @@
+package services_test
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type OrganizationInviteTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *OrganizationInviteTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *OrganizationInviteTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *OrganizationInviteTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+func (suite *OrganizationInviteTestSuite) TestOrganizationInviteService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	newUser := func() *model.User {
+		_, user, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		return user
+	}
+	createInvite := func(role model.OrganizationRole, maxUses int) (*model.OrganizationInvite, *model.Organization, *model.User) {
+		teacher := newUser()
+		organization, err := suite.sv.CreateOrganization(ctx, model.NewOrganization{Name: "Language School"}, teacher.ID)
+		suite.Require().NoError(err)
+		invite, err := suite.sv.CreateOrganizationInvite(ctx, model.NewOrganizationInvite{
+			OrganizationID: organization.ID,
+			Role:           role,
+			ExpiresAt:      time.Now().Add(time.Hour),
+			MaxUses:        maxUses,
+		}, teacher.ID)
+		suite.Require().NoError(err)
+		return invite, organization, teacher
+	}
+
+	suite.Run("Normal_AcceptOrganizationInvite", func() {
+		invite, organization, _ := createInvite(model.OrganizationRoleStudent, 2)
+		assert.NotEmpty(t, *invite.Token)
+		user := newUser()
+
+		joined, err := suite.sv.AcceptOrganizationInvite(ctx, *invite.Token, user.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, organization.ID, joined.ID)
+		role, err := suite.sv.GetOrganizationRole(ctx, organization.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrganizationRoleStudent, *role)
+		stored, err := suite.sv.GetOrganizationInviteByID(ctx, invite.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, stored.Uses)
+		assert.Nil(t, stored.Token) // Only the hash of the token is stored
+	})
+
+	suite.Run("Normal_AcceptOrganizationInviteAsTeacher", func() {
+		invite, organization, teacher := createInvite(model.OrganizationRoleStudent, 1)
+
+		_, err := suite.sv.AcceptOrganizationInvite(ctx, *invite.Token, teacher.ID)
+
+		// The teacher is not demoted and the invite is left for someone else
+		assert.NoError(t, err)
+		role, err := suite.sv.GetOrganizationRole(ctx, organization.ID, teacher.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrganizationRoleTeacher, *role)
+		stored, err := suite.sv.GetOrganizationInviteByID(ctx, invite.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, stored.Uses)
+	})
+
+	suite.Run("Error_AcceptRevokedOrganizationInvite", func() {
+		invite, _, _ := createInvite(model.OrganizationRoleStudent, 1)
+
+		success, err := suite.sv.RevokeOrganizationInvite(ctx, invite.ID)
+		assert.NoError(t, err)
+		assert.True(t, *success)
+		_, err = suite.sv.AcceptOrganizationInvite(ctx, *invite.Token, newUser().ID)
+
+		assert.ErrorContains(t, err, "invite has been revoked")
+	})
+
+	suite.Run("Error_AcceptOrganizationInviteUsedUp", func() {
+		invite, _, _ := createInvite(model.OrganizationRoleStudent, 1)
+
+		_, err := suite.sv.AcceptOrganizationInvite(ctx, *invite.Token, newUser().ID)
+		assert.NoError(t, err)
+		_, err = suite.sv.AcceptOrganizationInvite(ctx, *invite.Token, newUser().ID)
+
+		assert.ErrorContains(t, err, "invite has been used up")
+	})
+
+	suite.Run("Normal_ChangeOrganizationRole", func() {
+		invite, organization, _ := createInvite(model.OrganizationRoleStudent, 1)
+		user := newUser()
+		_, err := suite.sv.AcceptOrganizationInvite(ctx, *invite.Token, user.ID)
+		suite.Require().NoError(err)
+
+		_, err = suite.sv.ChangeOrganizationRole(ctx, user.ID, organization.ID, model.OrganizationRoleTeacher)
+
+		assert.NoError(t, err)
+		role, err := suite.sv.GetOrganizationRole(ctx, organization.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrganizationRoleTeacher, *role)
+	})
+
+	suite.Run("Error_ChangeOrganizationRoleOfNonMember", func() {
+		_, organization, _ := createInvite(model.OrganizationRoleStudent, 1)
+		user := newUser()
+
+		_, err := suite.sv.ChangeOrganizationRole(ctx, user.ID, organization.ID, model.OrganizationRoleStudent)
+
+		assert.ErrorContains(t, err, "user is not a member of organization")
+		role, err := suite.sv.GetOrganizationRole(ctx, organization.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, role)
+	})
+}
+
+func TestOrganizationInviteTestSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationInviteTestSuite))
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+
+	"github.com/m-mizutani/goerr"
+	"gorm.io/gorm"
+)
+
+type permissionService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type PermissionService interface {
+	Permissions(ctx context.Context) ([]*model.Permission, error)
+	GetPermissionsByRole(ctx context.Context, roleID int64) ([]*model.Permission, error)
+	GrantPermissionToRole(ctx context.Context, roleID int64, permissionID int64) (*model.Role, error)
+	RevokePermissionFromRole(ctx context.Context, roleID int64, permissionID int64) (*model.Role, error)
+}
+
+func NewPermissionService(db *gorm.DB, defaultLimit int) PermissionService {
+	return &permissionService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToPermission(permission repository.Permission) *model.Permission {
+	return &model.Permission{
+		ID:          permission.ID,
+		Name:        permission.Name,
+		Description: permission.Description,
+	}
+}
+
+func (s *permissionService) Permissions(ctx context.Context) ([]*model.Permission, error) {
+	var permissions []repository.Permission
+	if err := s.db.WithContext(ctx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve permissions")
+	}
+
+	gqlPermissions := make([]*model.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		gqlPermissions = append(gqlPermissions, ConvertToPermission(permission))
+	}
+	return gqlPermissions, nil
+}
+
+func (s *permissionService) GetPermissionsByRole(ctx context.Context, roleID int64) ([]*model.Permission, error) {
+	var role repository.Role
+	if err := s.db.WithContext(ctx).Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).First(&role, roleID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to get role by ID : %d", roleID))
+	}
+
+	gqlPermissions := make([]*model.Permission, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		gqlPermissions = append(gqlPermissions, ConvertToPermission(permission))
+	}
+	return gqlPermissions, nil
+}
+
+func (s *permissionService) GrantPermissionToRole(ctx context.Context, roleID int64, permissionID int64) (*model.Role, error) {
+	var role repository.Role
+	var permission repository.Permission
+	if err := s.db.WithContext(ctx).First(&role, roleID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("role not found : %d", roleID))
+	}
+	if err := s.db.WithContext(ctx).First(&permission, permissionID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("permission not found : %d", permissionID))
+	}
+	if err := s.db.WithContext(ctx).Model(&role).Association("Permissions").Append(&permission); err != nil {
+		return nil, goerr.Wrap(err, "failed to grant permission to role")
+	}
+	return ConvertToRole(role), nil
+}
+
+func (s *permissionService) RevokePermissionFromRole(ctx context.Context, roleID int64, permissionID int64) (*model.Role, error) {
+	var role repository.Role
+	if err := s.db.WithContext(ctx).Preload("Permissions", "id = ?", permissionID).First(&role, roleID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("role not found : %d", roleID))
+	}
+	if len(role.Permissions) == 0 {
+		return nil, goerr.New(fmt.Sprintf("permission %d is not granted to role : %d", permissionID, roleID))
+	}
+	if err := s.db.WithContext(ctx).Model(&role).Association("Permissions").Delete(&role.Permissions[0]); err != nil {
+		return nil, goerr.Wrap(err, "failed to revoke permission from role")
+	}
+	return ConvertToRole(role), nil
+}
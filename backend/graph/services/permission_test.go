@@ -0,0 +1,199 @@
+package services_test
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/auth"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type PermissionTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *PermissionTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *PermissionTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *PermissionTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+// permissionByName returns the seeded permission of the name
+func (suite *PermissionTestSuite) permissionByName(ctx context.Context, name string) *model.Permission {
+	permissions, err := suite.sv.Permissions(ctx)
+	suite.Require().NoError(err)
+	for _, permission := range permissions {
+		if permission.Name == name {
+			return permission
+		}
+	}
+	suite.T().Fatalf("permission %s is not seeded", name)
+	return nil
+}
+
+func (suite *PermissionTestSuite) TestPermissionService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	newUser := func() *model.User {
+		user, err := suite.sv.CreateUser(ctx, model.NewUser{
+			Name:     "Test User",
+			Email:    testutils.GetRandomEmail(8),
+			GoogleID: testutils.GenerateUUIDv7(),
+			Created:  time.Now().UTC(),
+			Updated:  time.Now().UTC(),
+		})
+		suite.Require().NoError(err)
+		return user
+	}
+
+	suite.Run("Normal_SeededPermissions", func() {
+		permissions, err := suite.sv.Permissions(ctx)
+
+		assert.NoError(t, err)
+		var names []string
+		for _, permission := range permissions {
+			names = append(names, permission.Name)
+		}
+		assert.Equal(t, []string{
+			auth.PermissionCardGroupAdmin,
+			auth.PermissionCardGroupWrite,
+			auth.PermissionDeckPublish,
+			auth.PermissionOrganizationAdmin,
+			auth.PermissionOrganizationWrite,
+			auth.PermissionRoleAdmin,
+			auth.PermissionUserAdmin,
+		}, names)
+	})
+
+	suite.Run("Normal_GrantAndRevokePermission", func() {
+		role, _ := suite.sv.CreateRole(ctx, model.NewRole{Name: "Editor"})
+		write := suite.permissionByName(ctx, auth.PermissionCardGroupWrite)
+
+		_, err := suite.sv.GrantPermissionToRole(ctx, role.ID, write.ID)
+		assert.NoError(t, err)
+		_, err = suite.sv.GrantPermissionToRole(ctx, role.ID, write.ID)
+		assert.NoError(t, err) // Granting twice is a no-op
+
+		permissions, err := suite.sv.GetPermissionsByRole(ctx, role.ID)
+		assert.NoError(t, err)
+		assert.Len(t, permissions, 1)
+		assert.Equal(t, auth.PermissionCardGroupWrite, permissions[0].Name)
+
+		_, err = suite.sv.RevokePermissionFromRole(ctx, role.ID, write.ID)
+		assert.NoError(t, err)
+		permissions, err = suite.sv.GetPermissionsByRole(ctx, role.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+
+	suite.Run("Error_RevokeNotGrantedPermission", func() {
+		role, _ := suite.sv.CreateRole(ctx, model.NewRole{Name: "Editor"})
+		publish := suite.permissionByName(ctx, auth.PermissionDeckPublish)
+
+		_, err := suite.sv.RevokePermissionFromRole(ctx, role.ID, publish.ID)
+
+		assert.Error(t, err)
+	})
+
+	suite.Run("Normal_Authorize", func() {
+		user := newUser()
+		editor, _ := suite.sv.CreateRole(ctx, model.NewRole{Name: "Editor"})
+		publisher, _ := suite.sv.CreateRole(ctx, model.NewRole{Name: "Publisher"})
+		write := suite.permissionByName(ctx, auth.PermissionCardGroupWrite)
+		publish := suite.permissionByName(ctx, auth.PermissionDeckPublish)
+		suite.sv.GrantPermissionToRole(ctx, editor.ID, write.ID)
+		suite.sv.GrantPermissionToRole(ctx, publisher.ID, write.ID)
+		suite.sv.GrantPermissionToRole(ctx, publisher.ID, publish.ID)
+		suite.sv.AssignRoleToUser(ctx, user.ID, editor.ID)
+		suite.sv.AssignRoleToUser(ctx, user.ID, publisher.ID)
+
+		permissions, err := suite.sv.UserPermissions(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{auth.PermissionCardGroupWrite, auth.PermissionDeckPublish}, permissions)
+
+		assert.NoError(t, suite.sv.Authorize(ctx, user.ID, auth.PermissionDeckPublish))
+		err = suite.sv.Authorize(ctx, user.ID, auth.PermissionUserAdmin)
+		assert.ErrorIs(t, err, auth.ErrForbidden)
+		assert.ErrorContains(t, err, "permission user:admin required")
+	})
+
+	suite.Run("Normal_NoPermissions", func() {
+		user := newUser()
+
+		permissions, err := suite.sv.UserPermissions(ctx, user.ID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+
+	suite.Run("Normal_DefaultRole", func() {
+		// Roles are cleaned up between tests, so recreate the seeded default role
+		role, _ := suite.sv.CreateRole(ctx, model.NewRole{Name: "user"})
+		suite.sv.GrantPermissionToRole(ctx, role.ID, suite.permissionByName(ctx, auth.PermissionCardGroupWrite).ID)
+
+		user := newUser()
+
+		hasRole, err := suite.sv.UserHasRole(ctx, user.ID, "user")
+		assert.NoError(t, err)
+		assert.True(t, hasRole)
+		hasPermission, err := suite.sv.HasPermission(ctx, user.ID, auth.PermissionCardGroupWrite)
+		assert.NoError(t, err)
+		assert.True(t, hasPermission)
+	})
+
+	suite.Run("Normal_DefaultRole_LinkedUser", func() {
+		suite.sv.CreateRole(ctx, model.NewRole{Name: "user"})
+		email := testutils.GetRandomEmail(8)
+		existingUser := repository.User{Name: "Existing User", Email: email, Created: time.Now().UTC(), Updated: time.Now().UTC()}
+		suite.Require().NoError(suite.db.Create(&existingUser).Error)
+
+		linkedUser, err := suite.sv.ProvisionUser(ctx, testutils.GenerateUUIDv7(), email, true, "Taro")
+		suite.Require().NoError(err)
+
+		hasRole, err := suite.sv.UserHasRole(ctx, linkedUser.ID, "user")
+		assert.NoError(t, err)
+		assert.True(t, hasRole)
+	})
+}
+
+func TestPermissionTestSuite(t *testing.T) {
+	suite.Run(t, new(PermissionTestSuite))
+}
@@ -27,6 +27,7 @@ type RoleService interface {
 	RemoveRoleFromUser(ctx context.Context, userID int64, roleID int64) (*model.User, error)
 	Roles(ctx context.Context) ([]*model.Role, error)
 	GetRolesByIDs(ctx context.Context, ids []int64) ([]*model.Role, error)
+	UserHasRole(ctx context.Context, userID int64, roleName string) (bool, error)
 }
 
 func NewRoleService(db *gorm.DB, defaultLimit int) RoleService {
@@ -230,3 +231,16 @@ func (s *roleService) GetRolesByIDs(ctx context.Context, ids []int64) ([]*model.
 
 	return gqlRoles, nil
 }
+
+// UserHasRole reports whether the user has the role, comparing role names case-insensitively
+func (s *roleService) UserHasRole(ctx context.Context, userID int64, roleName string) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).
+		Table("user_roles").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("user_roles.user_id = ? AND LOWER(roles.name) = LOWER(?)", userID, roleName).
+		Count(&count).Error; err != nil {
+		return false, goerr.Wrap(err, fmt.Errorf("failed to check role %s of user : %d", roleName, userID))
+	}
+	return count > 0, nil
+}
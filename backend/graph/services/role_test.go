@@ -203,6 +203,28 @@ func (suite *RoleTestSuite) TestRoleService() {
 		assert.Nil(t, updatedUser)
 	})
 
+	suite.Run("Normal_UserHasRole", func() {
+
+		newUser := model.NewUser{
+			Name:     "Test User",
+			Email:    testutils.GetRandomEmail(8),
+			GoogleID: testutils.GenerateUUIDv7(),
+			Created:  time.Now().UTC(),
+			Updated:  time.Now().UTC()}
+		createdUser, _ := userService.CreateUser(ctx, newUser)
+
+		createdRole, _ := roleService.CreateRole(ctx, model.NewRole{Name: "Admin"})
+		roleService.AssignRoleToUser(ctx, createdUser.ID, createdRole.ID)
+
+		hasAdmin, err := roleService.UserHasRole(ctx, createdUser.ID, "ADMIN")
+		assert.NoError(t, err)
+		assert.True(t, hasAdmin) // Role names are compared case-insensitively
+
+		hasEditor, err := roleService.UserHasRole(ctx, createdUser.ID, "EDITOR")
+		assert.NoError(t, err)
+		assert.False(t, hasEditor)
+	})
+
 	suite.Run("Normal_ListRoles", func() {
 
 		newRole1 := model.NewRole{Name: "Test Role 1"}
@@ -14,6 +14,15 @@ type Services interface {
 	UserService
 	RoleService
 	SwipeRecordService
+	PermissionService
+	Authorizer
+	CardGroupInviteService
+	CatalogService
+	CardGroupVersionService
+	CardSuggestionService
+	OrganizationService
+	OrganizationInviteService
+	ClassroomService
 	BeginTx(ctx context.Context) (*gorm.DB, error)
 }
 
@@ -23,17 +32,36 @@ type services struct {
 	*userService
 	*roleService
 	*swipeRecordService
+	*permissionService
+	*authorizer
+	*cardGroupInviteService
+	*catalogService
+	*cardGroupVersionService
+	*cardSuggestionService
+	*organizationService
+	*organizationInviteService
+	*classroomService
 	db *gorm.DB
 }
 
 func New(db *gorm.DB) Services {
 	return &services{
-		cardService:        &cardService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
-		cardGroupService:   &cardGroupService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
-		userService:        &userService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
-		roleService:        &roleService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
-		swipeRecordService: &swipeRecordService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
-		db:                 db,
+		cardService: &cardService{db: db, defaultLimit: config.Cfg.PGQueryLimit,
+			chunkSize: config.Cfg.FLDictionaryChunkSize, renameThreshold: config.Cfg.FLMirrorRenameThreshold},
+		cardGroupService:          &cardGroupService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		userService:               &userService{db: db, defaultLimit: config.Cfg.PGQueryLimit, defaultRole: config.Cfg.FLDefaultRole},
+		roleService:               &roleService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		swipeRecordService:        &swipeRecordService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		permissionService:         &permissionService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		authorizer:                &authorizer{db: db},
+		cardGroupInviteService:    &cardGroupInviteService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		catalogService:            &catalogService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		cardGroupVersionService:   &cardGroupVersionService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		cardSuggestionService:     &cardSuggestionService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		organizationService:       &organizationService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		organizationInviteService: &organizationInviteService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		classroomService:          &classroomService{db: db, defaultLimit: config.Cfg.PGQueryLimit},
+		db:                        db,
 	}
 }
 
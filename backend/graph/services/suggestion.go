@@ -0,0 +1,174 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cardSuggestionService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type CardSuggestionService interface {
+	SuggestCardEdit(ctx context.Context, input model.NewCardSuggestion, userID int64) (*model.CardSuggestion, error)
+	GetCardSuggestionByID(ctx context.Context, id int64) (*model.CardSuggestion, error)
+	PendingCardSuggestions(ctx context.Context, cardGroupID int64) ([]*model.CardSuggestion, error)
+	ApproveCardSuggestion(ctx context.Context, id int64, reviewerID int64) (*model.Card, error)
+	RejectCardSuggestion(ctx context.Context, id int64, reviewerID int64) (*model.CardSuggestion, error)
+}
+
+func NewCardSuggestionService(db *gorm.DB, defaultLimit int) CardSuggestionService {
+	return &cardSuggestionService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToCardSuggestion(suggestion repository.CardSuggestion) *model.CardSuggestion {
+	return &model.CardSuggestion{
+		ID:          suggestion.ID,
+		CardID:      suggestion.CardID,
+		CardGroupID: suggestion.CardGroupID,
+		SuggestedBy: suggestion.SuggestedBy,
+		Front:       suggestion.Front,
+		Back:        suggestion.Back,
+		Comment:     suggestion.Comment,
+		Status:      model.CardSuggestionStatus(suggestion.Status),
+		ReviewedBy:  suggestion.ReviewedBy,
+		ReviewedAt:  suggestion.ReviewedAt,
+		Created:     suggestion.Created,
+	}
+}
+
+// SuggestCardEdit creates a pending suggestion to change the front and the back of the card
+func (s *cardSuggestionService) SuggestCardEdit(ctx context.Context, input model.NewCardSuggestion, userID int64) (*model.CardSuggestion, error) {
+	var card repository.Card
+	if err := s.db.WithContext(ctx).First(&card, input.CardID).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("card does not exist : %d", input.CardID))
+	}
+	if card.Front == input.Front && card.Back == input.Back {
+		return nil, goerr.New(fmt.Sprintf("suggestion does not change the card : %d", card.ID))
+	}
+
+	now := time.Now().UTC()
+	suggestion := repository.CardSuggestion{
+		CardID:      card.ID,
+		CardGroupID: card.CardGroupID,
+		SuggestedBy: &userID,
+		Front:       input.Front,
+		Back:        input.Back,
+		Comment:     input.Comment,
+		Status:      model.CardSuggestionStatusPending.String(),
+		Created:     now,
+		Updated:     now,
+	}
+	if err := s.db.WithContext(ctx).Create(&suggestion).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to create card suggestion")
+	}
+	return ConvertToCardSuggestion(suggestion), nil
+}
+
+func (s *cardSuggestionService) GetCardSuggestionByID(ctx context.Context, id int64) (*model.CardSuggestion, error) {
+	var suggestion repository.CardSuggestion
+	if err := s.db.WithContext(ctx).First(&suggestion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, goerr.Wrap(err, fmt.Errorf("card suggestion not found : %d", id))
+		}
+		return nil, goerr.Wrap(err, "failed to retrieve card suggestion by ID")
+	}
+	return ConvertToCardSuggestion(suggestion), nil
+}
+
+// PendingCardSuggestions returns the pending suggestions for the cards of the card group, oldest first
+func (s *cardSuggestionService) PendingCardSuggestions(ctx context.Context, cardGroupID int64) ([]*model.CardSuggestion, error) {
+	var suggestions []repository.CardSuggestion
+	if err := s.db.WithContext(ctx).
+		Where("cardgroup_id = ? AND status = ?", cardGroupID, model.CardSuggestionStatusPending.String()).
+		Order("created ASC, id ASC").
+		Limit(s.defaultLimit).
+		Find(&suggestions).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve card suggestions")
+	}
+
+	gqlSuggestions := make([]*model.CardSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		gqlSuggestions = append(gqlSuggestions, ConvertToCardSuggestion(suggestion))
+	}
+	return gqlSuggestions, nil
+}
+
+// reviewCardSuggestion locks the pending suggestion, calls fn with it and records the review with the status
+func reviewCardSuggestion(tx *gorm.DB, id int64, reviewerID int64, status model.CardSuggestionStatus, fn func(suggestion *repository.CardSuggestion) error) (*repository.CardSuggestion, error) {
+	var suggestion repository.CardSuggestion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&suggestion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, goerr.Wrap(err, fmt.Errorf("card suggestion not found : %d", id))
+		}
+		return nil, goerr.Wrap(err, "failed to find card suggestion")
+	}
+	if suggestion.Status != model.CardSuggestionStatusPending.String() {
+		return nil, goerr.New(fmt.Sprintf("card suggestion has already been reviewed : %d", id))
+	}
+
+	if err := fn(&suggestion); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	suggestion.Status = status.String()
+	suggestion.ReviewedBy = &reviewerID
+	suggestion.ReviewedAt = &now
+	suggestion.Updated = now
+	if err := tx.Save(&suggestion).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to review card suggestion")
+	}
+	return &suggestion, nil
+}
+
+// ApproveCardSuggestion applies the suggestion to the card through UpdateCard, keeping the review date
+// and the interval of the card
+func (s *cardSuggestionService) ApproveCardSuggestion(ctx context.Context, id int64, reviewerID int64) (*model.Card, error) {
+	var updated *model.Card
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := reviewCardSuggestion(tx, id, reviewerID, model.CardSuggestionStatusApproved, func(suggestion *repository.CardSuggestion) error {
+			cards := &cardService{db: tx, defaultLimit: s.defaultLimit}
+			card, err := cards.GetCardByID(ctx, suggestion.CardID)
+			if err != nil {
+				return err
+			}
+			updated, err = cards.UpdateCard(ctx, card.ID, model.NewCard{
+				Front:        suggestion.Front,
+				Back:         suggestion.Back,
+				ReviewDate:   card.ReviewDate,
+				IntervalDays: &card.IntervalDays,
+				CardgroupID:  card.CardGroupID,
+			})
+			return err
+		})
+		return err
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to approve card suggestion")
+	}
+	return updated, nil
+}
+
+// RejectCardSuggestion closes the suggestion without changing the card
+func (s *cardSuggestionService) RejectCardSuggestion(ctx context.Context, id int64, reviewerID int64) (*model.CardSuggestion, error) {
+	var rejected *repository.CardSuggestion
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		rejected, err = reviewCardSuggestion(tx, id, reviewerID, model.CardSuggestionStatusRejected, func(*repository.CardSuggestion) error {
+			return nil
+		})
+		return err
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to reject card suggestion")
+	}
+	return ConvertToCardSuggestion(*rejected), nil
+}
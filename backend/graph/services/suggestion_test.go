@@ -0,0 +1,150 @@
+package services_test
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+type CardSuggestionTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *CardSuggestionTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *CardSuggestionTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *CardSuggestionTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+func (suite *CardSuggestionTestSuite) TestCardSuggestionService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	// createCard creates a card in a new card group and a viewer of the card group
+	createCard := func() (*model.Card, *model.User, *model.User) {
+		cardGroup, owner, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		card, err := suite.sv.CreateCard(ctx, model.NewCard{
+			Front:       "Bonjuor",
+			Back:        "Hello",
+			ReviewDate:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			CardgroupID: cardGroup.ID,
+		})
+		suite.Require().NoError(err)
+		_, viewer, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		_, err = suite.sv.AddUserToCardGroup(ctx, viewer.ID, cardGroup.ID, model.CardGroupRoleViewer)
+		suite.Require().NoError(err)
+		return card, owner, viewer
+	}
+
+	suite.Run("Normal_ApproveCardSuggestion", func() {
+		card, owner, viewer := createCard()
+
+		suggestion, err := suite.sv.SuggestCardEdit(ctx, model.NewCardSuggestion{
+			CardID:  card.ID,
+			Front:   "Bonjour",
+			Back:    "Hello",
+			Comment: "Typo",
+		}, viewer.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardSuggestionStatusPending, suggestion.Status)
+		assert.Equal(t, card.CardGroupID, suggestion.CardGroupID)
+
+		pending, err := suite.sv.PendingCardSuggestions(ctx, card.CardGroupID)
+		assert.NoError(t, err)
+		if assert.Len(t, pending, 1) {
+			assert.Equal(t, "Typo", pending[0].Comment)
+		}
+
+		updated, err := suite.sv.ApproveCardSuggestion(ctx, suggestion.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bonjour", updated.Front)
+		assert.True(t, card.ReviewDate.Equal(updated.ReviewDate)) // Progress is kept
+
+		approved, err := suite.sv.GetCardSuggestionByID(ctx, suggestion.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardSuggestionStatusApproved, approved.Status)
+		assert.Equal(t, owner.ID, *approved.ReviewedBy)
+
+		pending, err = suite.sv.PendingCardSuggestions(ctx, card.CardGroupID)
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	suite.Run("Normal_RejectCardSuggestion", func() {
+		card, owner, viewer := createCard()
+
+		suggestion, err := suite.sv.SuggestCardEdit(ctx, model.NewCardSuggestion{
+			CardID: card.ID,
+			Front:  "Bonjour",
+			Back:   "Goodbye",
+		}, viewer.ID)
+		suite.Require().NoError(err)
+
+		rejected, err := suite.sv.RejectCardSuggestion(ctx, suggestion.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CardSuggestionStatusRejected, rejected.Status)
+		assert.NotNil(t, rejected.ReviewedAt)
+
+		unchanged, err := suite.sv.GetCardByID(ctx, card.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bonjuor", unchanged.Front)
+
+		// A reviewed suggestion cannot be reviewed again
+		_, err = suite.sv.ApproveCardSuggestion(ctx, suggestion.ID, owner.ID)
+		assert.ErrorContains(t, err, "already been reviewed")
+	})
+
+	suite.Run("Error_SuggestSameCard", func() {
+		card, _, viewer := createCard()
+
+		_, err := suite.sv.SuggestCardEdit(ctx, model.NewCardSuggestion{
+			CardID: card.ID,
+			Front:  card.Front,
+			Back:   card.Back,
+		}, viewer.ID)
+		assert.ErrorContains(t, err, "does not change the card")
+	})
+}
+
+func TestCardSuggestionTestSuite(t *testing.T) {
+	suite.Run(t, new(CardSuggestionTestSuite))
+}
@@ -20,6 +20,9 @@ const (
 	MAYBE     = 3
 )
 
+// ReviewIntervalDays are the intervals between reviews of a card, which grow with each known review
+var ReviewIntervalDays = []int{1, 3, 7, 14, 30}
+
 type swipeRecordService struct {
 	db           *gorm.DB
 	defaultLimit int
@@ -28,6 +31,7 @@ type swipeRecordService struct {
 type SwipeRecordService interface {
 	GetSwipeRecordByID(ctx context.Context, id int64) (*model.SwipeRecord, error)
 	CreateSwipeRecord(ctx context.Context, input model.NewSwipeRecord) (*model.SwipeRecord, error)
+	CreateSwipeRecords(ctx context.Context, inputs []model.NewSwipeRecord) error
 	UpdateSwipeRecord(ctx context.Context, id int64, input model.NewSwipeRecord) (*model.SwipeRecord, error)
 	DeleteSwipeRecord(ctx context.Context, id int64) (*bool, error)
 	SwipeRecords(ctx context.Context) ([]*model.SwipeRecord, error)
@@ -98,6 +102,26 @@ func (s *swipeRecordService) CreateSwipeRecord(ctx context.Context, input model.
 	return ConvertToSwipeRecord(*gormSwipeRecord), nil
 }
 
+// CreateSwipeRecords creates the swipe records in batches, e.g. for the history of imported cards
+func (s *swipeRecordService) CreateSwipeRecords(ctx context.Context, inputs []model.NewSwipeRecord) error {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	gormSwipeRecords := make([]*repository.SwipeRecord, 0, len(inputs))
+	for _, input := range inputs {
+		gormSwipeRecords = append(gormSwipeRecords, ConvertToGormSwipeRecordFromNew(input))
+	}
+	result := s.db.WithContext(ctx).CreateInBatches(gormSwipeRecords, s.defaultLimit)
+	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "foreign key constraint") {
+			return goerr.Wrap(fmt.Errorf("invalid swipe ID or card ID"), result.Error)
+		}
+		return goerr.Wrap(result.Error, "failed to create swipe records")
+	}
+	return nil
+}
+
 func (s *swipeRecordService) UpdateSwipeRecord(ctx context.Context, id int64, input model.NewSwipeRecord) (*model.SwipeRecord, error) {
 	var swipeRecord repository.SwipeRecord
 	if err := s.db.WithContext(ctx).First(&swipeRecord, id).Error; err != nil {
@@ -3,10 +3,13 @@ package services
 import (
 	"backend/graph/db"
 	"backend/graph/model"
+	"backend/pkg/config"
 	"context"
 	"fmt"
 	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
 	"time"
 )
@@ -14,6 +17,8 @@ import (
 type userService struct {
 	db           *gorm.DB
 	defaultLimit int
+	// defaultRole is the name of the role given to new users
+	defaultRole string
 }
 
 type UserService interface {
@@ -25,10 +30,24 @@ type UserService interface {
 	DeleteUser(ctx context.Context, id int64) (*bool, error)
 	PaginatedUsersByRole(ctx context.Context, roleID int64, first *int, after *int64, last *int, before *int64) (*model.UserConnection, error)
 	GetUsersByIDs(ctx context.Context, ids []int64) ([]*model.User, error)
+	ProvisionUser(ctx context.Context, googleID string, email string, emailVerified bool, name string) (*model.User, error)
 }
 
 func NewUserService(db *gorm.DB, defaultLimit int) UserService {
-	return &userService{db: db, defaultLimit: defaultLimit}
+	return &userService{db: db, defaultLimit: defaultLimit, defaultRole: config.Cfg.FLDefaultRole}
+}
+
+// assignDefaultRole gives the default role to the user. Nothing is assigned when the role does not exist.
+func (s *userService) assignDefaultRole(tx *gorm.DB, userID int64) error {
+	if s.defaultRole == "" {
+		return nil
+	}
+	if err := tx.Exec(`INSERT INTO user_roles (user_id, role_id)
+		SELECT ?, id FROM roles WHERE name = ?
+		ON CONFLICT DO NOTHING`, userID, s.defaultRole).Error; err != nil {
+		return goerr.Wrap(err, fmt.Sprintf("failed to assign default role %s to user : %d", s.defaultRole, userID))
+	}
+	return nil
 }
 
 func ConvertToGormUserFromNew(input model.NewUser) *db.User {
@@ -74,12 +93,16 @@ func (s *userService) GetUserByID(ctx context.Context, id int64) (*model.User, e
 
 func (s *userService) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
 	gormUser := ConvertToGormUserFromNew(input)
-	result := s.db.WithContext(ctx).Create(gormUser)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "unique constraint") {
-			return nil, goerr.Wrap(fmt.Errorf("user already exists"), result.Error)
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(gormUser).Error; err != nil {
+			return err
+		}
+		return s.assignDefaultRole(tx, gormUser.ID)
+	}); err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			return nil, goerr.Wrap(fmt.Errorf("user already exists"), err)
 		}
-		return nil, goerr.Wrap(result.Error, "failed to create user")
+		return nil, goerr.Wrap(err, "failed to create user")
 	}
 	return ConvertToUser(*gormUser), nil
 }
@@ -203,3 +226,60 @@ func (s *userService) GetUsersByIDs(ctx context.Context, ids []int64) ([]*model.
 	}
 	return gqlUsers, nil
 }
+
+// ProvisionUser returns the user of the identity at the identity provider, stored in google_id.
+// On first login, a user with the same verified email and no identity yet is linked to it, and a new
+// user is created otherwise. A user with the same email and another identity is never taken over, and
+// an unverified email never links a user.
+func (s *userService) ProvisionUser(ctx context.Context, googleID string, email string, emailVerified bool, name string) (*model.User, error) {
+	if googleID == "" {
+		return nil, goerr.New("identity is required to provision user")
+	}
+
+	var user db.User
+	err := s.db.WithContext(ctx).Where("google_id = ?", googleID).First(&user).Error
+	if err == nil {
+		return ConvertToUser(user), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, goerr.Wrap(err, "failed to find user by identity")
+	}
+	if email == "" {
+		return nil, goerr.New("email is required to provision user")
+	}
+
+	now := time.Now().UTC()
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if emailVerified {
+			var linkedUser db.User
+			result := tx.Model(&linkedUser).
+				Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+				Where("email = ? AND (google_id IS NULL OR google_id = '')", email).
+				Updates(map[string]interface{}{"google_id": googleID, "updated": now})
+			if result.Error != nil {
+				return goerr.Wrap(result.Error, "failed to link user")
+			}
+			if result.RowsAffected > 0 {
+				return s.assignDefaultRole(tx, linkedUser.ID)
+			}
+		}
+
+		newUser := db.User{Name: name, Email: email, GoogleID: googleID, Created: now, Updated: now}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newUser)
+		if result.Error != nil {
+			return goerr.Wrap(result.Error, "failed to create user")
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return s.assignDefaultRole(tx, newUser.ID)
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to provision user")
+	}
+
+	// Read back the user, which a concurrent first login may have provisioned instead
+	if err := s.db.WithContext(ctx).Where("google_id = ?", googleID).First(&user).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Sprintf("failed to provision user : %s", email))
+	}
+	return ConvertToUser(user), nil
+}
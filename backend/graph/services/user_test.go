@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	repository "backend/graph/db"
 	"backend/graph/model"
 	"backend/graph/services"
 	"backend/testutils"
@@ -210,6 +211,64 @@ func (suite *UserTestSuite) TestUserService() {
 		assert.True(t, *deleted)
 	})
 
+	suite.Run("Normal_ProvisionUser_Create", func() {
+		email := testutils.GetRandomEmail(8)
+		googleID := testutils.GenerateUUIDv7()
+
+		createdUser, err := userService.ProvisionUser(ctx, googleID, email, true, "Taro")
+		assert.NoError(t, err)
+		assert.Equal(t, "Taro", createdUser.Name)
+		assert.Equal(t, googleID, createdUser.GoogleID)
+
+		// Later logins find the same user
+		foundUser, err := userService.ProvisionUser(ctx, googleID, email, true, "Taro")
+		assert.NoError(t, err)
+		assert.Equal(t, createdUser.ID, foundUser.ID)
+	})
+
+	suite.Run("Normal_ProvisionUser_Link", func() {
+		email := testutils.GetRandomEmail(8)
+		existingUser := repository.User{Name: "Existing User", Email: email, Created: time.Now().UTC(), Updated: time.Now().UTC()}
+		assert.NoError(t, suite.db.Create(&existingUser).Error)
+		googleID := testutils.GenerateUUIDv7()
+
+		linkedUser, err := userService.ProvisionUser(ctx, googleID, email, true, "Taro")
+		assert.NoError(t, err)
+		assert.Equal(t, existingUser.ID, linkedUser.ID)
+		assert.Equal(t, "Existing User", linkedUser.Name)
+		assert.Equal(t, googleID, linkedUser.GoogleID)
+	})
+
+	suite.Run("Error_ProvisionUser_LinkWithUnverifiedEmail", func() {
+		email := testutils.GetRandomEmail(8)
+		existingUser := repository.User{Name: "Existing User", Email: email, Created: time.Now().UTC(), Updated: time.Now().UTC()}
+		assert.NoError(t, suite.db.Create(&existingUser).Error)
+
+		provisionedUser, err := userService.ProvisionUser(ctx, testutils.GenerateUUIDv7(), email, false, "Taro")
+		assert.Error(t, err)
+		assert.Nil(t, provisionedUser)
+
+		// The existing user keeps having no identity
+		assert.NoError(t, suite.db.First(&existingUser, existingUser.ID).Error)
+		assert.Empty(t, existingUser.GoogleID)
+	})
+
+	suite.Run("Error_ProvisionUser_EmailOfAnotherIdentity", func() {
+		email := testutils.GetRandomEmail(8)
+		_, err := userService.ProvisionUser(ctx, testutils.GenerateUUIDv7(), email, true, "Taro")
+		assert.NoError(t, err)
+
+		provisionedUser, err := userService.ProvisionUser(ctx, testutils.GenerateUUIDv7(), email, true, "Taro")
+		assert.Error(t, err)
+		assert.Nil(t, provisionedUser)
+	})
+
+	suite.Run("Error_ProvisionUser_WithoutEmail", func() {
+		provisionedUser, err := userService.ProvisionUser(ctx, testutils.GenerateUUIDv7(), "", true, "Taro")
+		assert.ErrorContains(t, err, "email is required to provision user")
+		assert.Nil(t, provisionedUser)
+	})
+
 	suite.Run("Normal_ListUsers", func() {
 
 		// Create a role
@@ -0,0 +1,421 @@
+package services
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// cardGroupVersionService manages the versions of published card groups and updates of their copies
+type cardGroupVersionService struct {
+	db           *gorm.DB
+	defaultLimit int
+}
+
+type CardGroupVersionService interface {
+	PublishCardGroupVersion(ctx context.Context, cardGroupID int64, note string) (*model.CardGroupVersion, error)
+	CardGroupVersions(ctx context.Context, cardGroupID int64) ([]*model.CardGroupVersion, error)
+	CardGroupUpdate(ctx context.Context, cardGroupID int64) (*model.CardGroupUpdate, error)
+	ApplyCardGroupUpdate(ctx context.Context, cardGroupID int64) (*model.CardGroup, error)
+}
+
+func NewCardGroupVersionService(db *gorm.DB, defaultLimit int) CardGroupVersionService {
+	return &cardGroupVersionService{db: db, defaultLimit: defaultLimit}
+}
+
+func ConvertToCardGroupVersion(version repository.CardgroupVersion, cardCount int) *model.CardGroupVersion {
+	return &model.CardGroupVersion{
+		ID:          version.ID,
+		CardGroupID: version.CardGroupID,
+		Version:     version.Version,
+		Note:        version.Note,
+		CardCount:   cardCount,
+		Created:     version.Created,
+	}
+}
+
+func ConvertToVersionCard(card repository.CardgroupVersionCard) *model.VersionCard {
+	return &model.VersionCard{
+		SourceCardID: card.CardID,
+		Front:        card.Front,
+		Back:         card.Back,
+		Reading:      card.Reading,
+	}
+}
+
+// snapshotCardGroup stores the current cards of the card group as its next version
+func snapshotCardGroup(tx *gorm.DB, cardGroupID int64, note string) (*repository.CardgroupVersion, int, error) {
+	var latest int
+	if err := tx.Model(&repository.CardgroupVersion{}).
+		Where("cardgroup_id = ?", cardGroupID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return nil, 0, goerr.Wrap(err, "failed to get latest version")
+	}
+
+	now := time.Now().UTC()
+	version := repository.CardgroupVersion{
+		CardGroupID: cardGroupID,
+		Version:     latest + 1,
+		Note:        note,
+		Created:     now,
+		Updated:     now,
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		return nil, 0, goerr.Wrap(err, "failed to create version")
+	}
+
+	result := tx.Exec(`INSERT INTO cardgroup_version_cards
+			(version_id, card_id, front, back, reading, senses, tags, frequency_rank)
+		SELECT ?, id, front, back, reading, senses, tags, frequency_rank
+		FROM cards WHERE cardgroup_id = ?`, version.ID, cardGroupID)
+	if result.Error != nil {
+		return nil, 0, goerr.Wrap(result.Error, "failed to snapshot cards")
+	}
+	return &version, int(result.RowsAffected), nil
+}
+
+// latestVersion returns the latest version of the card group, or nil when it has never been published
+func latestVersion(tx *gorm.DB, cardGroupID int64) (*repository.CardgroupVersion, error) {
+	var version repository.CardgroupVersion
+	if err := tx.Where("cardgroup_id = ?", cardGroupID).Order("version DESC").First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, goerr.Wrap(err, "failed to get latest version")
+	}
+	return &version, nil
+}
+
+// versionCards returns the cards of the version of the card group by their source card IDs
+func versionCards(tx *gorm.DB, cardGroupID int64, version int) (map[int64]repository.CardgroupVersionCard, error) {
+	var cards []repository.CardgroupVersionCard
+	if err := tx.Joins("JOIN cardgroup_versions ON cardgroup_versions.id = cardgroup_version_cards.version_id").
+		Where("cardgroup_versions.cardgroup_id = ? AND cardgroup_versions.version = ?", cardGroupID, version).
+		Find(&cards).Error; err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to get cards of version %d", version))
+	}
+	byID := make(map[int64]repository.CardgroupVersionCard, len(cards))
+	for _, card := range cards {
+		byID[card.CardID] = card
+	}
+	return byID, nil
+}
+
+// sameVersionCard reports whether the contents of the cards are the same
+func sameVersionCard(a, b repository.CardgroupVersionCard) bool {
+	return a.Front == b.Front && a.Back == b.Back && a.Reading == b.Reading &&
+		slices.Equal(a.Senses, b.Senses) && slices.Equal(a.Tags, b.Tags) &&
+		((a.FrequencyRank == nil && b.FrequencyRank == nil) ||
+			(a.FrequencyRank != nil && b.FrequencyRank != nil && *a.FrequencyRank == *b.FrequencyRank))
+}
+
+// cardGroupChanges holds the differences between two versions, ordered by source card ID.
+// conflicts are the changes the copy cannot take, which are not in the other lists.
+type cardGroupChanges struct {
+	added     []repository.CardgroupVersionCard
+	changed   [][2]repository.CardgroupVersionCard
+	removed   []repository.CardgroupVersionCard
+	conflicts []repository.CardgroupVersionCard
+}
+
+func diffVersionCards(current, latest map[int64]repository.CardgroupVersionCard) cardGroupChanges {
+	var changes cardGroupChanges
+	for id, card := range latest {
+		old, ok := current[id]
+		if !ok {
+			changes.added = append(changes.added, card)
+		} else if !sameVersionCard(old, card) {
+			changes.changed = append(changes.changed, [2]repository.CardgroupVersionCard{old, card})
+		}
+	}
+	for id, card := range current {
+		if _, ok := latest[id]; !ok {
+			changes.removed = append(changes.removed, card)
+		}
+	}
+
+	byCardID := func(a, b repository.CardgroupVersionCard) int { return int(a.CardID - b.CardID) }
+	slices.SortFunc(changes.added, byCardID)
+	slices.SortFunc(changes.removed, byCardID)
+	slices.SortFunc(changes.changed, func(a, b [2]repository.CardgroupVersionCard) int { return byCardID(a[1], b[1]) })
+	return changes
+}
+
+// versionCardOf returns the contents of the card of a copy as a card of the version
+func versionCardOf(card repository.Card) repository.CardgroupVersionCard {
+	return repository.CardgroupVersionCard{
+		Front:         card.Front,
+		Back:          card.Back,
+		Reading:       card.Reading,
+		Senses:        card.Senses,
+		Tags:          card.Tags,
+		FrequencyRank: card.FrequencyRank,
+	}
+}
+
+// separateConflicts moves the changes the copy cannot take to the conflicts: changed and removed cards
+// edited in the copy since its version, which keep the local edits, and changed and added cards whose
+// front another card of the copy already uses
+func separateConflicts(tx *gorm.DB, cardGroupID int64, changes *cardGroupChanges) error {
+	var cards []repository.Card
+	if err := tx.Where("cardgroup_id = ?", cardGroupID).Find(&cards).Error; err != nil {
+		return goerr.Wrap(err, fmt.Errorf("failed to get cards of card group : %d", cardGroupID))
+	}
+	bySourceID := make(map[int64]repository.Card, len(cards))
+	fronts := make(map[string]bool, len(cards))
+	for _, card := range cards {
+		if card.SourceCardID != nil {
+			bySourceID[*card.SourceCardID] = card
+		}
+		fronts[card.Front] = true
+	}
+	edited := func(old repository.CardgroupVersionCard) bool {
+		card, ok := bySourceID[old.CardID]
+		return ok && !sameVersionCard(versionCardOf(card), old)
+	}
+
+	// Fronts are freed and taken in the order the update is applied
+	removed := changes.removed[:0]
+	for _, card := range changes.removed {
+		if edited(card) {
+			changes.conflicts = append(changes.conflicts, card)
+			continue
+		}
+		if local, ok := bySourceID[card.CardID]; ok {
+			delete(fronts, local.Front)
+		}
+		removed = append(removed, card)
+	}
+	changes.removed = removed
+
+	changed := changes.changed[:0]
+	for _, change := range changes.changed {
+		old, card := change[0], change[1]
+		if edited(old) || (card.Front != old.Front && fronts[card.Front]) {
+			changes.conflicts = append(changes.conflicts, card)
+			continue
+		}
+		if local, ok := bySourceID[card.CardID]; ok {
+			delete(fronts, local.Front)
+			fronts[card.Front] = true
+		}
+		changed = append(changed, change)
+	}
+	changes.changed = changed
+
+	added := changes.added[:0]
+	for _, card := range changes.added {
+		if fronts[card.Front] {
+			changes.conflicts = append(changes.conflicts, card)
+			continue
+		}
+		fronts[card.Front] = true
+		added = append(added, card)
+	}
+	changes.added = added
+
+	slices.SortFunc(changes.conflicts, func(a, b repository.CardgroupVersionCard) int { return int(a.CardID - b.CardID) })
+	return nil
+}
+
+// PublishCardGroupVersion snapshots the cards of the published card group as its next version
+func (s *cardGroupVersionService) PublishCardGroupVersion(ctx context.Context, cardGroupID int64, note string) (*model.CardGroupVersion, error) {
+	var published *model.CardGroupVersion
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cardGroup repository.Cardgroup
+		if err := tx.First(&cardGroup, cardGroupID).Error; err != nil {
+			return goerr.Wrap(err, fmt.Errorf("card group not found : %d", cardGroupID))
+		}
+		if !cardGroup.IsPublic {
+			return goerr.New(fmt.Sprintf("card group is not published : %d", cardGroupID))
+		}
+
+		version, count, err := snapshotCardGroup(tx, cardGroupID, note)
+		if err != nil {
+			return err
+		}
+		published = ConvertToCardGroupVersion(*version, count)
+		return nil
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to publish card group version")
+	}
+	return published, nil
+}
+
+// CardGroupVersions returns the versions of the card group, latest first
+func (s *cardGroupVersionService) CardGroupVersions(ctx context.Context, cardGroupID int64) ([]*model.CardGroupVersion, error) {
+	var versions []struct {
+		repository.CardgroupVersion
+		CardCount int `gorm:"column:card_count"`
+	}
+	if err := s.db.WithContext(ctx).
+		Model(&repository.CardgroupVersion{}).
+		Select("cardgroup_versions.*, (SELECT COUNT(*) FROM cardgroup_version_cards WHERE version_id = cardgroup_versions.id) AS card_count").
+		Where("cardgroup_id = ?", cardGroupID).
+		Order("version DESC").
+		Limit(s.defaultLimit).
+		Scan(&versions).Error; err != nil {
+		return nil, goerr.Wrap(err, "failed to retrieve card group versions")
+	}
+
+	gqlVersions := make([]*model.CardGroupVersion, 0, len(versions))
+	for _, version := range versions {
+		gqlVersions = append(gqlVersions, ConvertToCardGroupVersion(version.CardgroupVersion, version.CardCount))
+	}
+	return gqlVersions, nil
+}
+
+// cardGroupUpdate returns the copy and the changes of its source since its version,
+// or nil changes when the copy is up to date
+func cardGroupUpdate(tx *gorm.DB, cardGroupID int64) (*repository.Cardgroup, *repository.CardgroupVersion, *cardGroupChanges, error) {
+	var cardGroup repository.Cardgroup
+	if err := tx.First(&cardGroup, cardGroupID).Error; err != nil {
+		return nil, nil, nil, goerr.Wrap(err, fmt.Errorf("card group not found : %d", cardGroupID))
+	}
+	if cardGroup.SourceCardGroupID == nil {
+		return nil, nil, nil, goerr.New(fmt.Sprintf("card group is neither a subscription nor a fork : %d", cardGroupID))
+	}
+
+	latest, err := latestVersion(tx, *cardGroup.SourceCardGroupID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if latest == nil || (cardGroup.SourceVersion != nil && *cardGroup.SourceVersion >= latest.Version) {
+		return &cardGroup, latest, nil, nil
+	}
+
+	current := map[int64]repository.CardgroupVersionCard{}
+	if cardGroup.SourceVersion != nil {
+		if current, err = versionCards(tx, *cardGroup.SourceCardGroupID, *cardGroup.SourceVersion); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	latestCards, err := versionCards(tx, *cardGroup.SourceCardGroupID, latest.Version)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	changes := diffVersionCards(current, latestCards)
+	if err := separateConflicts(tx, cardGroup.ID, &changes); err != nil {
+		return nil, nil, nil, err
+	}
+	return &cardGroup, latest, &changes, nil
+}
+
+// CardGroupUpdate returns the changes of the source of the subscription or fork since its version
+func (s *cardGroupVersionService) CardGroupUpdate(ctx context.Context, cardGroupID int64) (*model.CardGroupUpdate, error) {
+	cardGroup, latest, changes, err := cardGroupUpdate(s.db.WithContext(ctx), cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card group update")
+	}
+	if changes == nil {
+		return nil, nil
+	}
+
+	update := &model.CardGroupUpdate{
+		CardGroupID:    cardGroup.ID,
+		CurrentVersion: cardGroup.SourceVersion,
+		LatestVersion:  latest.Version,
+		Added:          make([]*model.VersionCard, 0, len(changes.added)),
+		Changed:        make([]*model.VersionCardChange, 0, len(changes.changed)),
+		Removed:        make([]*model.VersionCard, 0, len(changes.removed)),
+		Conflicts:      make([]*model.VersionCard, 0, len(changes.conflicts)),
+	}
+	for _, card := range changes.added {
+		update.Added = append(update.Added, ConvertToVersionCard(card))
+	}
+	for _, change := range changes.changed {
+		update.Changed = append(update.Changed, &model.VersionCardChange{
+			Before: ConvertToVersionCard(change[0]),
+			After:  ConvertToVersionCard(change[1]),
+		})
+	}
+	for _, card := range changes.removed {
+		update.Removed = append(update.Removed, ConvertToVersionCard(card))
+	}
+	for _, card := range changes.conflicts {
+		update.Conflicts = append(update.Conflicts, ConvertToVersionCard(card))
+	}
+	return update, nil
+}
+
+// ApplyCardGroupUpdate brings the subscription or fork to the latest version of its source. Changed
+// cards keep their review date and interval, added cards start as new cards. Conflicting changes are
+// skipped, so cards edited in a fork keep the local edits.
+func (s *cardGroupVersionService) ApplyCardGroupUpdate(ctx context.Context, cardGroupID int64) (*model.CardGroup, error) {
+	var updated *model.CardGroup
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cardGroup, latest, changes, err := cardGroupUpdate(tx, cardGroupID)
+		if err != nil {
+			return err
+		}
+		if changes == nil {
+			updated = ConvertToCardGroup(*cardGroup)
+			return nil
+		}
+
+		now := time.Now().UTC()
+		for _, card := range changes.removed {
+			if err := tx.Where("cardgroup_id = ? AND source_card_id = ?", cardGroup.ID, card.CardID).
+				Delete(&repository.Card{}).Error; err != nil {
+				return goerr.Wrap(err, fmt.Errorf("failed to remove card : %d", card.CardID))
+			}
+		}
+		for _, change := range changes.changed {
+			card := change[1]
+			if err := tx.Model(&repository.Card{}).
+				Where("cardgroup_id = ? AND source_card_id = ?", cardGroup.ID, card.CardID).
+				Updates(map[string]interface{}{
+					"front":          card.Front,
+					"back":           card.Back,
+					"reading":        card.Reading,
+					"senses":         card.Senses,
+					"tags":           card.Tags,
+					"frequency_rank": card.FrequencyRank,
+					"updated":        now,
+				}).Error; err != nil {
+				return goerr.Wrap(err, fmt.Errorf("failed to change card : %d", card.CardID))
+			}
+		}
+		for _, card := range changes.added {
+			newCard := repository.Card{
+				Front:         card.Front,
+				Back:          card.Back,
+				Reading:       card.Reading,
+				Senses:        card.Senses,
+				Tags:          card.Tags,
+				FrequencyRank: card.FrequencyRank,
+				ReviewDate:    now,
+				IntervalDays:  1,
+				CardGroupID:   cardGroup.ID,
+				SourceCardID:  &card.CardID,
+				Created:       now,
+				Updated:       now,
+			}
+			if err := tx.Create(&newCard).Error; err != nil {
+				return goerr.Wrap(err, fmt.Errorf("failed to add card : %d", card.CardID))
+			}
+		}
+
+		cardGroup.SourceVersion = &latest.Version
+		cardGroup.Updated = now
+		if err := tx.Model(cardGroup).Updates(map[string]interface{}{
+			"source_version": latest.Version,
+			"updated":        now,
+		}).Error; err != nil {
+			return goerr.Wrap(err, "failed to update version of card group")
+		}
+		updated = ConvertToCardGroup(*cardGroup)
+		return nil
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to apply card group update")
+	}
+	return updated, nil
+}
@@ -0,0 +1,262 @@
+package services_test
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type CardGroupVersionTestSuite struct {
+	suite.Suite
+	db      *gorm.DB
+	sv      services.Services
+	cleanup func()
+}
+
+func (suite *CardGroupVersionTestSuite) SetupSuite() {
+	// Setup context
+	ctx := context.Background()
+
+	// Set up the test database
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "dbname")
+	if err != nil {
+		suite.T().Fatalf("Failed to setup test database: %+v", err)
+	}
+	suite.cleanup = func() {
+		cleanup(migrationFilePath)
+	}
+
+	// Run migrations
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		suite.T().Fatalf("Failed to run migrations: %+v", err)
+	}
+
+	// Setup service
+	suite.db = pg.GetDB()
+	suite.sv = services.New(suite.db)
+}
+
+func (suite *CardGroupVersionTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *CardGroupVersionTestSuite) SetupSubTest() {
+	t := suite.T()
+	t.Helper()
+	testutils.RunServersTest(t, suite.db, nil)
+}
+
+func (suite *CardGroupVersionTestSuite) TestCardGroupVersionService() {
+	ctx := context.Background()
+	t := suite.T()
+	t.Helper()
+
+	// publishDeck creates a public card group with the cards
+	publishDeck := func(cards int) (*model.CardGroup, []*model.Card) {
+		cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		created := make([]*model.Card, 0, cards)
+		for i := 0; i < cards; i++ {
+			card, err := suite.sv.CreateCard(ctx, model.NewCard{
+				Front:       "Front " + strconv.Itoa(i),
+				Back:        "Back " + strconv.Itoa(i),
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: cardGroup.ID,
+			})
+			suite.Require().NoError(err)
+			created = append(created, card)
+		}
+		published, err := suite.sv.PublishCardGroup(ctx, model.PublishCardGroup{
+			CardGroupID:    cardGroup.ID,
+			Description:    "Deck",
+			SourceLanguage: "ja",
+			TargetLanguage: "en",
+		})
+		suite.Require().NoError(err)
+		return published, created
+	}
+	newUser := func() *model.User {
+		_, user, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+		return user
+	}
+
+	suite.Run("Normal_PublishCardGroupVersion", func() {
+		deck, cards := publishDeck(2)
+
+		_, err := suite.sv.DeleteCard(ctx, cards[0].ID)
+		suite.Require().NoError(err)
+		version, err := suite.sv.PublishCardGroupVersion(ctx, deck.ID, "Remove a card")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, version.Version)
+		assert.Equal(t, "Remove a card", version.Note)
+		assert.Equal(t, 1, version.CardCount)
+
+		versions, err := suite.sv.CardGroupVersions(ctx, deck.ID)
+		assert.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Equal(t, 2, versions[0].Version)
+		assert.Equal(t, 2, versions[1].CardCount)
+	})
+
+	suite.Run("Normal_ApplyCardGroupUpdate", func() {
+		deck, cards := publishDeck(3)
+		subscription, err := suite.sv.SubscribeCardGroup(ctx, deck.ID, newUser().ID)
+		suite.Require().NoError(err)
+		assert.Equal(t, 1, *subscription.SourceVersion)
+
+		// Progress of the subscriber on the card to be fixed
+		reviewDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		suite.Require().NoError(suite.db.Model(&repository.Card{}).
+			Where("cardgroup_id = ? AND source_card_id = ?", subscription.ID, cards[1].ID).
+			Updates(map[string]interface{}{"review_date": reviewDate, "interval_days": 8}).Error)
+
+		update, err := suite.sv.CardGroupUpdate(ctx, subscription.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, update) // Up to date
+
+		// The owner fixes a typo, removes a card and adds a card
+		_, err = suite.sv.UpdateCard(ctx, cards[1].ID, model.NewCard{
+			Front:      "Front 1",
+			Back:       "Back one",
+			ReviewDate: cards[1].ReviewDate,
+		})
+		suite.Require().NoError(err)
+		_, err = suite.sv.DeleteCard(ctx, cards[2].ID)
+		suite.Require().NoError(err)
+		added, err := suite.sv.CreateCard(ctx, model.NewCard{
+			Front:       "Front 3",
+			Back:        "Back 3",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: deck.ID,
+		})
+		suite.Require().NoError(err)
+		_, err = suite.sv.PublishCardGroupVersion(ctx, deck.ID, "")
+		suite.Require().NoError(err)
+
+		update, err = suite.sv.CardGroupUpdate(ctx, subscription.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *update.CurrentVersion)
+		assert.Equal(t, 2, update.LatestVersion)
+		if assert.Len(t, update.Added, 1) {
+			assert.Equal(t, added.ID, update.Added[0].SourceCardID)
+		}
+		if assert.Len(t, update.Changed, 1) {
+			assert.Equal(t, "Back 1", update.Changed[0].Before.Back)
+			assert.Equal(t, "Back one", update.Changed[0].After.Back)
+		}
+		if assert.Len(t, update.Removed, 1) {
+			assert.Equal(t, cards[2].ID, update.Removed[0].SourceCardID)
+		}
+
+		applied, err := suite.sv.ApplyCardGroupUpdate(ctx, subscription.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, *applied.SourceVersion)
+
+		var copies []repository.Card
+		suite.db.Where("cardgroup_id = ?", subscription.ID).Order("source_card_id").Find(&copies)
+		if assert.Len(t, copies, 3) {
+			assert.Equal(t, "Back one", copies[1].Back)
+			assert.Equal(t, 8, copies[1].IntervalDays) // Progress is kept
+			assert.True(t, reviewDate.Equal(copies[1].ReviewDate))
+			assert.Equal(t, "Front 3", copies[2].Front)
+			assert.Equal(t, 1, copies[2].IntervalDays)
+		}
+
+		update, err = suite.sv.CardGroupUpdate(ctx, subscription.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, update)
+	})
+
+	suite.Run("Normal_ApplyCardGroupUpdate_ForkConflicts", func() {
+		deck, cards := publishDeck(3)
+		fork, err := suite.sv.ForkCardGroup(ctx, deck.ID, newUser().ID)
+		suite.Require().NoError(err)
+
+		// The forker edits two cards and adds a card of their own
+		var forkCards []repository.Card
+		suite.Require().NoError(suite.db.Where("cardgroup_id = ?", fork.ID).Order("source_card_id").Find(&forkCards).Error)
+		suite.Require().Len(forkCards, 3)
+		for _, i := range []int{0, 2} {
+			suite.Require().NoError(suite.db.Model(&forkCards[i]).Update("back", "My back").Error)
+		}
+		_, err = suite.sv.CreateCard(ctx, model.NewCard{
+			Front:       "Front 3",
+			Back:        "My back",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: fork.ID,
+		})
+		suite.Require().NoError(err)
+
+		// The owner changes and removes the edited cards, removes an unedited card and adds a card of the same front
+		_, err = suite.sv.UpdateCard(ctx, cards[0].ID, model.NewCard{
+			Front:      "Front 0",
+			Back:       "Back zero",
+			ReviewDate: cards[0].ReviewDate,
+		})
+		suite.Require().NoError(err)
+		for _, i := range []int{1, 2} {
+			_, err = suite.sv.DeleteCard(ctx, cards[i].ID)
+			suite.Require().NoError(err)
+		}
+		added, err := suite.sv.CreateCard(ctx, model.NewCard{
+			Front:       "Front 3",
+			Back:        "Back 3",
+			ReviewDate:  time.Now().UTC(),
+			CardgroupID: deck.ID,
+		})
+		suite.Require().NoError(err)
+		_, err = suite.sv.PublishCardGroupVersion(ctx, deck.ID, "")
+		suite.Require().NoError(err)
+
+		update, err := suite.sv.CardGroupUpdate(ctx, fork.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, update.Added)
+		assert.Empty(t, update.Changed)
+		if assert.Len(t, update.Removed, 1) {
+			assert.Equal(t, cards[1].ID, update.Removed[0].SourceCardID)
+		}
+		if assert.Len(t, update.Conflicts, 3) {
+			assert.Equal(t, cards[0].ID, update.Conflicts[0].SourceCardID)
+			assert.Equal(t, cards[2].ID, update.Conflicts[1].SourceCardID)
+			assert.Equal(t, added.ID, update.Conflicts[2].SourceCardID)
+		}
+
+		applied, err := suite.sv.ApplyCardGroupUpdate(ctx, fork.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, *applied.SourceVersion)
+
+		var copies []repository.Card
+		suite.db.Where("cardgroup_id = ?", fork.ID).Order("front").Find(&copies)
+		if assert.Len(t, copies, 3) {
+			assert.Equal(t, "Front 0", copies[0].Front)
+			assert.Equal(t, "My back", copies[0].Back) // Local edits are kept
+			assert.Equal(t, "Front 2", copies[1].Front)
+			assert.Equal(t, "Front 3", copies[2].Front)
+			assert.Equal(t, "My back", copies[2].Back)
+		}
+	})
+
+	suite.Run("Error_PublishVersionOfPrivateCardGroup", func() {
+		cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, suite.sv, suite.sv, suite.sv)
+		suite.Require().NoError(err)
+
+		_, err = suite.sv.PublishCardGroupVersion(ctx, cardGroup.ID, "")
+		assert.ErrorContains(t, err, "card group is not published")
+		_, err = suite.sv.CardGroupUpdate(ctx, cardGroup.ID)
+		assert.ErrorContains(t, err, "neither a subscription nor a fork")
+	})
+}
+
+func TestCardGroupVersionTestSuite(t *testing.T) {
+	suite.Run(t, new(CardGroupVersionTestSuite))
+}
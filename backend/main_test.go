@@ -1,6 +1,7 @@
 package main
 
 import (
+	"backend/pkg/auth"
 	"backend/pkg/config"
 	"backend/pkg/utils"
 	"backend/testutils"
@@ -11,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
@@ -113,3 +115,33 @@ func TestMainSmoke(t *testing.T) {
 		assert.Equal(t, http.StatusOK, res.StatusCode)
 	})
 }
+
+func TestExportAnki(t *testing.T) {
+	t.Parallel()
+
+	ts, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("Error_InvalidCardGroupID", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/export/anki/invalid")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Error_WithoutViewer", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/export/anki/1")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("Error_NotMember", func(t *testing.T) {
+		token, err := auth.NewToken(999999, config.Cfg.JWTSecret, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/export/anki/999999", nil)
+		req.AddCookie(&http.Cookie{Name: "jwt", Value: token})
+
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
@@ -0,0 +1,131 @@
+package anki
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Field separator of notes.flds
+const fieldSeparator = "\x1f"
+
+// Card types of cards.type
+const (
+	CardTypeNew      = 0
+	CardTypeLearning = 1
+	CardTypeReview   = 2
+)
+
+// Review types of revlog.type
+const (
+	ReviewTypeLearn   = 0
+	ReviewTypeReview  = 1
+	ReviewTypeRelearn = 2
+	ReviewTypeCram    = 3
+)
+
+// Answer buttons of revlog.ease
+const (
+	EaseAgain = 1
+	EaseHard  = 2
+	EaseGood  = 3
+	EaseEasy  = 4
+)
+
+// Model is a note type, which names the fields of notes
+type Model struct {
+	ID     int64
+	Name   string
+	Fields []string
+}
+
+// Note is a note of a collection
+type Note struct {
+	ID int64
+	// GUID identifies the note across collections, so that importing it again updates the note
+	GUID    string
+	ModelID int64
+	Fields  []string
+	Tags    []string
+}
+
+// Card is a card generated from a note, holding its scheduling state
+type Card struct {
+	ID     int64
+	NoteID int64
+	Ord    int
+	Type   int
+	Queue  int
+	// Due is the number of days since the collection is created for review cards
+	Due int64
+	// Interval is in days. Negative values are seconds of learning cards.
+	Interval int
+}
+
+// Review is an entry of the review log
+type Review struct {
+	// ID is the epoch milliseconds when the review is done
+	ID       int64
+	CardID   int64
+	Ease     int
+	Interval int
+	Type     int
+}
+
+// Collection is the content of an Anki package
+type Collection struct {
+	Created time.Time
+	Models  map[int64]*Model
+	Notes   []*Note
+	Cards   []*Card
+	Reviews []*Review
+}
+
+// Field returns the field of the note by name. When the note type does not have
+// the field, the field at fallbackIndex is returned instead.
+func (c *Collection) Field(note *Note, name string, fallbackIndex int) string {
+	if m, ok := c.Models[note.ModelID]; ok && name != "" {
+		for i, field := range m.Fields {
+			if strings.EqualFold(field, name) && i < len(note.Fields) {
+				return note.Fields[i]
+			}
+		}
+	}
+	if fallbackIndex >= 0 && fallbackIndex < len(note.Fields) {
+		return note.Fields[fallbackIndex]
+	}
+	return ""
+}
+
+// DueDate returns the date when a review card is due
+func (c *Collection) DueDate(card *Card) time.Time {
+	return c.Created.AddDate(0, 0, int(card.Due))
+}
+
+// DueFromDate returns the due of a review card which is due at the date
+func (c *Collection) DueFromDate(date time.Time) int64 {
+	return int64(math.Floor(date.Sub(c.Created).Hours() / 24))
+}
+
+// Time returns when the review is done
+func (r *Review) Time() time.Time {
+	return time.UnixMilli(r.ID).UTC()
+}
+
+var (
+	lineBreakTag = regexp.MustCompile(`(?i)<br\s*/?>|</?(div|p)(\s[^>]*)?>`)
+	htmlTag      = regexp.MustCompile(`<[^>]*>`)
+	blankLines   = regexp.MustCompile(`\n\s*\n`)
+)
+
+// StripHTML converts a field in HTML into plain text
+func StripHTML(field string) string {
+	text := lineBreakTag.ReplaceAllString(field, "\n")
+	text = htmlTag.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = strings.ReplaceAll(text, "\u00a0", " ")
+	text = blankLines.ReplaceAllString(strings.TrimSpace(text), "\n")
+	return text
+}
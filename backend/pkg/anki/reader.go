@@ -0,0 +1,192 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	_ "modernc.org/sqlite"
+)
+
+// Names of the collection in a package, from the newest to the oldest format
+var collectionNames = []string{"collection.anki21", "collection.anki2"}
+
+// ErrCollectionTooLarge is returned when the extracted collection exceeds the maximum size
+var ErrCollectionTooLarge = errors.New("collection is too large")
+
+// ReadPackage reads an Anki package (.apkg). The collection is extracted into a
+// temporary file since SQLite needs a file to open. maxBytes limits the size of the
+// extracted collection, and zero or less means no limit.
+func ReadPackage(data []byte, maxBytes int64) (*Collection, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open package")
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	for _, name := range collectionNames {
+		if f, ok := files[name]; ok {
+			return readCollectionFile(f, maxBytes)
+		}
+	}
+	if _, ok := files["collection.anki21b"]; ok {
+		return nil, goerr.Wrap(fmt.Errorf("compressed collection is not supported. Please export with \"Support older Anki versions\""))
+	}
+	return nil, goerr.Wrap(fmt.Errorf("collection not found in package"))
+}
+
+func readCollectionFile(f *zip.File, maxBytes int64) (*Collection, error) {
+	src, err := f.Open()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open collection")
+	}
+	defer src.Close()
+
+	// The size in the zip header is not trusted, so the extraction reads one more byte than allowed
+	var r io.Reader = src
+	if maxBytes > 0 {
+		r = io.LimitReader(src, maxBytes+1)
+	}
+
+	tmp, err := os.CreateTemp("", "anki-*.sqlite")
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return nil, goerr.Wrap(err, "failed to extract collection")
+	}
+	if maxBytes > 0 && n > maxBytes {
+		tmp.Close()
+		return nil, goerr.Wrap(ErrCollectionTooLarge).With("max_bytes", maxBytes)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, goerr.Wrap(err, "failed to extract collection")
+	}
+
+	return ReadCollection(tmp.Name())
+}
+
+// ReadCollection reads an Anki collection from the SQLite file at path
+func ReadCollection(path string) (*Collection, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open collection")
+	}
+	defer db.Close()
+
+	c := &Collection{}
+	if err := c.readCol(db); err != nil {
+		return nil, err
+	}
+	if err := c.readNotes(db); err != nil {
+		return nil, err
+	}
+	if err := c.readCards(db); err != nil {
+		return nil, err
+	}
+	if err := c.readReviews(db); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Collection) readCol(db *sql.DB) error {
+	var crt int64
+	var models string
+	if err := db.QueryRow("SELECT crt, models FROM col").Scan(&crt, &models); err != nil {
+		return goerr.Wrap(err, "failed to read collection")
+	}
+	c.Created = time.Unix(crt, 0).UTC()
+
+	var jsonModels map[string]jsonModel
+	if err := json.Unmarshal([]byte(models), &jsonModels); err != nil {
+		return goerr.Wrap(err, "failed to read note types")
+	}
+
+	c.Models = make(map[int64]*Model, len(jsonModels))
+	for key, jm := range jsonModels {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return goerr.Wrap(err, fmt.Errorf("invalid note type ID : %s", key))
+		}
+		fields := make([]string, len(jm.Fields))
+		for _, field := range jm.Fields {
+			if field.Ord >= 0 && field.Ord < len(fields) {
+				fields[field.Ord] = field.Name
+			}
+		}
+		c.Models[id] = &Model{ID: id, Name: jm.Name, Fields: fields}
+	}
+	return nil
+}
+
+func (c *Collection) readNotes(db *sql.DB) error {
+	rows, err := db.Query("SELECT id, guid, mid, flds, tags FROM notes ORDER BY id")
+	if err != nil {
+		return goerr.Wrap(err, "failed to read notes")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note Note
+		var fields, tags string
+		if err := rows.Scan(&note.ID, &note.GUID, &note.ModelID, &fields, &tags); err != nil {
+			return goerr.Wrap(err, "failed to read note")
+		}
+		note.Fields = strings.Split(fields, fieldSeparator)
+		note.Tags = strings.Fields(tags)
+		c.Notes = append(c.Notes, &note)
+	}
+	return rows.Err()
+}
+
+func (c *Collection) readCards(db *sql.DB) error {
+	rows, err := db.Query("SELECT id, nid, ord, type, queue, due, ivl FROM cards ORDER BY nid, ord")
+	if err != nil {
+		return goerr.Wrap(err, "failed to read cards")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var card Card
+		if err := rows.Scan(&card.ID, &card.NoteID, &card.Ord, &card.Type, &card.Queue, &card.Due, &card.Interval); err != nil {
+			return goerr.Wrap(err, "failed to read card")
+		}
+		c.Cards = append(c.Cards, &card)
+	}
+	return rows.Err()
+}
+
+func (c *Collection) readReviews(db *sql.DB) error {
+	rows, err := db.Query("SELECT id, cid, ease, ivl, type FROM revlog ORDER BY id")
+	if err != nil {
+		return goerr.Wrap(err, "failed to read review log")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review Review
+		if err := rows.Scan(&review.ID, &review.CardID, &review.Ease, &review.Interval, &review.Type); err != nil {
+			return goerr.Wrap(err, "failed to read review")
+		}
+		c.Reviews = append(c.Reviews, &review)
+	}
+	return rows.Err()
+}
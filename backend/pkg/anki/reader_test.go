@@ -0,0 +1,160 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCollectionSQL creates a collection with a basic note type and a reversed note type
+const testCollectionSQL = schemaSQL + `
+INSERT INTO col VALUES (1, 1719792000, 0, 0, 11, 0, 0, 0, '{}',
+    '{"1001": {"name": "Basic", "flds": [{"name": "Front", "ord": 0}, {"name": "Back", "ord": 1}]},
+      "1002": {"name": "Vocabulary", "flds": [{"name": "Meaning", "ord": 1}, {"name": "Word", "ord": 0}, {"name": "Example", "ord": 2}]}}',
+    '{}', '{}', '{}');
+INSERT INTO notes VALUES (1, 'a', 1001, 0, 0, ' jlpt n5 ', 'run' || char(31) || '走る<br>駆ける', 0, 0, 0, '');
+INSERT INTO notes VALUES (2, 'b', 1002, 0, 0, '', 'rube' || char(31) || '田舎者&nbsp;' || char(31) || 'He is a rube.', 0, 0, 0, '');
+INSERT INTO cards VALUES (11, 1, 1, 0, 0, 0, 2, 2, 10, 7, 2500, 3, 0, 0, 0, 0, 0, '');
+INSERT INTO cards VALUES (12, 2, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, '');
+INSERT INTO revlog VALUES (1719878400000, 11, 0, 1, -600, 0, 0, 0, 0);
+INSERT INTO revlog VALUES (1719964800000, 11, 0, 3, 7, 1, 2500, 0, 1);
+`
+
+// writeTestPackage zips a collection built from the SQL into an Anki package
+func writeTestPackage(t *testing.T, collectionName string, statements string) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "collection.anki2")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(statements)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	collection, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(collectionName)
+	require.NoError(t, err)
+	_, err = w.Write(collection)
+	require.NoError(t, err)
+	w, err = zw.Create("media")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("{}"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestReadPackage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_ReadPackage", func(t *testing.T) {
+		t.Parallel()
+		data := writeTestPackage(t, "collection.anki2", testCollectionSQL)
+
+		c, err := ReadPackage(data, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), c.Created)
+		assert.Len(t, c.Models, 2)
+		assert.Equal(t, []string{"Word", "Meaning", "Example"}, c.Models[1002].Fields)
+
+		require.Len(t, c.Notes, 2)
+		assert.Equal(t, []string{"jlpt", "n5"}, c.Notes[0].Tags)
+		assert.Equal(t, "run", c.Field(c.Notes[0], "Front", 0))
+		assert.Equal(t, "走る\n駆ける", StripHTML(c.Field(c.Notes[0], "Back", 1)))
+		assert.Equal(t, "田舎者", StripHTML(c.Field(c.Notes[1], "Meaning", 1)))
+		assert.Equal(t, "rube", c.Field(c.Notes[1], "Front", 0)) // Falls back to the index
+
+		require.Len(t, c.Cards, 2)
+		assert.Equal(t, 7, c.Cards[0].Interval)
+		assert.Equal(t, time.Date(2024, 7, 11, 0, 0, 0, 0, time.UTC), c.DueDate(c.Cards[0]))
+
+		require.Len(t, c.Reviews, 2)
+		assert.Equal(t, EaseAgain, c.Reviews[0].Ease)
+		assert.Equal(t, time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC), c.Reviews[1].Time())
+	})
+
+	t.Run("Normal_ReadPackage_Anki21", func(t *testing.T) {
+		t.Parallel()
+		data := writeTestPackage(t, "collection.anki21", testCollectionSQL)
+
+		c, err := ReadPackage(data, 0)
+
+		require.NoError(t, err)
+		assert.Len(t, c.Notes, 2)
+	})
+
+	t.Run("Normal_ReadPackage_WithinLimit", func(t *testing.T) {
+		t.Parallel()
+		data := writeTestPackage(t, "collection.anki2", testCollectionSQL)
+
+		c, err := ReadPackage(data, 10*1024*1024)
+
+		require.NoError(t, err)
+		assert.Len(t, c.Notes, 2)
+	})
+
+	t.Run("Error_ReadPackage_TooLarge", func(t *testing.T) {
+		t.Parallel()
+		data := writeTestPackage(t, "collection.anki2", testCollectionSQL)
+
+		c, err := ReadPackage(data, 1024)
+
+		assert.ErrorIs(t, err, ErrCollectionTooLarge)
+		assert.Nil(t, c)
+	})
+
+	t.Run("Error_ReadPackage_NotZip", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := ReadPackage([]byte("not a zip file"), 0)
+
+		assert.Error(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("Error_ReadPackage_CompressedCollection", func(t *testing.T) {
+		t.Parallel()
+		data := writeTestPackage(t, "collection.anki21b", testCollectionSQL)
+
+		c, err := ReadPackage(data, 0)
+
+		assert.ErrorContains(t, err, "compressed collection is not supported")
+		assert.Nil(t, c)
+	})
+}
+
+func TestStripHTML(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		field    string
+		expected string
+	}{
+		{"Plain text", "走る", "走る"},
+		{"Line breaks", "走る<br />駆ける<div>運行する</div>", "走る\n駆ける\n運行する"},
+		{"Entities", "A &amp; B&nbsp;", "A & B"},
+		{"Tags", "<b>bold</b> <span style=\"color: red\">red</span>", "bold red"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, StripHTML(tc.field))
+		})
+	}
+}
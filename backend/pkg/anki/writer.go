@@ -0,0 +1,348 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+)
+
+// The collection written by WritePackage has a single deck with the default options
+const (
+	deckID        = 1
+	deckConfID    = 1
+	defaultFactor = 2500
+)
+
+// BasicModelID is the ID of the note type created by NewBasicModel
+const BasicModelID int64 = 1719792000000
+
+// NewBasicModel returns the "Basic" note type with Front and Back fields
+func NewBasicModel() *Model {
+	return &Model{ID: BasicModelID, Name: "Basic", Fields: []string{"Front", "Back"}}
+}
+
+// jsonModel is a note type stored in col.models
+type jsonModel struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Type      int            `json:"type"`
+	Mod       int64          `json:"mod"`
+	Usn       int            `json:"usn"`
+	SortField int            `json:"sortf"`
+	DeckID    int64          `json:"did"`
+	Templates []jsonTemplate `json:"tmpls"`
+	Fields    []jsonField    `json:"flds"`
+	CSS       string         `json:"css"`
+	LatexPre  string         `json:"latexPre"`
+	LatexPost string         `json:"latexPost"`
+	Req       []any          `json:"req"`
+	Tags      []string       `json:"tags"`
+	Vers      []any          `json:"vers"`
+}
+
+type jsonTemplate struct {
+	Name           string `json:"name"`
+	Ord            int    `json:"ord"`
+	QuestionFormat string `json:"qfmt"`
+	AnswerFormat   string `json:"afmt"`
+	DeckID         *int64 `json:"did"`
+	BrowserQFormat string `json:"bqfmt"`
+	BrowserAFormat string `json:"bafmt"`
+}
+
+type jsonField struct {
+	Name   string   `json:"name"`
+	Ord    int      `json:"ord"`
+	Sticky bool     `json:"sticky"`
+	RTL    bool     `json:"rtl"`
+	Font   string   `json:"font"`
+	Size   int      `json:"size"`
+	Media  []string `json:"media"`
+}
+
+const (
+	modelCSS       = ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n"
+	modelLatexPre  = "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n"
+	modelLatexPost = "\\end{document}"
+)
+
+// toJSON converts a model into a note type with a single card template,
+// which shows the first field on the question and the others on the answer
+func (m *Model) toJSON(mod int64) jsonModel {
+	fields := make([]jsonField, len(m.Fields))
+	answers := []string{"{{FrontSide}}", "<hr id=answer>"}
+	for i, name := range m.Fields {
+		fields[i] = jsonField{Name: name, Ord: i, Font: "Arial", Size: 20, Media: []string{}}
+		if i > 0 {
+			answers = append(answers, "{{"+name+"}}")
+		}
+	}
+	question := ""
+	if len(m.Fields) > 0 {
+		question = "{{" + m.Fields[0] + "}}"
+	}
+
+	return jsonModel{
+		ID:        m.ID,
+		Name:      m.Name,
+		Mod:       mod,
+		Usn:       -1,
+		DeckID:    deckID,
+		Templates: []jsonTemplate{{Name: "Card 1", QuestionFormat: question, AnswerFormat: strings.Join(answers, "\n")}},
+		Fields:    fields,
+		CSS:       modelCSS,
+		LatexPre:  modelLatexPre,
+		LatexPost: modelLatexPost,
+		Req:       []any{[]any{0, "any", []int{0}}},
+		Tags:      []string{},
+		Vers:      []any{},
+	}
+}
+
+// WritePackage writes the collection into an Anki package (.apkg) with a single deck.
+// The collection is written in the schema version 11, so that older Anki versions can import it.
+func WritePackage(c *Collection, deckName string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "anki-*")
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := WriteCollection(path, c, deckName); err != nil {
+		return nil, err
+	}
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read collection")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(collectionNames[len(collectionNames)-1])
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to write package")
+	}
+	if _, err := w.Write(collection); err != nil {
+		return nil, goerr.Wrap(err, "failed to write package")
+	}
+	// The package has no media files
+	w, err = zw.Create("media")
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to write package")
+	}
+	if _, err := w.Write([]byte("{}")); err != nil {
+		return nil, goerr.Wrap(err, "failed to write package")
+	}
+	if err := zw.Close(); err != nil {
+		return nil, goerr.Wrap(err, "failed to write package")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteCollection writes the collection into a new SQLite file at path
+func WriteCollection(path string, c *Collection, deckName string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return goerr.Wrap(err, "failed to open collection")
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return goerr.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(schemaSQL); err != nil {
+		return goerr.Wrap(err, "failed to create collection")
+	}
+
+	now := time.Now()
+	if err := c.writeCol(tx, deckName, now); err != nil {
+		return err
+	}
+	if err := c.writeNotes(tx, now); err != nil {
+		return err
+	}
+	if err := c.writeCards(tx, now); err != nil {
+		return err
+	}
+	if err := c.writeReviews(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return goerr.Wrap(err, "failed to commit collection")
+	}
+	return nil
+}
+
+func (c *Collection) writeCol(tx *sql.Tx, deckName string, now time.Time) error {
+	models := make(map[string]jsonModel, len(c.Models))
+	var currentModel int64
+	for id, m := range c.Models {
+		models[strconv.FormatInt(id, 10)] = m.toJSON(now.Unix())
+		currentModel = id
+	}
+
+	conf := map[string]any{
+		"nextPos":       len(c.Cards) + 1,
+		"estTimes":      true,
+		"activeDecks":   []int{deckID},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       deckID,
+		"newBury":       true,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      strconv.FormatInt(currentModel, 10),
+		"collapseTime":  1200,
+	}
+	decks := map[string]any{
+		strconv.Itoa(deckID): map[string]any{
+			"id":        deckID,
+			"name":      deckName,
+			"desc":      "",
+			"mod":       now.Unix(),
+			"usn":       -1,
+			"conf":      deckConfID,
+			"dyn":       0,
+			"collapsed": false,
+			"extendNew": 10,
+			"extendRev": 50,
+			"newToday":  []int{0, 0},
+			"revToday":  []int{0, 0},
+			"lrnToday":  []int{0, 0},
+			"timeToday": []int{0, 0},
+		},
+	}
+	dconf := map[string]any{
+		strconv.Itoa(deckConfID): map[string]any{
+			"id":       deckConfID,
+			"name":     "Default",
+			"mod":      0,
+			"usn":      0,
+			"maxTaken": 60,
+			"autoplay": true,
+			"timer":    0,
+			"replayq":  true,
+			"dyn":      false,
+			"new": map[string]any{
+				"delays": []int{1, 10}, "ints": []int{1, 4, 7}, "initialFactor": defaultFactor,
+				"order": 1, "perDay": 20, "bury": true, "separate": true,
+			},
+			"rev": map[string]any{
+				"perDay": 200, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500,
+				"bury": true, "minSpace": 1,
+			},
+			"lapse": map[string]any{
+				"delays": []int{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0,
+			},
+		},
+	}
+
+	values := make([]string, 0, 4)
+	for _, v := range []any{conf, models, decks, dconf} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return goerr.Wrap(err, "failed to encode collection")
+		}
+		values = append(values, string(b))
+	}
+
+	created := c.Created
+	if created.IsZero() {
+		created = now
+	}
+	_, err := tx.Exec("INSERT INTO col VALUES (1, ?, ?, ?, ?, 0, 0, 0, ?, ?, ?, ?, '{}')",
+		created.Unix(), now.UnixMilli(), now.UnixMilli(), schemaVersion, values[0], values[1], values[2], values[3])
+	if err != nil {
+		return goerr.Wrap(err, "failed to write collection")
+	}
+	return nil
+}
+
+func (c *Collection) writeNotes(tx *sql.Tx, now time.Time) error {
+	stmt, err := tx.Prepare("INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')")
+	if err != nil {
+		return goerr.Wrap(err, "failed to write notes")
+	}
+	defer stmt.Close()
+
+	for _, note := range c.Notes {
+		guid := note.GUID
+		if guid == "" {
+			guid = strconv.FormatInt(note.ID, 36)
+		}
+		tags := ""
+		if len(note.Tags) > 0 {
+			tags = " " + strings.Join(note.Tags, " ") + " "
+		}
+		sortField := ""
+		if len(note.Fields) > 0 {
+			sortField = StripHTML(note.Fields[0])
+		}
+
+		if _, err := stmt.Exec(note.ID, guid, note.ModelID, now.Unix(), tags,
+			strings.Join(note.Fields, fieldSeparator), sortField, checksum(sortField)); err != nil {
+			return goerr.Wrap(err, "failed to write note")
+		}
+	}
+	return nil
+}
+
+func (c *Collection) writeCards(tx *sql.Tx, now time.Time) error {
+	stmt, err := tx.Prepare("INSERT INTO cards VALUES (?, ?, ?, ?, ?, -1, ?, ?, ?, ?, ?, 0, 0, 0, 0, 0, 0, '')")
+	if err != nil {
+		return goerr.Wrap(err, "failed to write cards")
+	}
+	defer stmt.Close()
+
+	for _, card := range c.Cards {
+		factor := 0
+		if card.Type == CardTypeReview {
+			factor = defaultFactor
+		}
+		if _, err := stmt.Exec(card.ID, card.NoteID, deckID, card.Ord, now.Unix(),
+			card.Type, card.Queue, card.Due, card.Interval, factor); err != nil {
+			return goerr.Wrap(err, "failed to write card")
+		}
+	}
+	return nil
+}
+
+func (c *Collection) writeReviews(tx *sql.Tx) error {
+	stmt, err := tx.Prepare("INSERT INTO revlog VALUES (?, ?, -1, ?, ?, 0, ?, 0, ?)")
+	if err != nil {
+		return goerr.Wrap(err, "failed to write review log")
+	}
+	defer stmt.Close()
+
+	for _, review := range c.Reviews {
+		if _, err := stmt.Exec(review.ID, review.CardID, review.Ease, review.Interval, defaultFactor, review.Type); err != nil {
+			return goerr.Wrap(err, "failed to write review")
+		}
+	}
+	return nil
+}
+
+// checksum is the first 8 hex digits of the SHA1 of the sort field, used by Anki to find duplicates
+func checksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
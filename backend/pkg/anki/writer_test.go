@@ -0,0 +1,120 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCollection() *Collection {
+	created := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	return &Collection{
+		Created: created,
+		Models:  map[int64]*Model{BasicModelID: NewBasicModel()},
+		Notes: []*Note{
+			{ID: 1, GUID: "flamingo-1", ModelID: BasicModelID, Fields: []string{"run", "走る"}, Tags: []string{"verb"}},
+			{ID: 2, ModelID: BasicModelID, Fields: []string{"rube", "田舎者"}},
+		},
+		Cards: []*Card{
+			{ID: 11, NoteID: 1, Type: CardTypeReview, Queue: CardTypeReview, Due: 10, Interval: 7},
+			{ID: 12, NoteID: 2, Type: CardTypeNew, Queue: CardTypeNew, Due: 2},
+		},
+		Reviews: []*Review{
+			{ID: created.AddDate(0, 0, 3).UnixMilli(), CardID: 11, Ease: EaseGood, Interval: 7, Type: ReviewTypeReview},
+		},
+	}
+}
+
+func TestWritePackage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_WritePackage", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := WritePackage(newTestCollection(), "English")
+		require.NoError(t, err)
+
+		c, err := ReadPackage(data, 0)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), c.Created)
+		require.Contains(t, c.Models, BasicModelID)
+		assert.Equal(t, []string{"Front", "Back"}, c.Models[BasicModelID].Fields)
+
+		require.Len(t, c.Notes, 2)
+		assert.Equal(t, "flamingo-1", c.Notes[0].GUID)
+		assert.Equal(t, []string{"verb"}, c.Notes[0].Tags)
+		assert.Equal(t, "run", c.Field(c.Notes[0], "Front", 0))
+		assert.Equal(t, "走る", c.Field(c.Notes[0], "Back", 1))
+		assert.NotEmpty(t, c.Notes[1].GUID)
+
+		require.Len(t, c.Cards, 2)
+		assert.Equal(t, CardTypeReview, c.Cards[0].Type)
+		assert.Equal(t, 7, c.Cards[0].Interval)
+		assert.Equal(t, time.Date(2024, 7, 11, 0, 0, 0, 0, time.UTC), c.DueDate(c.Cards[0]))
+		assert.Equal(t, CardTypeNew, c.Cards[1].Type)
+
+		require.Len(t, c.Reviews, 1)
+		assert.Equal(t, EaseGood, c.Reviews[0].Ease)
+		assert.Equal(t, time.Date(2024, 7, 4, 0, 0, 0, 0, time.UTC), c.Reviews[0].Time())
+	})
+
+	t.Run("Normal_WritePackage_Deck", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := WritePackage(newTestCollection(), "English")
+		require.NoError(t, err)
+
+		// Anki needs the deck and the card template in the collection to import it
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		f, err := zr.Open("collection.anki2")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "collection.anki2")
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(f)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+		db, err := sql.Open("sqlite", path)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var ver int
+		var models, decks string
+		require.NoError(t, db.QueryRow("SELECT ver, models, decks FROM col").Scan(&ver, &models, &decks))
+		assert.Equal(t, schemaVersion, ver)
+		assert.Contains(t, decks, `"name":"English"`)
+		assert.Contains(t, models, `"qfmt":"{{Front}}"`)
+		assert.Contains(t, models, `{{Back}}`)
+
+		var csum int64
+		require.NoError(t, db.QueryRow("SELECT csum FROM notes WHERE id = 1").Scan(&csum))
+		assert.Equal(t, checksum("run"), csum)
+	})
+
+	t.Run("Error_WritePackage_DuplicateCard", func(t *testing.T) {
+		t.Parallel()
+		c := newTestCollection()
+		c.Cards = append(c.Cards, &Card{ID: 11, NoteID: 2})
+
+		data, err := WritePackage(c, "English")
+
+		assert.Error(t, err)
+		assert.Nil(t, data)
+	})
+}
+
+func TestDueFromDate(t *testing.T) {
+	t.Parallel()
+	c := newTestCollection()
+
+	assert.Equal(t, int64(10), c.DueFromDate(time.Date(2024, 7, 11, 9, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int64(-1), c.DueFromDate(time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,121 @@
+// Package auth carries the authenticated user of a request, the viewer, from the JWT
+// claims to the resolvers and usecases through the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/m-mizutani/goerr"
+)
+
+// ErrUnauthenticated is returned when a user-scoped operation is called without a viewer
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrForbidden is returned when the viewer is not allowed to perform an operation
+var ErrForbidden = errors.New("forbidden")
+
+type viewerContextKey struct{}
+
+// Claims are the claims of the JWT of a logged-in user. The subject is the ID of the user for
+// tokens signed with the HMAC secret, and the identity of the user at the identity provider otherwise.
+type Claims struct {
+	jwt.RegisteredClaims
+	Email         string       `json:"email,omitempty"`
+	EmailVerified bool         `json:"email_verified,omitempty"`
+	GoogleID      string       `json:"google_id,omitempty"`
+	UserMetadata  UserMetadata `json:"user_metadata,omitempty"`
+}
+
+// UserMetadata is the profile of the user in Supabase tokens
+type UserMetadata struct {
+	FullName      string `json:"full_name,omitempty"`
+	Name          string `json:"name,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+}
+
+// Identity returns the ID of the user at the identity provider, the google_id claim if any or the subject
+func (c *Claims) Identity() string {
+	if c.GoogleID != "" {
+		return c.GoogleID
+	}
+	return c.Subject
+}
+
+// IsEmailVerified reports whether the identity provider verified the email, in the email_verified
+// claim or in the profile of Supabase tokens
+func (c *Claims) IsEmailVerified() bool {
+	return c.EmailVerified || c.UserMetadata.EmailVerified
+}
+
+// DisplayName returns the name of the user in the profile, or the local part of the email
+func (c *Claims) DisplayName() string {
+	if c.UserMetadata.FullName != "" {
+		return c.UserMetadata.FullName
+	}
+	if c.UserMetadata.Name != "" {
+		return c.UserMetadata.Name
+	}
+	name, _, _ := strings.Cut(c.Email, "@")
+	return name
+}
+
+// UserID returns the ID of the user in the subject of the claims
+func (c *Claims) UserID() (int64, error) {
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, goerr.Wrap(ErrUnauthenticated, "invalid subject").With("subject", c.Subject)
+	}
+	return userID, nil
+}
+
+// SubjectViewer resolves the viewer from the user ID in the subject, for tokens signed with the HMAC secret
+func SubjectViewer(ctx context.Context, claims *Claims) (int64, error) {
+	return claims.UserID()
+}
+
+// NewToken signs a token for the user with HS256, valid until expiresAt
+func NewToken(userID int64, secret string, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to sign token")
+	}
+	return signed, nil
+}
+
+// WithViewer returns a copy of ctx carrying the ID of the authenticated user
+func WithViewer(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, viewerContextKey{}, userID)
+}
+
+// ViewerID returns the ID of the authenticated user, or ErrUnauthenticated for anonymous requests
+func ViewerID(ctx context.Context) (int64, error) {
+	userID, ok := ctx.Value(viewerContextKey{}).(int64)
+	if !ok {
+		return 0, goerr.Wrap(ErrUnauthenticated, "no viewer in context")
+	}
+	return userID, nil
+}
+
+// Permissions seeded by migration and checked by the Authorizer
+const (
+	PermissionCardGroupWrite    = "cardgroup:write"
+	PermissionCardGroupAdmin    = "cardgroup:admin"
+	PermissionDeckPublish       = "deck:publish"
+	PermissionUserAdmin         = "user:admin"
+	PermissionRoleAdmin         = "role:admin"
+	PermissionOrganizationWrite = "organization:write"
+	PermissionOrganizationAdmin = "organization:admin"
+)
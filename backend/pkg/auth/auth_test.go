@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_RoundTrip", func(t *testing.T) {
+		// Arrange
+		token, err := NewToken(42, "secret", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		// Act
+		claims := new(Claims)
+		_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("secret"), nil
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		userID, err := claims.UserID()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), userID)
+	})
+
+	t.Run("Error_InvalidSubject", func(t *testing.T) {
+		// Arrange
+		claims := &Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "google-oauth2|123"}}
+
+		// Act
+		_, err := claims.UserID()
+
+		// Assert
+		assert.True(t, errors.Is(err, ErrUnauthenticated))
+	})
+}
+
+func TestViewerID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_WithViewer", func(t *testing.T) {
+		// Act
+		userID, err := ViewerID(WithViewer(context.Background(), 7))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), userID)
+	})
+
+	t.Run("Error_Anonymous", func(t *testing.T) {
+		// Act
+		_, err := ViewerID(context.Background())
+
+		// Assert
+		assert.True(t, errors.Is(err, ErrUnauthenticated))
+	})
+}
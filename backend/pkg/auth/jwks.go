@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/m-mizutani/goerr"
+)
+
+const (
+	// minRefreshInterval limits how often an unknown key ID makes the JWKS document be fetched again
+	minRefreshInterval = time.Minute
+	// minRetryInterval and maxRetryInterval bound the backoff after failed fetches, while the cached keys are served
+	minRetryInterval = 5 * time.Second
+	maxRetryInterval = 5 * time.Minute
+)
+
+// jwk is a JSON Web Key of RFC 7517. Only RSA and EC public keys are used.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKS is the JSON Web Key Set of an identity provider, fetched from url and cached for ttl
+type JWKS struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]interface{}
+	fetched time.Time
+	// fetching is closed when the fetch in flight finishes
+	fetching chan struct{}
+	// err is the error of the last fetch, returned until retryAt while backing off
+	err     error
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// NewJWKS returns a key set fetched lazily from url
+func NewJWKS(url string, ttl time.Duration) *JWKS {
+	return &JWKS{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Keyfunc returns the public key of the kid header of the token. The document is fetched again
+// when the cache is expired, or when the key ID is unknown, which happens after a key rotation.
+// The cached keys are still served when the fetch fails.
+func (j *JWKS) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	j.mu.Lock()
+	key, ok := j.keys[kid]
+	expired := time.Since(j.fetched) > j.ttl
+	stale := expired || time.Since(j.fetched) > minRefreshInterval
+	j.mu.Unlock()
+	if ok && !expired {
+		return key, nil
+	}
+
+	var err error
+	if stale {
+		err = j.refresh()
+	}
+
+	j.mu.Lock()
+	key, ok = j.keys[kid]
+	j.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, goerr.New("unknown key ID").With("kid", kid)
+}
+
+// refresh fetches the document and replaces the cached keys. Callers during a fetch wait for it
+// instead of fetching again, and a failed fetch is retried after an exponential backoff.
+func (j *JWKS) refresh() error {
+	j.mu.Lock()
+	if fetching := j.fetching; fetching != nil {
+		j.mu.Unlock()
+		<-fetching
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		return j.err
+	}
+	if time.Now().Before(j.retryAt) {
+		defer j.mu.Unlock()
+		return j.err
+	}
+	fetching := make(chan struct{})
+	j.fetching = fetching
+	j.mu.Unlock()
+
+	keys, err := j.fetch()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.err = err
+	if err != nil {
+		j.backoff = min(max(2*j.backoff, minRetryInterval), maxRetryInterval)
+		j.retryAt = time.Now().Add(j.backoff)
+	} else {
+		j.keys = keys
+		j.fetched = time.Now()
+		j.backoff = 0
+		j.retryAt = time.Time{}
+	}
+	j.fetching = nil
+	close(fetching)
+	return err
+}
+
+// fetch gets the document and decodes its signing keys. Keys that cannot be decoded are skipped,
+// so that a single malformed or unsupported key does not invalidate the others.
+func (j *JWKS) fetch() (map[string]interface{}, error) {
+	res, err := j.client.Get(j.url)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fetch JWKS")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, goerr.New(fmt.Sprintf("failed to fetch JWKS : %s", res.Status))
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, goerr.Wrap(err, "failed to decode JWKS")
+	}
+
+	keys := make(map[string]interface{}, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil || key == nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key, or returns nil for key types other than RSA and EC
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid modulus")
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, goerr.New("unsupported curve").With("crv", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid x coordinate")
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid y coordinate")
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, goerr.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/m-mizutani/goerr"
+)
+
+// VerifierConfig configures how tokens are validated
+type VerifierConfig struct {
+	// Secret validates HS256 tokens when JWKSURL is empty
+	Secret string
+	// JWKSURL is the JWKS document of the identity provider to validate RS256 and ES256 tokens
+	JWKSURL string
+	// CacheTTL is how long the JWKS document is cached
+	CacheTTL time.Duration
+	// Issuer and Audience are the expected iss and aud claims. Empty skips the check.
+	Issuer   string
+	Audience string
+}
+
+// Verifier validates the JWT of a request and returns its claims
+type Verifier struct {
+	keyfunc jwt.Keyfunc
+	parser  *jwt.Parser
+	jwks    bool
+}
+
+// ViewerResolver returns the ID of the user of the validated claims
+type ViewerResolver func(ctx context.Context, claims *Claims) (int64, error)
+
+// NewVerifier returns a verifier against the JWKS document if configured, or the HMAC secret otherwise
+func NewVerifier(cfg VerifierConfig) *Verifier {
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	if cfg.JWKSURL != "" {
+		opts = append(opts, jwt.WithValidMethods([]string{
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodES256.Alg(),
+		}))
+		return &Verifier{
+			keyfunc: NewJWKS(cfg.JWKSURL, cfg.CacheTTL).Keyfunc,
+			parser:  jwt.NewParser(opts...),
+			jwks:    true,
+		}
+	}
+
+	secret := []byte(cfg.Secret)
+	opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	return &Verifier{
+		keyfunc: func(*jwt.Token) (interface{}, error) { return secret, nil },
+		parser:  jwt.NewParser(opts...),
+	}
+}
+
+// UsesJWKS reports whether tokens are issued by the identity provider, whose subject is not a user ID
+func (v *Verifier) UsesJWKS() bool {
+	return v.jwks
+}
+
+// Parse validates the signature, expiry, issuer and audience of the token and returns its claims
+func (v *Verifier) Parse(tokenString string) (*Claims, error) {
+	claims := new(Claims)
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, v.keyfunc); err != nil {
+		return nil, goerr.Wrap(err, "invalid token")
+	}
+	return claims, nil
+}
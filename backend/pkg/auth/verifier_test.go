@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer   = "https://example.supabase.co/auth/v1"
+	testAudience = "authenticated"
+)
+
+// jwksServer is a local stand-in of the JWKS endpoint of an identity provider
+type jwksServer struct {
+	*httptest.Server
+	keys    []jwk
+	fetches atomic.Int32
+	// failing makes the endpoint respond with an error, and delay slows down the responses
+	failing atomic.Bool
+	delay   time.Duration
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		time.Sleep(s.delay)
+		if s.failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func (s *jwksServer) addRSAKey(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	s.keys = append(s.keys, jwk{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   encodeBigInt(key.N),
+		E:   encodeBigInt(big.NewInt(int64(key.E))),
+	})
+	return key
+}
+
+func (s *jwksServer) addECKey(t *testing.T, kid string) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	s.keys = append(s.keys, jwk{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   encodeBigInt(key.X),
+		Y:   encodeBigInt(key.Y),
+	})
+	return key
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims *Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func newClaims(issuer string, audience string) *Claims {
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "6f1c1e0e-5a3b-4c8e-9d4f-1a2b3c4d5e6f",
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Email:         "taro@example.com",
+		EmailVerified: true,
+		GoogleID:      "104729810375629184756",
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	server := newJWKSServer(t)
+	rsaKey := server.addRSAKey(t, "rsa-1")
+	ecKey := server.addECKey(t, "ec-1")
+	verifier := NewVerifier(VerifierConfig{
+		JWKSURL:  server.URL,
+		CacheTTL: time.Hour,
+		Issuer:   testIssuer,
+		Audience: testAudience,
+	})
+
+	t.Run("Normal_RS256", func(t *testing.T) {
+		// Arrange
+		token := signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, newClaims(testIssuer, testAudience))
+
+		// Act
+		claims, err := verifier.Parse(token)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, verifier.UsesJWKS())
+		assert.Equal(t, "104729810375629184756", claims.Identity())
+		assert.Equal(t, "taro", claims.DisplayName())
+		assert.True(t, claims.IsEmailVerified())
+	})
+
+	t.Run("Normal_ES256", func(t *testing.T) {
+		// Arrange
+		claims := newClaims(testIssuer, testAudience)
+		claims.GoogleID = ""
+		claims.UserMetadata.FullName = "Taro Yamada"
+		claims.EmailVerified = false
+		claims.UserMetadata.EmailVerified = true // Supabase tokens verify the email in the profile
+		token := signToken(t, jwt.SigningMethodES256, "ec-1", ecKey, claims)
+
+		// Act
+		parsed, err := verifier.Parse(token)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, claims.Subject, parsed.Identity()) // The subject without a google_id claim
+		assert.Equal(t, "Taro Yamada", parsed.DisplayName())
+		assert.True(t, parsed.IsEmailVerified())
+	})
+
+	t.Run("Error_Issuer", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, newClaims("https://evil.example.com", testAudience))
+
+		_, err := verifier.Parse(token)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+	})
+
+	t.Run("Error_Audience", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, newClaims(testIssuer, "anon"))
+
+		_, err := verifier.Parse(token)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+	})
+
+	t.Run("Error_Expired", func(t *testing.T) {
+		claims := newClaims(testIssuer, testAudience)
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		token := signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims)
+
+		_, err := verifier.Parse(token)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("Error_UnknownKey", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		token := signToken(t, jwt.SigningMethodRS256, "rsa-2", otherKey, newClaims(testIssuer, testAudience))
+
+		_, err = verifier.Parse(token)
+
+		assert.ErrorContains(t, err, "unknown key ID")
+	})
+
+	t.Run("Error_HMACWithJWKS", func(t *testing.T) {
+		// A token signed with the public key as an HMAC secret must not be accepted
+		token := signToken(t, jwt.SigningMethodHS256, "rsa-1", []byte(server.keys[0].N), newClaims(testIssuer, testAudience))
+
+		_, err := verifier.Parse(token)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+}
+
+func TestVerifier_Secret(t *testing.T) {
+	t.Parallel()
+
+	verifier := NewVerifier(VerifierConfig{Secret: "secret"})
+
+	t.Run("Normal_HS256", func(t *testing.T) {
+		token, err := NewToken(42, "secret", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		claims, err := verifier.Parse(token)
+
+		assert.NoError(t, err)
+		assert.False(t, verifier.UsesJWKS())
+		userID, err := claims.UserID()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), userID)
+	})
+
+	t.Run("Error_WrongSecret", func(t *testing.T) {
+		token, err := NewToken(42, "other", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		_, err = verifier.Parse(token)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+}
+
+func TestJWKS_Cache(t *testing.T) {
+	t.Parallel()
+
+	server := newJWKSServer(t)
+	rsaKey := server.addRSAKey(t, "rsa-1")
+	jwks := NewJWKS(server.URL, time.Hour)
+	token := &jwt.Token{Header: map[string]interface{}{"kid": "rsa-1"}}
+
+	t.Run("Normal_Cached", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			key, err := jwks.Keyfunc(token)
+			assert.NoError(t, err)
+			assert.Equal(t, rsaKey.N, key.(*rsa.PublicKey).N)
+		}
+		assert.Equal(t, int32(1), server.fetches.Load())
+	})
+
+	t.Run("Normal_Rotation", func(t *testing.T) {
+		// Arrange
+		server.addECKey(t, "ec-1")
+		rotated := &jwt.Token{Header: map[string]interface{}{"kid": "ec-1"}}
+
+		// Act
+		_, err := jwks.Keyfunc(rotated)
+		assert.ErrorContains(t, err, "unknown key ID") // Refetched at most once a minute
+		jwks.fetched = time.Now().Add(-2 * minRefreshInterval)
+		key, err := jwks.Keyfunc(rotated)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.IsType(t, &ecdsa.PublicKey{}, key)
+		assert.Equal(t, int32(2), server.fetches.Load())
+	})
+}
+
+func TestJWKS_Failure(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_StaleKeys", func(t *testing.T) {
+		// Arrange
+		server := newJWKSServer(t)
+		rsaKey := server.addRSAKey(t, "rsa-1")
+		jwks := NewJWKS(server.URL, time.Hour)
+		token := &jwt.Token{Header: map[string]interface{}{"kid": "rsa-1"}}
+		_, err := jwks.Keyfunc(token)
+		assert.NoError(t, err)
+		server.failing.Store(true)
+		jwks.fetched = time.Now().Add(-2 * time.Hour)
+
+		// Act
+		key, err := jwks.Keyfunc(token)
+		again, againErr := jwks.Keyfunc(token)
+		_, unknownErr := jwks.Keyfunc(&jwt.Token{Header: map[string]interface{}{"kid": "rsa-2"}})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rsaKey.N, key.(*rsa.PublicKey).N)
+		assert.NoError(t, againErr)
+		assert.Equal(t, key, again)
+		assert.ErrorContains(t, unknownErr, "failed to fetch JWKS")
+		assert.Equal(t, int32(2), server.fetches.Load()) // No fetch while backing off
+	})
+
+	t.Run("Normal_SkipInvalidKeys", func(t *testing.T) {
+		// Arrange
+		server := newJWKSServer(t)
+		server.keys = append(server.keys,
+			jwk{Kty: "RSA", Kid: "broken", N: "!!!", E: "AQAB"},
+			jwk{Kty: "EC", Kid: "unsupported", Crv: "secp256k1", X: "AQ", Y: "AQ"},
+		)
+		ecKey := server.addECKey(t, "ec-1")
+		jwks := NewJWKS(server.URL, time.Hour)
+
+		// Act
+		key, err := jwks.Keyfunc(&jwt.Token{Header: map[string]interface{}{"kid": "ec-1"}})
+		_, brokenErr := jwks.Keyfunc(&jwt.Token{Header: map[string]interface{}{"kid": "broken"}})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, ecKey.X, key.(*ecdsa.PublicKey).X)
+		assert.ErrorContains(t, brokenErr, "unknown key ID")
+	})
+
+	t.Run("Normal_SingleFetch", func(t *testing.T) {
+		// Arrange
+		server := newJWKSServer(t)
+		server.addRSAKey(t, "rsa-1")
+		server.delay = 100 * time.Millisecond
+		jwks := NewJWKS(server.URL, time.Hour)
+		token := &jwt.Token{Header: map[string]interface{}{"kid": "rsa-1"}}
+
+		// Act
+		var wg sync.WaitGroup
+		errs := make([]error, 10)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = jwks.Keyfunc(token)
+			}(i)
+		}
+		wg.Wait()
+
+		// Assert
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), server.fetches.Load())
+	})
+}
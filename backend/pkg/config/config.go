@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/caarlos0/env/v11"
 	"log/slog"
+	"time"
 )
 
 const (
@@ -35,6 +36,26 @@ type Config struct {
 	// Application configuration
 	JWTSecret            string `env:"FL_JWT_SECRET,notEmpty" envDefault:"jwt_secret to be replaced."`
 	FLBatchDefaultAmount int    `env:"FL_BATCH_DEFAULT_AMOUNT,notEmpty" envDefault:"10"`
+	// Name of the role given to new users. No role is given when the role does not exist.
+	FLDefaultRole string `env:"FL_DEFAULT_ROLE" envDefault:"user"`
+
+	// Identity provider configuration. An empty JWKS URL validates tokens with JWTSecret instead.
+	FLJWKSURL      string        `env:"FL_JWKS_URL" envDefault:""`
+	FLJWKSCacheTTL time.Duration `env:"FL_JWKS_CACHE_TTL,notEmpty" envDefault:"1h"`
+	// Expected iss and aud claims of tokens. Empty skips the check.
+	FLJWTIssuer   string `env:"FL_JWT_ISSUER" envDefault:""`
+	FLJWTAudience string `env:"FL_JWT_AUDIENCE" envDefault:""`
+
+	// Dictionary configuration
+	FLSenseSeparators     []string `env:"FL_SENSE_SEPARATORS" envSeparator:"|" envDefault:";|；|、"`
+	FLDictionaryMaxBytes  int64    `env:"FL_DICTIONARY_MAX_BYTES,notEmpty" envDefault:"10485760"`
+	FLDictionaryChunkSize int      `env:"FL_DICTIONARY_CHUNK_SIZE,notEmpty" envDefault:"500"`
+	// Minimum similarity of fronts to take a card as renamed in mirror mode
+	FLMirrorRenameThreshold float64 `env:"FL_MIRROR_RENAME_THRESHOLD,notEmpty" envDefault:"0.8"`
+	// Path of a JMdict XML or EDICT file used to look up words offline. Empty disables the lookup.
+	FLJMdictPath string `env:"FL_JMDICT_PATH" envDefault:""`
+	// Path of a word frequency list sorted from the most frequent word. Empty disables frequency ranks.
+	FLFrequencyListPath string `env:"FL_FREQUENCY_LIST_PATH" envDefault:""`
 }
 
 // Cfg is the package-level variable that holds the parsed configuration
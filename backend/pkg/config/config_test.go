@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"backend/pkg/config"
 	"github.com/caarlos0/env/v11"
@@ -35,6 +36,17 @@ func TestConfigDefaults(t *testing.T) {
 	assert.Equal(t, "flamingodb", config.Cfg.PGDBName, "Default PGDBName should be 'flamingodb'")
 	assert.Equal(t, "5432", config.Cfg.PGPort, "Default PGPort should be '5432'")
 	assert.Equal(t, "allow", config.Cfg.PGSSLMode, "Default PGSSLMode should be 'allow'")
+	assert.Equal(t, []string{";", "；", "、"}, config.Cfg.FLSenseSeparators, "Default FLSenseSeparators should be ';', '；' and '、'")
+	assert.Equal(t, int64(10485760), config.Cfg.FLDictionaryMaxBytes, "Default FLDictionaryMaxBytes should be 10MiB")
+	assert.Equal(t, 500, config.Cfg.FLDictionaryChunkSize, "Default FLDictionaryChunkSize should be 500")
+	assert.Equal(t, 0.8, config.Cfg.FLMirrorRenameThreshold, "Default FLMirrorRenameThreshold should be 0.8")
+	assert.Equal(t, "", config.Cfg.FLJMdictPath, "Default FLJMdictPath should be empty")
+	assert.Equal(t, "", config.Cfg.FLFrequencyListPath, "Default FLFrequencyListPath should be empty")
+	assert.Equal(t, "user", config.Cfg.FLDefaultRole, "Default FLDefaultRole should be 'user'")
+	assert.Equal(t, "", config.Cfg.FLJWKSURL, "Default FLJWKSURL should be empty")
+	assert.Equal(t, time.Hour, config.Cfg.FLJWKSCacheTTL, "Default FLJWKSCacheTTL should be 1h")
+	assert.Equal(t, "", config.Cfg.FLJWTIssuer, "Default FLJWTIssuer should be empty")
+	assert.Equal(t, "", config.Cfg.FLJWTAudience, "Default FLJWTAudience should be empty")
 }
 
 func TestConfigCustomValues(t *testing.T) {
@@ -0,0 +1,138 @@
+package frequency
+
+import (
+	"backend/pkg/utils"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/m-mizutani/goerr"
+)
+
+// List is a word frequency list mapping words to their ranks, where 1 is the most frequent word
+type List struct {
+	ranks map[string]int
+}
+
+// NewList ranks the words in the given order
+func NewList(words []string) *List {
+	l := &List{ranks: make(map[string]int, len(words))}
+	for _, word := range words {
+		l.add(word)
+	}
+	return l
+}
+
+func (l *List) add(word string) {
+	key := utils.Normalize(word)
+	if key == "" {
+		return
+	}
+	// The first occurrence is the most frequent one
+	if _, ok := l.ranks[key]; !ok {
+		l.ranks[key] = len(l.ranks) + 1
+	}
+}
+
+// Len returns the number of ranked words
+func (l *List) Len() int {
+	return len(l.ranks)
+}
+
+// Rank returns the rank of the word compared after normalization
+func (l *List) Rank(word string) (int, bool) {
+	rank, ok := l.ranks[utils.Normalize(word)]
+	return rank, ok
+}
+
+// Load reads the frequency list file at path
+func Load(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to open frequency list : %s", path))
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read reads a frequency list sorted from the most frequent word, optionally gzipped.
+// Each line holds a word, and may have other columns such as a rank or a count separated
+// by tabs, commas or spaces. The first column which is not a number is taken as the word,
+// and the rank is the position of the line. Empty lines and lines starting with # are skipped.
+func Read(r io.Reader) (*List, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to decompress frequency list")
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	l := &List{ranks: make(map[string]int)}
+	scanner := bufio.NewScanner(br)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "\ufeff")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if word, ok := wordOf(line); ok {
+			l.add(word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, goerr.Wrap(err, "failed to read frequency list")
+	}
+	if l.Len() == 0 {
+		return nil, goerr.Wrap(fmt.Errorf("no words found in frequency list"))
+	}
+	return l, nil
+}
+
+// wordOf returns the first column of the line which is not a number
+func wordOf(line string) (string, bool) {
+	columns := strings.FieldsFunc(line, func(r rune) bool {
+		return r == '\t' || r == ',' || r == ' '
+	})
+	for _, column := range columns {
+		if _, err := strconv.ParseFloat(column, 64); err != nil {
+			return column, true
+		}
+	}
+	return "", false
+}
+
+// ErrNotConfigured is returned when no frequency list is configured
+var ErrNotConfigured = fmt.Errorf("frequency list is not configured")
+
+// Lazy loads the frequency list file on the first use
+type Lazy struct {
+	path string
+	once sync.Once
+	list *List
+	err  error
+}
+
+// NewLazy returns a Lazy loading the file at path. An empty path disables the frequency list.
+func NewLazy(path string) *Lazy {
+	return &Lazy{path: path}
+}
+
+// Get returns the frequency list, loading it if it is not loaded yet
+func (l *Lazy) Get() (*List, error) {
+	l.once.Do(func() {
+		if l.path == "" {
+			l.err = goerr.Wrap(ErrNotConfigured)
+			return
+		}
+		l.list, l.err = Load(l.path)
+	})
+	return l.list, l.err
+}
@@ -0,0 +1,112 @@
+package frequency
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected map[string]int
+	}{
+		{
+			name:     "Word per line",
+			input:    "the\nof\nrun\n",
+			expected: map[string]int{"the": 1, "of": 2, "run": 3},
+		},
+		{
+			name:     "Word and count",
+			input:    "\ufeffthe,5000\r\nof,3000\r\n",
+			expected: map[string]int{"the": 1, "of": 2},
+		},
+		{
+			name:     "Duplicates keep the first rank",
+			input:    "the\nThe\nrun\n",
+			expected: map[string]int{"the": 1, "run": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := Read(strings.NewReader(tc.input))
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, l.ranks)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_Load", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := Load("testdata/frequency.tsv")
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, l.Len()) // The header, the duplicate and the number are skipped
+		rank, ok := l.Rank("RUN")
+		assert.True(t, ok)
+		assert.Equal(t, 3, rank)
+		rank, ok = l.Rank("走る")
+		assert.True(t, ok)
+		assert.Equal(t, 4, rank)
+		_, ok = l.Rank("walk")
+		assert.False(t, ok)
+	})
+
+	t.Run("Normal_Read_Gzip", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte("the\nof\n"))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		l, err := Read(&buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, l.Len())
+	})
+
+	t.Run("Error_Read_Empty", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := Read(strings.NewReader("# header only\n"))
+
+		assert.Error(t, err)
+		assert.Nil(t, l)
+	})
+
+	t.Run("Error_Lazy_NotConfigured", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := NewLazy("").Get()
+
+		assert.ErrorIs(t, err, ErrNotConfigured)
+		assert.Nil(t, l)
+	})
+}
+
+func TestNewList(t *testing.T) {
+	t.Parallel()
+
+	l := NewList([]string{"the", "", "of"})
+
+	rank, ok := l.Rank("of")
+	assert.True(t, ok)
+	assert.Equal(t, 2, rank)
+}
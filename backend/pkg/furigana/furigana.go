@@ -0,0 +1,149 @@
+package furigana
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Segment is a part of an annotated text with the reading of its base text, if any
+type Segment struct {
+	Text    string
+	Reading string
+}
+
+// Parse splits a text annotated in the form 漢字[かんじ] into segments. When the annotation
+// follows kanji, only the kanji right before it take the reading, so that 走[はし]る and
+// 漢字[かんじ]の勉強[べんきょう] work. Otherwise, the reading belongs to the text back to
+// the previous space, punctuation or annotation, such as 走る[はしる]、駆ける[かける].
+// Brackets without a closing bracket or without a base text are kept as they are.
+func Parse(text string) []Segment {
+	var segments []Segment
+	var pending []rune
+
+	flush := func(runes []rune) {
+		if len(runes) == 0 {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].Reading == "" {
+			segments[n-1].Text += string(runes)
+			return
+		}
+		segments = append(segments, Segment{Text: string(runes)})
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '[' {
+			pending = append(pending, r)
+			continue
+		}
+
+		end := indexRune(runes[i+1:], ']')
+		start := baseStart(pending)
+		if end < 0 || start == len(pending) {
+			pending = append(pending, r)
+			continue
+		}
+
+		reading := strings.TrimSpace(string(runes[i+1 : i+1+end]))
+		flush(pending[:start])
+		segments = append(segments, Segment{Text: string(pending[start:]), Reading: reading})
+		pending = nil
+		i += end + 1
+	}
+	flush(pending)
+	return segments
+}
+
+// baseStart returns the index in runes where the base text of an annotation starts
+func baseStart(runes []rune) int {
+	i := len(runes)
+	if i > 0 && isKanji(runes[i-1]) {
+		for i > 0 && isKanji(runes[i-1]) {
+			i--
+		}
+		return i
+	}
+	for i > 0 && !isSeparator(runes[i-1]) {
+		i--
+	}
+	return i
+}
+
+func indexRune(runes []rune, target rune) int {
+	for i, r := range runes {
+		if r == target {
+			return i
+		}
+	}
+	return -1
+}
+
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) || r == '々' || r == '〆' || r == 'ヶ'
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || r == ']'
+}
+
+// HasAnnotation reports whether the text has any reading annotation
+func HasAnnotation(text string) bool {
+	for _, segment := range Parse(text) {
+		if segment.Reading != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Plain returns the text without annotations
+func Plain(text string) string {
+	var b strings.Builder
+	for _, segment := range Parse(text) {
+		b.WriteString(segment.Text)
+	}
+	return b.String()
+}
+
+// Kana returns the text with the annotated parts replaced by their readings
+func Kana(text string) string {
+	var b strings.Builder
+	for _, segment := range Parse(text) {
+		if segment.Reading != "" {
+			b.WriteString(segment.Reading)
+		} else {
+			b.WriteString(segment.Text)
+		}
+	}
+	return b.String()
+}
+
+// Ruby returns the text as HTML with the annotated parts in ruby elements,
+// such as <ruby>漢字<rt>かんじ</rt></ruby>. The text is escaped.
+func Ruby(text string) string {
+	var b strings.Builder
+	for _, segment := range Parse(text) {
+		if segment.Reading == "" {
+			b.WriteString(html.EscapeString(segment.Text))
+			continue
+		}
+		b.WriteString("<ruby>")
+		b.WriteString(html.EscapeString(segment.Text))
+		b.WriteString("<rt>")
+		b.WriteString(html.EscapeString(segment.Reading))
+		b.WriteString("</rt></ruby>")
+	}
+	return b.String()
+}
+
+// Annotate returns the word annotated with the reading as a whole, or the word itself
+// when the reading is empty or the same as the word
+func Annotate(word, reading string) string {
+	if reading == "" || reading == word {
+		return word
+	}
+	return word + "[" + reading + "]"
+}
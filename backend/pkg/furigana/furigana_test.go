@@ -0,0 +1,86 @@
+package furigana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []Segment
+	}{
+		{
+			name:     "Kanji",
+			input:    "漢字[かんじ]",
+			expected: []Segment{{Text: "漢字", Reading: "かんじ"}},
+		},
+		{
+			name:  "Kanji followed by kana",
+			input: "走[はし]る",
+			expected: []Segment{
+				{Text: "走", Reading: "はし"},
+				{Text: "る"},
+			},
+		},
+		{
+			name:  "Kanji between kana",
+			input: "漢字[かんじ]の勉強[べんきょう]",
+			expected: []Segment{
+				{Text: "漢字", Reading: "かんじ"},
+				{Text: "の"},
+				{Text: "勉強", Reading: "べんきょう"},
+			},
+		},
+		{
+			name:  "Whole words separated by punctuation",
+			input: "走る[はしる]、駆ける[かける]",
+			expected: []Segment{
+				{Text: "走る", Reading: "はしる"},
+				{Text: "、"},
+				{Text: "駆ける", Reading: "かける"},
+			},
+		},
+		{
+			name:     "Unclosed bracket",
+			input:    "漢字[かんじ",
+			expected: []Segment{{Text: "漢字[かんじ"}},
+		},
+		{
+			name:     "Bracket without a base text",
+			input:    "[かんじ]",
+			expected: []Segment{{Text: "[かんじ]"}},
+		},
+		{
+			name:     "No annotation",
+			input:    "田舎者",
+			expected: []Segment{{Text: "田舎者"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, Parse(tc.input))
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	input := "漢字[かんじ]の勉強[べんきょう]"
+
+	assert.True(t, HasAnnotation(input))
+	assert.False(t, HasAnnotation("漢字"))
+	assert.Equal(t, "漢字の勉強", Plain(input))
+	assert.Equal(t, "かんじのべんきょう", Kana(input))
+	assert.Equal(t, "<ruby>漢字<rt>かんじ</rt></ruby>の<ruby>勉強<rt>べんきょう</rt></ruby>", Ruby(input))
+	assert.Equal(t, "&lt;b&gt;", Ruby("<b>"))
+	assert.Equal(t, "走る[はしる]", Annotate("走る", "はしる"))
+	assert.Equal(t, "はしる", Annotate("はしる", "はしる"))
+}
@@ -0,0 +1,186 @@
+package jmdict
+
+import (
+	"backend/pkg/furigana"
+	"backend/pkg/utils"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/m-mizutani/goerr"
+)
+
+// MaxDefinitions is the number of senses or words a definition is made of
+const MaxDefinitions = 3
+
+// Dictionary is an in-memory store of entries indexed by writings, readings and English glosses
+type Dictionary struct {
+	entries []*Entry
+	byWord  map[string][]int
+	byGloss map[string][]int
+}
+
+// NewDictionary indexes the entries
+func NewDictionary(entries []*Entry) *Dictionary {
+	d := &Dictionary{
+		entries: entries,
+		byWord:  make(map[string][]int),
+		byGloss: make(map[string][]int),
+	}
+	for i, entry := range entries {
+		for _, word := range append(append([]string{}, entry.Kanji...), entry.Readings...) {
+			addIndex(d.byWord, word, i)
+		}
+		for _, sense := range entry.Senses {
+			for _, gloss := range sense.Glosses {
+				addIndex(d.byGloss, gloss, i)
+				// Verbs are glossed as "to run", and looked up as "run"
+				if trimmed := strings.TrimPrefix(utils.Normalize(gloss), "to "); trimmed != utils.Normalize(gloss) {
+					addIndex(d.byGloss, trimmed, i)
+				}
+			}
+		}
+	}
+	return d
+}
+
+func addIndex(index map[string][]int, key string, i int) {
+	key = utils.Normalize(key)
+	if key == "" {
+		return
+	}
+	// Entries are indexed in order, so a duplicate can only be the last one
+	if ids := index[key]; len(ids) > 0 && ids[len(ids)-1] == i {
+		return
+	}
+	index[key] = append(index[key], i)
+}
+
+// Len returns the number of entries
+func (d *Dictionary) Len() int {
+	return len(d.entries)
+}
+
+// Lookup finds the entries of a Japanese word by its writing or reading, or of an
+// English word by its gloss. Common words come first.
+func (d *Dictionary) Lookup(term string) []*Entry {
+	index := d.byGloss
+	if IsJapanese(term) {
+		index = d.byWord
+	}
+
+	ids := index[utils.Normalize(term)]
+	entries := make([]*Entry, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, d.entries[id])
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Common && !entries[j].Common
+	})
+	return entries
+}
+
+// Define makes a definition of the term to fill the back of a card. A Japanese word is
+// defined by the English meanings of its first entry, and an English word by the Japanese
+// words glossed with it. Up to MaxDefinitions senses are returned with the definition.
+func (d *Dictionary) Define(term string) (string, []string, bool) {
+	entries := d.Lookup(term)
+	if len(entries) == 0 {
+		return "", nil, false
+	}
+
+	var senses []string
+	separator := "; "
+	if IsJapanese(term) {
+		senses = entries[0].Meanings()
+	} else {
+		separator = "、"
+		for _, entry := range entries {
+			senses = appendUnique(senses, entry.Headword())
+		}
+	}
+	if len(senses) > MaxDefinitions {
+		senses = senses[:MaxDefinitions]
+	}
+	if len(senses) == 0 {
+		return "", nil, false
+	}
+	return strings.Join(senses, separator), senses, true
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// ErrNotConfigured is returned when no dictionary file is configured
+var ErrNotConfigured = fmt.Errorf("dictionary is not configured")
+
+// Lazy loads the dictionary file on the first use, since loading JMdict takes a few seconds
+type Lazy struct {
+	path string
+	once sync.Once
+	dic  *Dictionary
+	err  error
+}
+
+// NewLazy returns a Lazy loading the file at path. An empty path disables the dictionary.
+func NewLazy(path string) *Lazy {
+	return &Lazy{path: path}
+}
+
+// Get returns the dictionary, loading it if it is not loaded yet
+func (l *Lazy) Get() (*Dictionary, error) {
+	l.once.Do(func() {
+		if l.path == "" {
+			l.err = goerr.Wrap(ErrNotConfigured)
+			return
+		}
+		l.dic, l.err = Load(l.path)
+	})
+	return l.dic, l.err
+}
+
+// Reading returns the reading of a word written in kanji. Words written in kana have no reading.
+func (d *Dictionary) Reading(word string) (string, bool) {
+	for _, entry := range d.Lookup(word) {
+		for _, kanji := range entry.Kanji {
+			if kanji == word && len(entry.Readings) > 0 {
+				return entry.Readings[0], true
+			}
+		}
+	}
+	return "", false
+}
+
+// Annotate annotates the words found in the text with their readings in the form 漢字[かんじ].
+// It returns an empty string when none of the words has a reading.
+func (d *Dictionary) Annotate(text string, words []string) string {
+	var b strings.Builder
+	rest := text
+	annotated := false
+	for _, word := range words {
+		reading, ok := d.Reading(word)
+		if !ok {
+			continue
+		}
+		i := strings.Index(rest, word)
+		if i < 0 {
+			continue
+		}
+		b.WriteString(rest[:i])
+		b.WriteString(furigana.Annotate(word, reading))
+		rest = rest[i+len(word):]
+		annotated = true
+	}
+	if !annotated {
+		return ""
+	}
+	b.WriteString(rest)
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package jmdict
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_Load_JMdict", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := Load("testdata/JMdict_e.xml")
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, d.Len())
+		entries := d.Lookup("走る")
+		require.Len(t, entries, 1)
+		assert.Equal(t, 1596520, entries[0].ID)
+		assert.Equal(t, []string{"はしる"}, entries[0].Readings)
+		assert.True(t, entries[0].Common)
+		assert.Equal(t, []string{"v5r", "vi"}, entries[0].Senses[0].PartsOfSpeech)
+		assert.Equal(t, []string{"to run"}, entries[0].Senses[0].Glosses) // Glosses in other languages are skipped
+		assert.Equal(t, []string{"v5r", "vi"}, entries[0].Senses[1].PartsOfSpeech)
+	})
+
+	t.Run("Normal_Load_EDICT", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := Load("testdata/edict.txt")
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, d.Len()) // The header is skipped
+		entries := d.Lookup("はしる")
+		require.Len(t, entries, 1)
+		assert.Equal(t, []string{"走る", "奔る"}, entries[0].Kanji)
+		assert.True(t, entries[0].Common)
+		assert.Equal(t, []string{"to run", "to travel (movement of vehicles), to drive"}, entries[0].Meanings())
+		assert.Equal(t, []string{"いなか者"}, d.Lookup("いなか者")[0].Readings)
+	})
+
+	t.Run("Normal_Read_EUCJPAndGzip", func(t *testing.T) {
+		t.Parallel()
+		data, err := os.ReadFile("testdata/edict.txt")
+		require.NoError(t, err)
+		encoded, err := japanese.EUCJP.NewEncoder().Bytes(data)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err = gz.Write(encoded)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		entries, err := Read(&buf)
+
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, []string{"走る", "奔る"}, entries[0].Kanji)
+	})
+
+	t.Run("Error_Load_NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := Load("testdata/not_found.xml")
+
+		assert.Error(t, err)
+		assert.Nil(t, d)
+	})
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	d, err := Load("testdata/JMdict_e.xml")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		term     string
+		expected []string
+	}{
+		{"Kanji", "駆ける", []string{"駆ける"}},
+		{"Reading", "いなかもの", []string{"田舎者"}},
+		{"Gloss", "rube", []string{"田舎者"}},
+		{"Gloss of a verb, common words first", "Run", []string{"走る", "駆ける"}},
+		{"Not found", "walk", []string{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			headwords := []string{}
+			for _, entry := range d.Lookup(tc.term) {
+				headwords = append(headwords, entry.Headword())
+			}
+			assert.Equal(t, tc.expected, headwords)
+		})
+	}
+}
+
+func TestDefine(t *testing.T) {
+	t.Parallel()
+	d, err := Load("testdata/JMdict_e.xml")
+	require.NoError(t, err)
+
+	definition, senses, ok := d.Define("run")
+	assert.True(t, ok)
+	assert.Equal(t, "走る、駆ける", definition)
+	assert.Equal(t, []string{"走る", "駆ける"}, senses)
+
+	definition, senses, ok = d.Define("走る")
+	assert.True(t, ok)
+	assert.Equal(t, "to run; to travel (movement of vehicles), to drive", definition)
+	assert.Len(t, senses, 2)
+
+	_, _, ok = d.Define("walk")
+	assert.False(t, ok)
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+	d, err := Load("testdata/JMdict_e.xml")
+	require.NoError(t, err)
+
+	reading, ok := d.Reading("駆ける")
+	assert.True(t, ok)
+	assert.Equal(t, "かける", reading)
+	_, ok = d.Reading("はしる") // Kana has no reading
+	assert.False(t, ok)
+
+	assert.Equal(t, "走る[はしる]、駆ける[かける]", d.Annotate("走る、駆ける", []string{"走る", "駆ける"}))
+	assert.Equal(t, "歩く、走る[はしる]", d.Annotate("歩く、走る", []string{"歩く", "走る"}))
+	assert.Equal(t, "", d.Annotate("歩く", []string{"歩く"}))
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewLazy("testdata/JMdict_e.xml").Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.Len())
+
+	d, err = NewLazy("").Get()
+	assert.ErrorIs(t, err, ErrNotConfigured)
+	assert.Nil(t, d)
+}
@@ -0,0 +1,54 @@
+package jmdict
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Entry is an entry of JMdict, or a line of EDICT
+type Entry struct {
+	ID       int
+	Kanji    []string
+	Readings []string
+	Senses   []Sense
+	// Common is true for the entries marked as common words, such as news1 and ichi1
+	Common bool
+}
+
+// Sense is a meaning of an entry
+type Sense struct {
+	PartsOfSpeech []string
+	Glosses       []string
+}
+
+// Headword returns the first kanji writing of the entry, or the first reading for kana-only words
+func (e *Entry) Headword() string {
+	if len(e.Kanji) > 0 {
+		return e.Kanji[0]
+	}
+	if len(e.Readings) > 0 {
+		return e.Readings[0]
+	}
+	return ""
+}
+
+// Meanings returns the glosses of each sense joined by commas
+func (e *Entry) Meanings() []string {
+	meanings := make([]string, 0, len(e.Senses))
+	for _, sense := range e.Senses {
+		if len(sense.Glosses) > 0 {
+			meanings = append(meanings, strings.Join(sense.Glosses, ", "))
+		}
+	}
+	return meanings
+}
+
+// IsJapanese reports whether the term contains kana or kanji
+func IsJapanese(term string) bool {
+	for _, r := range term {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+package jmdict
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/m-mizutani/goerr"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// Priority markers of JMdict treated as common words, and the (P) marker of EDICT
+var commonPriorities = map[string]bool{
+	"news1": true, "ichi1": true, "spec1": true, "spec2": true, "gai1": true,
+}
+
+const edictCommonMarker = "(P)"
+
+// Load reads a JMdict XML file or an EDICT file, optionally gzipped, into a dictionary
+func Load(path string) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open dictionary")
+	}
+	defer f.Close()
+
+	entries, err := Read(f)
+	if err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to load dictionary : %s", path))
+	}
+	return NewDictionary(entries), nil
+}
+
+// Read reads the entries of JMdict XML or EDICT. The format and gzip are detected from the content,
+// and EDICT in EUC-JP, the encoding it is distributed in, is converted into UTF-8.
+func Read(r io.Reader) ([]*Entry, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to decompress dictionary")
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	head, _ := br.Peek(4096)
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))), []byte("<")) {
+		return readXML(br)
+	}
+	if !validUTF8Prefix(head) {
+		return readEDICT(japanese.EUCJP.NewDecoder().Reader(br))
+	}
+	return readEDICT(br)
+}
+
+// validUTF8Prefix checks the bytes up to the last line break, since the rest may end in the middle of a rune
+func validUTF8Prefix(b []byte) bool {
+	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+		b = b[:i]
+	}
+	return utf8.Valid(b)
+}
+
+type xmlEntry struct {
+	Seq   int `xml:"ent_seq"`
+	Kanji []struct {
+		Keb string   `xml:"keb"`
+		Pri []string `xml:"ke_pri"`
+	} `xml:"k_ele"`
+	Readings []struct {
+		Reb string   `xml:"reb"`
+		Pri []string `xml:"re_pri"`
+	} `xml:"r_ele"`
+	Senses []struct {
+		Pos     []string `xml:"pos"`
+		Glosses []struct {
+			Text string `xml:",chardata"`
+			Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+		} `xml:"gloss"`
+	} `xml:"sense"`
+}
+
+// readXML reads JMdict entry by entry. The entities of parts of speech defined in the DTD,
+// such as &v5r;, are kept as their names.
+func readXML(r io.Reader) ([]*Entry, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+
+	var entries []*Entry
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to read JMdict")
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "entry" {
+			continue
+		}
+
+		var xe xmlEntry
+		if err := decoder.DecodeElement(&xe, &start); err != nil {
+			return nil, goerr.Wrap(err, "failed to read JMdict entry")
+		}
+		entries = append(entries, xe.toEntry())
+	}
+	return entries, nil
+}
+
+func (xe *xmlEntry) toEntry() *Entry {
+	entry := &Entry{ID: xe.Seq}
+	for _, k := range xe.Kanji {
+		entry.Kanji = append(entry.Kanji, k.Keb)
+		entry.Common = entry.Common || isCommon(k.Pri)
+	}
+	for _, r := range xe.Readings {
+		entry.Readings = append(entry.Readings, r.Reb)
+		entry.Common = entry.Common || isCommon(r.Pri)
+	}
+
+	var pos []string
+	for _, s := range xe.Senses {
+		// A sense without parts of speech has the same ones as the previous sense
+		if len(s.Pos) > 0 {
+			pos = pos[:0:0]
+			for _, p := range s.Pos {
+				pos = append(pos, strings.Trim(p, "&;"))
+			}
+		}
+		sense := Sense{PartsOfSpeech: pos}
+		for _, g := range s.Glosses {
+			if g.Lang == "" || g.Lang == "eng" {
+				sense.Glosses = append(sense.Glosses, strings.TrimSpace(g.Text))
+			}
+		}
+		if len(sense.Glosses) > 0 {
+			entry.Senses = append(entry.Senses, sense)
+		}
+	}
+	return entry
+}
+
+func isCommon(priorities []string) bool {
+	for _, p := range priorities {
+		if commonPriorities[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// readEDICT reads lines like "漢字;漢字(P) [かんじ] /(n) (1) Chinese characters/(2) kanji/(P)/EntL1234X/"
+func readEDICT(r io.Reader) ([]*Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var entries []*Entry
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if entry, ok := parseEDICTLine(line, len(entries)+1); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, goerr.Wrap(err, "failed to read EDICT")
+	}
+	return entries, nil
+}
+
+func parseEDICTLine(line string, id int) (*Entry, bool) {
+	i := strings.Index(line, " /")
+	// The first line of EDICT is a header starting with "？？？"
+	if i < 0 || strings.HasPrefix(line, "？") {
+		return nil, false
+	}
+	head, body := line[:i], strings.Trim(line[i+2:], "/")
+	entry := &Entry{ID: id}
+
+	words := head
+	if j := strings.Index(head, "["); j >= 0 {
+		words = strings.TrimSpace(head[:j])
+		entry.Readings = splitEDICTWords(strings.Trim(head[j:], "[] "), entry)
+		entry.Kanji = splitEDICTWords(words, entry)
+	} else {
+		entry.Readings = splitEDICTWords(words, entry)
+	}
+
+	var sense *Sense
+	for _, field := range strings.Split(body, "/") {
+		field = strings.TrimSpace(field)
+		if field == edictCommonMarker {
+			entry.Common = true
+			continue
+		}
+		if field == "" || strings.HasPrefix(field, "EntL") {
+			continue
+		}
+
+		var pos []string
+		newSense := sense == nil
+		for strings.HasPrefix(field, "(") {
+			end := strings.Index(field, ")")
+			if end < 0 {
+				break
+			}
+			tag := field[1:end]
+			field = strings.TrimSpace(field[end+1:])
+			if isSenseNumber(tag) {
+				newSense = true
+			} else {
+				pos = append(pos, strings.Split(tag, ",")...)
+			}
+		}
+		if newSense {
+			entry.Senses = append(entry.Senses, Sense{PartsOfSpeech: pos})
+			sense = &entry.Senses[len(entry.Senses)-1]
+		}
+		if field != "" {
+			sense.Glosses = append(sense.Glosses, field)
+		}
+	}
+	return entry, len(entry.Senses) > 0
+}
+
+// splitEDICTWords splits writings separated by semicolons, dropping markers such as (P) and (iK)
+func splitEDICTWords(s string, entry *Entry) []string {
+	var words []string
+	for _, word := range strings.Split(s, ";") {
+		if strings.Contains(word, edictCommonMarker) {
+			entry.Common = true
+		}
+		if j := strings.Index(word, "("); j >= 0 {
+			word = word[:j]
+		}
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func isSenseNumber(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
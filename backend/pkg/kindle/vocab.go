@@ -0,0 +1,106 @@
+package kindle
+
+import (
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+	_ "modernc.org/sqlite"
+)
+
+// Word is a word looked up on a Kindle with the sentences it is found in
+type Word struct {
+	ID   string
+	Word string
+	// Stem is the dictionary form of the word
+	Stem    string
+	Lang    string
+	Lookups []*Lookup
+}
+
+// Lookup is a lookup of a word in a book
+type Lookup struct {
+	// Usage is the sentence of the book the word is looked up in
+	Usage     string
+	BookTitle string
+	Authors   string
+	Time      time.Time
+}
+
+// Headword returns the stem of the word, or the word itself when the stem is unknown
+func (w *Word) Headword() string {
+	if w.Stem != "" {
+		return w.Stem
+	}
+	return w.Word
+}
+
+// ReadVocabulary reads the Vocabulary Builder database (vocab.db) of a Kindle.
+// The database is written into a temporary file since SQLite needs a file to open.
+func ReadVocabulary(data []byte) ([]*Word, error) {
+	tmp, err := os.CreateTemp("", "vocab-*.db")
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, goerr.Wrap(err, "failed to write vocabulary")
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, goerr.Wrap(err, "failed to write vocabulary")
+	}
+
+	return ReadVocabularyFile(tmp.Name())
+}
+
+// ReadVocabularyFile reads the Vocabulary Builder database at path. Words are
+// ordered by the time they are first looked up, and lookups by time.
+func ReadVocabularyFile(path string) ([]*Word, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open vocabulary")
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT w.id, COALESCE(w.word, ''), COALESCE(w.stem, ''), COALESCE(w.lang, ''),
+		       COALESCE(l.usage, ''), COALESCE(b.title, ''), COALESCE(b.authors, ''), COALESCE(l.timestamp, 0)
+		FROM WORDS w
+		LEFT JOIN LOOKUPS l ON l.word_key = w.id
+		LEFT JOIN BOOK_INFO b ON b.id = l.book_key
+		ORDER BY w.timestamp, w.id, l.timestamp`)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read words")
+	}
+	defer rows.Close()
+
+	var words []*Word
+	var current *Word
+	for rows.Next() {
+		var word Word
+		var lookup Lookup
+		var timestamp int64
+		if err := rows.Scan(&word.ID, &word.Word, &word.Stem, &word.Lang,
+			&lookup.Usage, &lookup.BookTitle, &lookup.Authors, &timestamp); err != nil {
+			return nil, goerr.Wrap(err, "failed to read word")
+		}
+
+		if current == nil || current.ID != word.ID {
+			current = &word
+			words = append(words, current)
+		}
+		if lookup.Usage != "" {
+			// Timestamps are in epoch milliseconds
+			lookup.Time = time.UnixMilli(timestamp).UTC()
+			current.Lookups = append(current.Lookups, &lookup)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, goerr.Wrap(err, "failed to read words")
+	}
+
+	return words, nil
+}
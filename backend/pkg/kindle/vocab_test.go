@@ -0,0 +1,79 @@
+package kindle
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVocabularySQL creates a vocabulary with the schema of Kindle
+const testVocabularySQL = `
+CREATE TABLE WORDS (id TEXT PRIMARY KEY NOT NULL UNIQUE, word TEXT, stem TEXT, lang TEXT,
+    category INTEGER DEFAULT 0, timestamp INTEGER DEFAULT 0, profileid TEXT);
+CREATE TABLE LOOKUPS (id TEXT PRIMARY KEY NOT NULL, word_key TEXT, book_key TEXT, dict_key TEXT,
+    pos TEXT, usage TEXT, timestamp INTEGER DEFAULT 0);
+CREATE TABLE BOOK_INFO (id TEXT PRIMARY KEY NOT NULL, asin TEXT, guid TEXT, lang TEXT, title TEXT, authors TEXT);
+INSERT INTO WORDS VALUES ('en:running', 'running', 'run', 'en', 0, 1719878400000, '');
+INSERT INTO WORDS VALUES ('en:serendipity', 'serendipity', 'serendipity', 'en', 0, 1719792000000, '');
+INSERT INTO WORDS VALUES ('ja:走る', '走る', NULL, 'ja', 0, 1719964800000, '');
+INSERT INTO BOOK_INFO VALUES ('book1', 'B000', 'g1', 'en', 'The Book', 'An Author');
+INSERT INTO LOOKUPS VALUES ('l1', 'en:running', 'book1', '', '', 'He was running late.', 1719878400000);
+INSERT INTO LOOKUPS VALUES ('l2', 'en:running', 'book1', '', '', 'They kept running.', 1719964800000);
+INSERT INTO LOOKUPS VALUES ('l3', 'en:serendipity', 'book1', '', '', 'It was pure serendipity.', 1719792000000);
+`
+
+func writeTestVocabulary(t *testing.T) []byte {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "vocab.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(testVocabularySQL)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func TestReadVocabulary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_ReadVocabulary", func(t *testing.T) {
+		t.Parallel()
+
+		words, err := ReadVocabulary(writeTestVocabulary(t))
+
+		require.NoError(t, err)
+		require.Len(t, words, 3)
+
+		assert.Equal(t, "serendipity", words[0].Headword())
+		require.Len(t, words[0].Lookups, 1)
+		assert.Equal(t, "The Book", words[0].Lookups[0].BookTitle)
+
+		assert.Equal(t, "running", words[1].Word)
+		assert.Equal(t, "run", words[1].Headword())
+		require.Len(t, words[1].Lookups, 2)
+		assert.Equal(t, "He was running late.", words[1].Lookups[0].Usage)
+		assert.Equal(t, time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC), words[1].Lookups[1].Time)
+
+		assert.Equal(t, "走る", words[2].Headword()) // Falls back to the word without a stem
+		assert.Equal(t, "ja", words[2].Lang)
+		assert.Empty(t, words[2].Lookups)
+	})
+
+	t.Run("Error_ReadVocabulary_NotVocabulary", func(t *testing.T) {
+		t.Parallel()
+
+		words, err := ReadVocabulary([]byte("not a database"))
+
+		assert.Error(t, err)
+		assert.Nil(t, words)
+	})
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/logger"
+	"net/http"
+
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// TokenContext is the key of the claims of the validated JWT in the Echo context
+var TokenContext = "user"
+
+// JWTMiddleware returns an Echo middleware function that validates the JWT in the jwt cookie
+// with the verifier and stores its claims in the Echo context under the TokenContext key. When
+// optional is true, requests without a valid token are passed on as anonymous instead of rejected.
+func JWTMiddleware(verifier *auth.Verifier, optional bool) echo.MiddlewareFunc {
+	cfg := echojwt.Config{
+		TokenLookup: "cookie:jwt",
+		ContextKey:  TokenContext,
+		ParseTokenFunc: func(c echo.Context, token string) (interface{}, error) {
+			return verifier.Parse(token)
+		},
+	}
+	if optional {
+		cfg.ContinueOnIgnoredError = true
+		cfg.ErrorHandler = func(c echo.Context, err error) error {
+			return nil
+		}
+	}
+	return echojwt.WithConfig(cfg)
+}
+
+// ViewerCtxMiddleware returns an Echo middleware function that puts the ID of the user of the
+// validated JWT, as resolved by resolve, into the request context, which can be accessed using
+// auth.ViewerID.
+func ViewerCtxMiddleware(resolve auth.ViewerResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get(TokenContext).(*auth.Claims)
+			if !ok {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			userID, err := resolve(ctx, claims)
+			if err != nil {
+				logger.Logger.ErrorContext(ctx, "Failed to resolve viewer", "err", err)
+				return echo.NewHTTPError(http.StatusUnauthorized, "Failed to resolve viewer")
+			}
+
+			c.SetRequest(c.Request().WithContext(auth.WithViewer(ctx, userID)))
+			return next(c)
+		}
+	}
+}
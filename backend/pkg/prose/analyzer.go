@@ -0,0 +1,138 @@
+package prose
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ikawaha/kagome-dict/ipa"
+	"github.com/ikawaha/kagome/v2/tokenizer"
+	"github.com/m-mizutani/goerr"
+)
+
+// Word is a word of a text reduced to its dictionary form
+type Word struct {
+	// Surface is the word as it appears first in the text
+	Surface string
+	// BaseForm is the dictionary form of the word, such as 走る for 走った
+	BaseForm string
+	// Reading is the reading of the dictionary form in hiragana
+	Reading string
+	// PartOfSpeech is the part of speech in the IPA dictionary, such as 動詞
+	PartOfSpeech string
+	// Count is the number of times the word appears in the text
+	Count int
+}
+
+// skippedPartsOfSpeech are the parts of speech not worth a card, such as particles
+var skippedPartsOfSpeech = map[string]bool{
+	"助詞":   true,
+	"助動詞":  true,
+	"記号":   true,
+	"フィラー": true,
+	"その他":  true,
+	"接頭詞":  true,
+	"接続詞":  true,
+	"連体詞":  true,
+}
+
+// skippedSubclasses are the subclasses of nouns and verbs not worth a card,
+// such as numbers, pronouns and suffixes
+var skippedSubclasses = map[string]bool{
+	"数":   true,
+	"代名詞": true,
+	"非自立": true,
+	"接尾":  true,
+}
+
+// Analyzer splits a Japanese text into words with the morphological analyzer kagome
+type Analyzer struct {
+	once      sync.Once
+	tokenizer *tokenizer.Tokenizer
+	err       error
+}
+
+// NewAnalyzer returns an Analyzer. The IPA dictionary is loaded on the first use,
+// since it takes a while and is not needed by most requests.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{}
+}
+
+func (a *Analyzer) load() (*tokenizer.Tokenizer, error) {
+	a.once.Do(func() {
+		a.tokenizer, a.err = tokenizer.New(ipa.Dict(), tokenizer.OmitBosEos())
+		if a.err != nil {
+			a.err = goerr.Wrap(a.err, "failed to load tokenizer")
+		}
+	})
+	return a.tokenizer, a.err
+}
+
+// Words returns the content words of the text in their dictionary form in the order they first appear.
+// Particles, auxiliary verbs, symbols, numbers and words without Japanese letters are left out.
+func (a *Analyzer) Words(text string) ([]*Word, error) {
+	t, err := a.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var words []*Word
+	index := make(map[string]*Word)
+	for _, token := range t.Analyze(text, tokenizer.Search) {
+		pos := token.POS()
+		if len(pos) == 0 || skippedPartsOfSpeech[pos[0]] || (len(pos) > 1 && skippedSubclasses[pos[1]]) {
+			continue
+		}
+		if !hasJapanese(token.Surface) {
+			continue
+		}
+
+		baseForm, ok := token.BaseForm()
+		if !ok || baseForm == "*" {
+			baseForm = token.Surface
+		}
+		if word, ok := index[baseForm]; ok {
+			word.Count++
+			continue
+		}
+
+		reading, ok := token.Reading()
+		if !ok || reading == "*" {
+			reading = ""
+		}
+		// The reading is of the surface, so it only fits the dictionary form when they are the same
+		if baseForm != token.Surface {
+			reading = ""
+		}
+
+		word := &Word{
+			Surface:      token.Surface,
+			BaseForm:     baseForm,
+			Reading:      ToHiragana(reading),
+			PartOfSpeech: pos[0],
+			Count:        1,
+		}
+		index[baseForm] = word
+		words = append(words, word)
+	}
+	return words, nil
+}
+
+// ToHiragana converts katakana into hiragana. Other characters such as the long vowel mark are kept.
+func ToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 'ァ' + 'ぁ'
+		}
+		return r
+	}, s)
+}
+
+func hasJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
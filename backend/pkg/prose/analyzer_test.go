@@ -0,0 +1,76 @@
+package prose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+	analyzer := NewAnalyzer()
+
+	testCases := []struct {
+		name      string
+		text      string
+		baseForms []string
+	}{
+		{
+			name:      "Verbs are reduced to the dictionary form",
+			text:      "猫が庭を走った。",
+			baseForms: []string{"猫", "庭", "走る"},
+		},
+		{
+			name:      "Adjectives and repeated words",
+			text:      "寒い朝に、寒かったので家で本を読みました。",
+			baseForms: []string{"寒い", "朝", "家", "本", "読む"},
+		},
+		{
+			name:      "Numbers, pronouns and latin letters are skipped",
+			text:      "私はGoで3つのプログラムを書く",
+			baseForms: []string{"プログラム", "書く"},
+		},
+		{
+			name:      "Empty text",
+			text:      "",
+			baseForms: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			words, err := analyzer.Words(tc.text)
+
+			require.NoError(t, err)
+			baseForms := []string{}
+			for _, word := range words {
+				baseForms = append(baseForms, word.BaseForm)
+			}
+			assert.Equal(t, tc.baseForms, baseForms)
+		})
+	}
+}
+
+func TestWords_Details(t *testing.T) {
+	t.Parallel()
+
+	words, err := NewAnalyzer().Words("寒い朝に、寒かった")
+
+	require.NoError(t, err)
+	require.Len(t, words, 2)
+	assert.Equal(t, &Word{Surface: "寒い", BaseForm: "寒い", Reading: "さむい", PartOfSpeech: "形容詞", Count: 2}, words[0])
+	assert.Equal(t, "あさ", words[1].Reading)
+	assert.Equal(t, "名詞", words[1].PartOfSpeech)
+}
+
+func TestToHiragana(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "かんじ", ToHiragana("カンジ"))
+	assert.Equal(t, "こーひー", ToHiragana("コーヒー"))
+	assert.Equal(t, "abc漢字", ToHiragana("abc漢字"))
+}
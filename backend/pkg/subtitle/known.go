@@ -0,0 +1,80 @@
+package subtitle
+
+import (
+	"backend/pkg/prose"
+	"backend/pkg/utils"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits a line into words
+type Tokenizer func(text string) []string
+
+// SplitWords is a Tokenizer for languages separating words with spaces and punctuation
+func SplitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\'' && r != '-'
+	})
+}
+
+// NewJapaneseTokenizer returns a Tokenizer splitting Japanese into the dictionary forms of its content words
+// with the analyzer, such as 今日, いい and 天気 for 今日はいい天気だね. Words of other languages are split
+// with SplitWords. Japanese the analyzer fails on is kept as one word, so that the line is not left out.
+func NewJapaneseTokenizer(analyzer *prose.Analyzer) Tokenizer {
+	return func(text string) []string {
+		var words []string
+		for _, word := range SplitWords(text) {
+			if !hasJapanese(word) {
+				words = append(words, word)
+				continue
+			}
+			analyzed, err := analyzer.Words(word)
+			if err != nil {
+				words = append(words, word)
+				continue
+			}
+			for _, w := range analyzed {
+				words = append(words, w.BaseForm)
+			}
+		}
+		return words
+	}
+}
+
+func hasJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
+
+// KnownWords is a set of words compared after normalization
+type KnownWords map[string]struct{}
+
+// NewKnownWords returns a set of the words
+func NewKnownWords(words []string) KnownWords {
+	known := make(KnownWords, len(words))
+	for _, word := range words {
+		known[utils.Normalize(word)] = struct{}{}
+	}
+	return known
+}
+
+// Contains reports whether the word is known
+func (k KnownWords) Contains(word string) bool {
+	_, ok := k[utils.Normalize(word)]
+	return ok
+}
+
+// AllKnown reports whether every word of the text is known.
+// A text without words is regarded as known since there is nothing to learn.
+func (k KnownWords) AllKnown(text string, tokenize Tokenizer) bool {
+	for _, word := range tokenize(text) {
+		if !k.Contains(word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package subtitle
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultPairTolerance is how far apart cues without an overlap can be to be paired
+const DefaultPairTolerance = 500 * time.Millisecond
+
+// Pair is a cue with the text of the other language track shown at the same time
+type Pair struct {
+	*Cue
+	Translation string
+}
+
+// PairTracks pairs up two language tracks by timestamp. Each cue of the secondary track
+// goes to the primary cue it overlaps the most, or the nearest one within tolerance,
+// so that a line split into two cues in one track is still paired with the whole line.
+// Primary cues without a counterpart have an empty translation.
+func PairTracks(primary, secondary []*Cue, tolerance time.Duration) []*Pair {
+	translations := make([][]string, len(primary))
+
+	for _, cue := range secondary {
+		best := -1
+		var bestOverlap time.Duration
+		var bestGap time.Duration
+		for i, p := range primary {
+			if overlap := min(p.End, cue.End) - max(p.Start, cue.Start); overlap > 0 {
+				if overlap > bestOverlap {
+					best, bestOverlap = i, overlap
+				}
+				continue
+			}
+			if bestOverlap > 0 {
+				continue
+			}
+			gap := max(p.Start-cue.End, cue.Start-p.End)
+			if gap <= tolerance && (best < 0 || gap < bestGap) {
+				best, bestGap = i, gap
+			}
+		}
+		if best >= 0 {
+			translations[best] = append(translations[best], cue.Text)
+		}
+	}
+
+	pairs := make([]*Pair, len(primary))
+	for i, cue := range primary {
+		pairs[i] = &Pair{Cue: cue, Translation: strings.Join(translations[i], " ")}
+	}
+	return pairs
+}
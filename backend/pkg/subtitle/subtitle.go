@@ -0,0 +1,139 @@
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/m-mizutani/goerr"
+)
+
+// Cue is a line of subtitles shown between Start and End
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// timingSeparator separates the start and the end of a cue in both SRT and WebVTT
+const timingSeparator = "-->"
+
+var (
+	// Formatting tags such as <i> and <c.yellow>, and the override tags of SRT such as {\an8}
+	formattingTag = regexp.MustCompile(`<[^>]*>|\{\\[^}]*}`)
+	spaces        = regexp.MustCompile(`\s+`)
+)
+
+// Parse reads subtitles in SRT or WebVTT. Cue numbers, cue identifiers, the WebVTT header,
+// NOTE and STYLE blocks are skipped since only blocks with a timing line are read as cues.
+// Cues without letters, such as music notes, are dropped.
+func Parse(r io.Reader) ([]*Cue, error) {
+	scanner := bufio.NewScanner(r)
+	var cues []*Cue
+	var block []string
+	lineNo := 0
+
+	flush := func() error {
+		defer func() { block = block[:0] }()
+		for i, line := range block {
+			if !strings.Contains(line, timingSeparator) {
+				continue
+			}
+			start, end, err := parseTiming(line)
+			if err != nil {
+				return goerr.Wrap(err, fmt.Errorf("invalid timing at line %d", lineNo-len(block)+i))
+			}
+			text := cleanText(block[i+1:])
+			if hasLetter(text) {
+				cues = append(cues, &Cue{Start: start, End: end, Text: text})
+			}
+			return nil
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.TrimSpace(line) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, goerr.Wrap(err, "failed to read subtitles")
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return cues, nil
+}
+
+// parseTiming parses "00:00:01,000 --> 00:00:02,500" of SRT and "00:01.000 --> 00:02.500 align:start" of WebVTT
+func parseTiming(line string) (time.Duration, time.Duration, error) {
+	parts := strings.SplitN(line, timingSeparator, 2)
+	start, err := parseTimestamp(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	// Cue settings of WebVTT follow the end
+	fields := strings.Fields(parts[1])
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("missing end of cue : %s", line)
+	}
+	end, err := parseTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseTimestamp parses hh:mm:ss,mmm or mm:ss.mmm
+func parseTimestamp(s string) (time.Duration, error) {
+	parts := strings.Split(strings.Replace(s, ",", ".", 1), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp : %s", s)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp : %s", s)
+	}
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+
+	for i, unit := range []time.Duration{time.Minute, time.Hour}[:len(parts)-1] {
+		v, err := strconv.Atoi(parts[len(parts)-2-i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp : %s", s)
+		}
+		d += time.Duration(v) * unit
+	}
+	return d, nil
+}
+
+// cleanText joins the lines of a cue into a line without formatting tags
+func cleanText(lines []string) string {
+	text := formattingTag.ReplaceAllString(strings.Join(lines, " "), "")
+	return strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package subtitle
+
+import (
+	"backend/pkg/prose"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSRT = "\ufeff1\r\n00:00:01,000 --> 00:00:02,500\r\n<i>今日は</i>\r\nいい天気だね\r\n\r\n" +
+	"2\r\n00:00:03,000 --> 00:00:04,000\r\n♪～\r\n\r\n" +
+	"3\r\n00:00:05,000 --> 00:00:07,000\r\n{\\an8}行こうか\r\n"
+
+const testVTT = `WEBVTT
+
+NOTE This is a comment
+
+STYLE
+::cue { color: yellow }
+
+intro
+00:01.000 --> 00:01.800 align:start
+<c.yellow>Nice weather</c>
+
+00:01.800 --> 00:02.400
+today.
+
+01:00:05.000 --> 01:00:07.000
+Shall we go?
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_Parse_SRT", func(t *testing.T) {
+		t.Parallel()
+
+		cues, err := Parse(strings.NewReader(testSRT))
+
+		require.NoError(t, err)
+		require.Len(t, cues, 2) // The music note is dropped
+		assert.Equal(t, &Cue{Start: time.Second, End: 2500 * time.Millisecond, Text: "今日は いい天気だね"}, cues[0])
+		assert.Equal(t, "行こうか", cues[1].Text)
+	})
+
+	t.Run("Normal_Parse_VTT", func(t *testing.T) {
+		t.Parallel()
+
+		cues, err := Parse(strings.NewReader(testVTT))
+
+		require.NoError(t, err)
+		require.Len(t, cues, 3)
+		assert.Equal(t, &Cue{Start: time.Second, End: 1800 * time.Millisecond, Text: "Nice weather"}, cues[0])
+		assert.Equal(t, time.Hour+5*time.Second, cues[2].Start)
+	})
+
+	t.Run("Error_Parse_InvalidTiming", func(t *testing.T) {
+		t.Parallel()
+
+		cues, err := Parse(strings.NewReader("1\n00:00:aa,000 --> 00:00:02,000\nHello\n"))
+
+		assert.Error(t, err)
+		assert.Nil(t, cues)
+	})
+}
+
+func TestPairTracks(t *testing.T) {
+	t.Parallel()
+
+	primary := []*Cue{
+		{Start: time.Second, End: 2500 * time.Millisecond, Text: "今日はいい天気だね"},
+		{Start: 5 * time.Second, End: 7 * time.Second, Text: "行こうか"},
+		{Start: 10 * time.Second, End: 11 * time.Second, Text: "え？"},
+	}
+	secondary := []*Cue{
+		{Start: time.Second, End: 1800 * time.Millisecond, Text: "Nice weather"},
+		{Start: 1800 * time.Millisecond, End: 2400 * time.Millisecond, Text: "today."},
+		{Start: 7200 * time.Millisecond, End: 8 * time.Second, Text: "Shall we go?"},
+		{Start: 20 * time.Second, End: 21 * time.Second, Text: "Far away"},
+	}
+
+	pairs := PairTracks(primary, secondary, DefaultPairTolerance)
+
+	require.Len(t, pairs, 3)
+	assert.Equal(t, "Nice weather today.", pairs[0].Translation)
+	assert.Equal(t, "Shall we go?", pairs[1].Translation) // Paired within the tolerance
+	assert.Equal(t, "", pairs[2].Translation)
+	assert.Equal(t, "え？", pairs[2].Text)
+}
+
+func TestKnownWords(t *testing.T) {
+	t.Parallel()
+
+	known := NewKnownWords([]string{"Shall", "we", "go"})
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"All known", "Shall we go?", true},
+		{"Case and width are folded", "ＳＨＡＬＬ we GO!", true},
+		{"Unknown word", "Shall we dance?", false},
+		{"No words", "...", true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, known.AllKnown(tc.text, SplitWords))
+		})
+	}
+}
+
+func TestJapaneseTokenizer(t *testing.T) {
+	t.Parallel()
+
+	tokenize := NewJapaneseTokenizer(prose.NewAnalyzer())
+	known := NewKnownWords([]string{"今日", "いい", "天気", "行く"})
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"All known", "今日はいい天気だね", true},
+		{"Dictionary forms are compared", "行った？", true},
+		{"Unknown word", "明日はいい天気だね", false},
+		{"Other languages are split by spaces", "OK、行こう", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, known.AllKnown(tc.text, tokenize))
+		})
+	}
+}
@@ -1,25 +1,48 @@
 package textdic
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
-	"regexp"
 	"strings"
 	"unicode"
 )
 
+// ideographicSpace is the full-width space used in Japanese texts
+const ideographicSpace = '\u3000'
+
 type lexer struct {
-	input  *strings.Reader
-	lineNo int
-	errors []error
+	input   *bufio.Reader
+	lineNo  int
+	errors  []error
+	readErr error
+
+	// allowMissingDefinitions makes a word without a definition an entry with an empty definition
+	allowMissingDefinitions bool
+	lastToken               int
+	pendingToken            int
+	pendingStr              string
+	hasPending              bool
 }
 
-func newLexer(input string) *lexer {
-	return &lexer{input: strings.NewReader(input), lineNo: 1}
+func newLexer(input io.Reader) *lexer {
+	return &lexer{input: bufio.NewReader(input), lineNo: 1}
 }
 
-func (l *lexer) Peek() rune {
+// readRune reads the next rune from the input. A read error other than io.EOF
+// is recorded once, and is handled as the end of the input by the parser.
+func (l *lexer) readRune() (rune, error) {
 	r, _, err := l.input.ReadRune()
+	if err != nil && !errors.Is(err, io.EOF) && l.readErr == nil {
+		l.readErr = err
+		l.errors = append(l.errors, fmt.Errorf("line : %d : %+v", l.lineNo, err))
+	}
+	return r, err
+}
+
+func (l *lexer) Peek() rune {
+	r, err := l.readRune()
 	if err == nil {
 		l.input.UnreadRune()
 	}
@@ -31,7 +54,7 @@ func (l *lexer) isNewLine(r rune) bool {
 }
 
 func (l *lexer) IsWhitespace(r rune) bool {
-	return unicode.IsSpace(r) || regexp.MustCompile(`\x{3000}`).MatchString(string(r))
+	return unicode.IsSpace(r) || r == ideographicSpace
 }
 
 func (l *lexer) isEnglishAndWhitespace(r rune) bool {
@@ -43,8 +66,27 @@ func (l *lexer) isJapanese(r rune) bool {
 }
 
 func (l *lexer) Lex(lval *yySymType) int {
+	if l.hasPending {
+		l.hasPending = false
+		lval.str = l.pendingStr
+		l.lastToken = l.pendingToken
+		return l.pendingToken
+	}
+
+	token := l.lex(lval)
+	if l.allowMissingDefinitions && l.lastToken == WORD && (token == NEWLINE || token == 0) {
+		// Insert an empty definition before the end of the line
+		l.pendingToken, l.pendingStr, l.hasPending = token, lval.str, true
+		lval.str = ""
+		token = DEFINITION
+	}
+	l.lastToken = token
+	return token
+}
+
+func (l *lexer) lex(lval *yySymType) int {
 	r, err := l.skipWhiteSpace()
-	if err == io.EOF {
+	if err != nil {
 		// Done with parsing
 		return 0
 	}
@@ -66,7 +108,7 @@ func (l *lexer) lexWord(lval *yySymType) int {
 	var wordBuilder strings.Builder
 	l.input.UnreadRune()
 	for {
-		r, _, err := l.input.ReadRune()
+		r, err := l.readRune()
 		if err != nil || l.isJapanese(r) || l.isNewLine(r) {
 			l.input.UnreadRune()
 			break
@@ -81,7 +123,7 @@ func (l *lexer) lexDefinition(lval *yySymType) int {
 	var defBuilder strings.Builder
 	l.input.UnreadRune()
 	for {
-		ch, _, err := l.input.ReadRune()
+		ch, err := l.readRune()
 		if err != nil || l.isNewLine(ch) {
 			l.input.UnreadRune()
 			break
@@ -94,7 +136,7 @@ func (l *lexer) lexDefinition(lval *yySymType) int {
 
 func (l *lexer) skipWhiteSpace() (rune, error) {
 	for {
-		r, _, err := l.input.ReadRune()
+		r, err := l.readRune()
 		if err != nil || !l.IsWhitespace(r) || l.isNewLine(r) {
 			return r, err
 		}
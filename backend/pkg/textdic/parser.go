@@ -5,22 +5,22 @@ package textdic
 
 import __yyfmt__ "fmt"
 
-//line ./pkg/textdic/parser.y:2
-
-import "sync"
-
 // Define Node and Nodes types
+//
+//line ./pkg/textdic/parser.y:2
 type Node struct {
 	Word       string
 	Definition string
+	// Reading is the definition annotated with readings in the form 漢字[かんじ], if any
+	Reading string
+	Senses  []string
 }
 
 type Nodes []Node
 
-//line ./pkg/textdic/parser.y:16
+//line ./pkg/textdic/parser.y:17
 type yySymType struct {
 	yys   int
-	mutex sync.RWMutex
 	str   string
 	node  Node
 	nodes Nodes
@@ -54,21 +54,15 @@ type Parser interface {
 
 func NewParser(yylex yyLexer) Parser {
 	yyparser := &yyParserImpl{}
-	yyparser.lval.mutex.RLock()
-	defer yyparser.lval.mutex.RUnlock()
 	yyparser.Parse(yylex)
 	return yyparser
 }
 
 func (yyrcvr *yyParserImpl) setNodes(nodes []Node) {
-	yyrcvr.lval.mutex.RLock()
-	defer yyrcvr.lval.mutex.RUnlock()
 	yyrcvr.lval.nodes = nodes
 }
 
 func (yyrcvr *yyParserImpl) GetNodes() []Node {
-	yyrcvr.lval.mutex.RLock()
-	defer yyrcvr.lval.mutex.RUnlock()
 	return yyrcvr.lval.nodes
 }
 
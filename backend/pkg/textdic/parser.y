@@ -1,12 +1,13 @@
 %{
 package textdic
 
-import "sync"
-
 // Define Node and Nodes types
 type Node struct {
 	Word       string
 	Definition string
+	// Reading is the definition annotated with readings in the form 漢字[かんじ], if any
+	Reading    string
+	Senses     []string
 }
 
 type Nodes []Node
@@ -14,7 +15,6 @@ type Nodes []Node
 %}
 
 %union {
-    mutex sync.RWMutex
 	str  string
 	node Node
 	nodes Nodes
@@ -52,20 +52,14 @@ type Parser interface {
 
 func NewParser(yylex yyLexer) Parser {
 	yyparser := &yyParserImpl{}
-    yyparser.lval.mutex.RLock()
-    defer yyparser.lval.mutex.RUnlock()
 	yyparser.Parse(yylex)
 	return yyparser
 }
 
 func (yyrcvr *yyParserImpl) setNodes(nodes []Node) {
-    yyrcvr.lval.mutex.RLock()
-    defer yyrcvr.lval.mutex.RUnlock()
 	yyrcvr.lval.nodes = nodes
 }
 
 func (yyrcvr *yyParserImpl) GetNodes() []Node {
-    yyrcvr.lval.mutex.RLock()
-    defer yyrcvr.lval.mutex.RUnlock()
 	return yyrcvr.lval.nodes
 }
@@ -2,6 +2,7 @@ package textdic
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -65,7 +66,7 @@ There is no leeway to provide services free of charge for the sake of others. 
 			mutext.RLock()
 			defer mutext.RUnlock()
 			// Create a new lexer with the input
-			l := newLexer(tc.input)
+			l := newLexer(strings.NewReader(tc.input))
 
 			// Parse the input using the parser instance
 			parser := NewParser(l)
@@ -95,7 +96,7 @@ trot out 自慢げに話題に持ち出す
 !もう一つエラー
 `
 		// Create a new lexer with the input
-		l := newLexer(input)
+		l := newLexer(strings.NewReader(input))
 
 		// For Debug
 		//yyDebug = 5
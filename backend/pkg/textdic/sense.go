@@ -0,0 +1,148 @@
+package textdic
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSenseSeparators are the separators used to split a definition into senses
+// when no other separators are configured.
+var DefaultSenseSeparators = []string{";", "；", "、"}
+
+// numberedSense matches candidates of numbered markers such as `1.`, `２．` or `①`.
+var numberedSense = regexp.MustCompile(`[0-9０-９]+[.．]|[\x{2460}-\x{2473}]`)
+
+// brackets maps opening brackets to closing ones. Separators inside brackets
+// belong to the sense itself, e.g. `（人）の気［癇］に障る`.
+var brackets = map[rune]rune{
+	'(': ')',
+	'（': '）',
+	'[': ']',
+	'［': '］',
+	'〔': '〕',
+	'「': '」',
+	'【': '】',
+}
+
+// senseSplitter struct definition
+type senseSplitter struct {
+	separators []string
+}
+
+// SenseSplitter splits a definition into a list of senses.
+type SenseSplitter interface {
+	Split(definition string) []string
+}
+
+// NewSenseSplitter creates a SenseSplitter splitting on the given separators.
+// Numbered markers are always treated as separators.
+func NewSenseSplitter(separators []string) SenseSplitter {
+	var seps []string
+	for _, sep := range separators {
+		if sep != "" {
+			seps = append(seps, sep)
+		}
+	}
+	return &senseSplitter{separators: seps}
+}
+
+// Split returns the senses of the definition in the original order.
+// Empty senses are dropped.
+func (s *senseSplitter) Split(definition string) []string {
+	senses := []string{}
+	for _, part := range s.splitNumbered(definition) {
+		for _, sense := range s.splitSeparators(part) {
+			sense = strings.TrimFunc(sense, unicode.IsSpace)
+			if sense != "" {
+				senses = append(senses, sense)
+			}
+		}
+	}
+	return senses
+}
+
+// splitNumbered splits the definition at numbered markers and removes the markers.
+func (s *senseSplitter) splitNumbered(definition string) []string {
+	var parts []string
+	start := 0
+	for _, m := range numberedSense.FindAllStringIndex(definition, -1) {
+		if !s.isNumberedMarker(definition, m[0], m[1]) {
+			continue
+		}
+		parts = append(parts, definition[start:m[0]])
+		start = m[1]
+	}
+	return append(parts, definition[start:])
+}
+
+// isNumberedMarker checks the surroundings of a numbered marker candidate.
+// Digits must start the definition or follow a whitespace, and must not be
+// followed by another digit, so that `3.5` is not taken as a marker.
+func (s *senseSplitter) isNumberedMarker(definition string, start, end int) bool {
+	first, _ := utf8.DecodeRuneInString(definition[start:])
+	if first >= '\u2460' && first <= '\u2473' {
+		return true
+	}
+	if start > 0 {
+		prev, _ := utf8.DecodeLastRuneInString(definition[:start])
+		if !unicode.IsSpace(prev) {
+			return false
+		}
+	}
+	if end < len(definition) {
+		next, _ := utf8.DecodeRuneInString(definition[end:])
+		if unicode.IsDigit(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitSeparators splits the text at the configured separators outside of brackets.
+func (s *senseSplitter) splitSeparators(text string) []string {
+	var parts []string
+	var closing []rune
+	start := 0
+
+	for i, r := range text {
+		if i < start {
+			// Still inside a separator consisting of several runes
+			continue
+		}
+		if c, ok := brackets[r]; ok {
+			closing = append(closing, c)
+			continue
+		}
+		if len(closing) > 0 {
+			if r == closing[len(closing)-1] {
+				closing = closing[:len(closing)-1]
+			}
+			continue
+		}
+		for _, sep := range s.separators {
+			if strings.HasPrefix(text[i:], sep) {
+				parts = append(parts, text[start:i])
+				start = i + len(sep)
+				break
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// MatchesAnySense reports whether the answer matches one of the senses.
+// Surrounding whitespaces and letter cases are ignored.
+func MatchesAnySense(senses []string, answer string) bool {
+	answer = strings.TrimFunc(answer, unicode.IsSpace)
+	if answer == "" {
+		return false
+	}
+	for _, sense := range senses {
+		if strings.EqualFold(strings.TrimFunc(sense, unicode.IsSpace), answer) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package textdic
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSenseSplitter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		separators []string
+		definition string
+		expected   []string
+	}{
+		{
+			name:       "Full-width semicolons",
+			separators: DefaultSenseSeparators,
+			definition: "走る；駆ける；運行する",
+			expected:   []string{"走る", "駆ける", "運行する"},
+		},
+		{
+			name:       "Mixed separators",
+			separators: DefaultSenseSeparators,
+			definition: "自慢げに歩かせて見せる、出して見せる; 披露する",
+			expected:   []string{"自慢げに歩かせて見せる", "出して見せる", "披露する"},
+		},
+		{
+			name:       "Numbered senses",
+			separators: DefaultSenseSeparators,
+			definition: "1. 走る 2. 運行する ３．動く",
+			expected:   []string{"走る", "運行する", "動く"},
+		},
+		{
+			name:       "Circled numbers",
+			separators: DefaultSenseSeparators,
+			definition: "①走る②運行する",
+			expected:   []string{"走る", "運行する"},
+		},
+		{
+			name:       "Separators inside brackets are kept",
+			separators: DefaultSenseSeparators,
+			definition: "〔時間、金などの〕余裕、ゆとり",
+			expected:   []string{"〔時間、金などの〕余裕", "ゆとり"},
+		},
+		{
+			name:       "Trailing separator",
+			separators: DefaultSenseSeparators,
+			definition: "情報に疎い、",
+			expected:   []string{"情報に疎い"},
+		},
+		{
+			name:       "Decimal numbers are not markers",
+			separators: DefaultSenseSeparators,
+			definition: "3.5倍",
+			expected:   []string{"3.5倍"},
+		},
+		{
+			name:       "Custom separators",
+			separators: []string{"／"},
+			definition: "急げ。／さっさとやれ。、すぐに",
+			expected:   []string{"急げ。", "さっさとやれ。、すぐに"},
+		},
+		{
+			name:       "No separators",
+			separators: nil,
+			definition: "走る；駆ける",
+			expected:   []string{"走る；駆ける"},
+		},
+		{
+			name:       "Empty definition",
+			separators: DefaultSenseSeparators,
+			definition: "",
+			expected:   []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			splitter := NewSenseSplitter(tc.separators)
+			assert.Equal(t, tc.expected, splitter.Split(tc.definition))
+		})
+	}
+}
+
+func TestMatchesAnySense(t *testing.T) {
+	t.Parallel()
+
+	senses := []string{"走る", "駆ける", "Run"}
+
+	testCases := []struct {
+		name     string
+		answer   string
+		expected bool
+	}{
+		{"First sense", "走る", true},
+		{"Second sense", "駆ける", true},
+		{"Surrounding spaces", "　駆ける ", true},
+		{"Case insensitive", "run", true},
+		{"Whole definition", "走る；駆ける", false},
+		{"Empty answer", "", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, MatchesAnySense(senses, tc.answer))
+		})
+	}
+}
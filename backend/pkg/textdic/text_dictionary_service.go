@@ -1,34 +1,91 @@
 package textdic
 
 import (
+	"backend/pkg/furigana"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 )
 
+// DefaultMaxBytes is the maximum size of a dictionary when no other size is configured
+const DefaultMaxBytes int64 = 10 * 1024 * 1024
+
+// ErrDictionaryTooLarge is returned when a dictionary exceeds the maximum size
+var ErrDictionaryTooLarge = errors.New("dictionary is too large")
+
 // textDictionaryService struct definition
 type textDictionaryService struct {
-	mu sync.RWMutex
+	mu            sync.RWMutex
+	senseSplitter SenseSplitter
+	maxBytes      int64
 }
 
 // TextDictionaryService defines the methods for processing text dictionaries.
 type TextDictionaryService interface {
 	Process(dic string) ([]Node, []error)
+	ProcessReader(r io.Reader) ([]Node, []error)
+	ProcessReaderWithOptions(r io.Reader, opts ParseOptions) ([]Node, []error)
 	DecodeBase64(s string) (string, error)
+	DecodeBase64Reader(s string) io.Reader
+}
+
+// Options holds the settings of TextDictionaryService
+type Options struct {
+	// SenseSplitter splits definitions into senses
+	SenseSplitter SenseSplitter
+	// MaxBytes is the maximum size of a dictionary in bytes. Zero or less means no limit.
+	MaxBytes int64
+}
+
+// ParseOptions holds the settings of a single parse
+type ParseOptions struct {
+	// AllowMissingDefinitions accepts lines with only a word, which are parsed
+	// into nodes with an empty definition to be filled in later.
+	AllowMissingDefinitions bool
 }
 
 // NewTextDictionaryService creates and returns a new instance of textDictionaryService
 func NewTextDictionaryService() TextDictionaryService {
-	return &textDictionaryService{}
+	return NewTextDictionaryServiceWithOptions(Options{
+		SenseSplitter: NewSenseSplitter(DefaultSenseSeparators),
+		MaxBytes:      DefaultMaxBytes,
+	})
+}
+
+// NewTextDictionaryServiceWithOptions creates a textDictionaryService with the given options
+func NewTextDictionaryServiceWithOptions(opts Options) TextDictionaryService {
+	if opts.SenseSplitter == nil {
+		opts.SenseSplitter = NewSenseSplitter(DefaultSenseSeparators)
+	}
+	return &textDictionaryService{senseSplitter: opts.SenseSplitter, maxBytes: opts.MaxBytes}
 }
 
 // Process processes a given dictionary string and returns the parsed Nodes or an error
 func (tds *textDictionaryService) Process(dic string) ([]Node, []error) {
+	return tds.ProcessReader(strings.NewReader(dic))
+}
+
+// ProcessReader parses a dictionary while reading it from r, so that the whole
+// dictionary is never held in memory as one string.
+func (tds *textDictionaryService) ProcessReader(r io.Reader) ([]Node, []error) {
+	return tds.ProcessReaderWithOptions(r, ParseOptions{})
+}
+
+// ProcessReaderWithOptions works as ProcessReader with the given parse options.
+func (tds *textDictionaryService) ProcessReaderWithOptions(r io.Reader, opts ParseOptions) ([]Node, []error) {
 	tds.mu.RLock()
 	defer tds.mu.RUnlock()
 
+	if tds.maxBytes > 0 {
+		r = &maxBytesReader{r: r, remaining: tds.maxBytes}
+	}
+
 	// Use the new parser to parse the input
-	l := newLexer(dic)
+	l := newLexer(r)
+	l.allowMissingDefinitions = opts.AllowMissingDefinitions
 
 	// Parse the input using the new parser
 	//yyErrorVerbose = true
@@ -44,6 +101,16 @@ func (tds *textDictionaryService) Process(dic string) ([]Node, []error) {
 		return nil, []error{err}
 	}
 
+	// Split definitions into senses
+	for i := range parsedNodes {
+		// Readings are kept apart from the definition, such as 走る[はしる]
+		if furigana.HasAnnotation(parsedNodes[i].Definition) {
+			parsedNodes[i].Reading = parsedNodes[i].Definition
+			parsedNodes[i].Definition = furigana.Plain(parsedNodes[i].Definition)
+		}
+		parsedNodes[i].Senses = tds.senseSplitter.Split(parsedNodes[i].Definition)
+	}
+
 	return parsedNodes, nil
 }
 
@@ -55,3 +122,32 @@ func (tds *textDictionaryService) DecodeBase64(s string) (string, error) {
 	}
 	return string(decoded), nil
 }
+
+// DecodeBase64Reader returns a reader decoding a Base64 encoded string on the fly.
+// Decoding errors are returned while reading.
+func (tds *textDictionaryService) DecodeBase64Reader(s string) io.Reader {
+	return base64.NewDecoder(base64.StdEncoding, strings.NewReader(s))
+}
+
+// maxBytesReader fails with ErrDictionaryTooLarge instead of truncating the
+// input silently as io.LimitReader does.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, ErrDictionaryTooLarge
+	}
+	// Read one more byte than allowed to detect the overflow
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n + int(m.remaining), ErrDictionaryTooLarge
+	}
+	return n, err
+}
@@ -1,6 +1,11 @@
 package textdic
 
 import (
+	"encoding/base64"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -206,6 +211,119 @@ Hold me accountable for 自分の行動の結果を受け入れ、罰を受け
 		}
 	})
 
+	t.Run("TestTextDictionaryService_Senses", func(t *testing.T) {
+		service := NewTextDictionaryServiceWithOptions(Options{SenseSplitter: NewSenseSplitter([]string{"；"})})
+
+		parsedNodes, err := service.Process("run 走る；駆ける；運行する\nrube 田舎者")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := [][]string{
+			{"走る", "駆ける", "運行する"},
+			{"田舎者"},
+		}
+		if len(parsedNodes) != len(expected) {
+			t.Fatalf("expected %d nodes, but got %d", len(expected), len(parsedNodes))
+		}
+		for i, node := range parsedNodes {
+			assert.Equal(t, expected[i], node.Senses)
+		}
+	})
+
+	t.Run("TestTextDictionaryService_ProcessReader", func(t *testing.T) {
+		service := NewTextDictionaryService()
+
+		// Build a large dictionary to be decoded and parsed on the fly
+		var builder strings.Builder
+		for i := 0; i < 50000; i++ {
+			builder.WriteString(fmt.Sprintf("word%d 単語%d\n", i, i))
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte(builder.String()))
+
+		parsedNodes, errs := service.ProcessReader(service.DecodeBase64Reader(encoded))
+		assert.Empty(t, errs)
+		assert.Len(t, parsedNodes, 50000)
+		assert.Equal(t, "word49999", parsedNodes[49999].Word)
+		assert.Equal(t, "単語49999", parsedNodes[49999].Definition)
+	})
+
+	t.Run("TestTextDictionaryService_ProcessReader_ErrorCases", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			maxBytes int64
+			input    func(service TextDictionaryService) io.Reader
+			expected error
+		}{
+			{
+				name:     "Exceeds max bytes",
+				maxBytes: 16,
+				input: func(service TextDictionaryService) io.Reader {
+					return strings.NewReader("rube 田舎者\njarring 気に障る")
+				},
+				expected: ErrDictionaryTooLarge,
+			},
+			{
+				name:     "Invalid Base64",
+				maxBytes: DefaultMaxBytes,
+				input: func(service TextDictionaryService) io.Reader {
+					return service.DecodeBase64Reader("Hello, World!")
+				},
+			},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				service := NewTextDictionaryServiceWithOptions(Options{MaxBytes: tc.maxBytes})
+
+				parsedNodes, errs := service.ProcessReader(tc.input(service))
+				assert.Nil(t, parsedNodes)
+				assert.NotEmpty(t, errs)
+				if tc.expected != nil {
+					assert.ErrorContains(t, errs[0], tc.expected.Error())
+				}
+			})
+		}
+	})
+
+	t.Run("TestTextDictionaryService_AllowMissingDefinitions", func(t *testing.T) {
+		service := NewTextDictionaryService()
+		input := "rube\n\njarring 気に障る\r\nleeway"
+
+		parsedNodes, errs := service.ProcessReaderWithOptions(strings.NewReader(input), ParseOptions{AllowMissingDefinitions: true})
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{"rube", "jarring", "leeway"}, []string{parsedNodes[0].Word, parsedNodes[1].Word, parsedNodes[2].Word})
+		assert.Equal(t, []string{"", "気に障る", ""}, []string{parsedNodes[0].Definition, parsedNodes[1].Definition, parsedNodes[2].Definition})
+
+		// Without the option, a word without a definition is still a syntax error
+		parsedNodes, errs = service.ProcessReader(strings.NewReader(input))
+		assert.Nil(t, parsedNodes)
+		assert.NotEmpty(t, errs)
+	})
+
+	t.Run("TestTextDictionaryService_Readings", func(t *testing.T) {
+		service := NewTextDictionaryService()
+
+		parsedNodes, errs := service.Process("run 走る[はしる]、駆ける[かける]\nrube 田舎者\n")
+		assert.Empty(t, errs)
+		assert.Len(t, parsedNodes, 2)
+		assert.Equal(t, "走る、駆ける", parsedNodes[0].Definition)
+		assert.Equal(t, "走る[はしる]、駆ける[かける]", parsedNodes[0].Reading)
+		assert.Equal(t, []string{"走る", "駆ける"}, parsedNodes[0].Senses)
+		assert.Equal(t, "", parsedNodes[1].Reading)
+	})
+
+	t.Run("TestTextDictionaryService_MaxBytesBoundary", func(t *testing.T) {
+		input := "rube 田舎者"
+		service := NewTextDictionaryServiceWithOptions(Options{MaxBytes: int64(len(input))})
+
+		parsedNodes, errs := service.Process(input)
+		assert.Empty(t, errs)
+		assert.Len(t, parsedNodes, 1)
+	})
+
 	t.Run("Test_decodeBase64", func(t *testing.T) {
 		service := NewTextDictionaryService()
 
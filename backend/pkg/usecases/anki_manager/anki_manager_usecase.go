@@ -0,0 +1,312 @@
+package anki_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/anki"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+)
+
+// Default field names mapped to Front and Back. The first and the second
+// fields are used when a note type does not have the named fields.
+const (
+	DefaultFrontField = "Front"
+	DefaultBackField  = "Back"
+	frontFieldIndex   = 0
+	backFieldIndex    = 1
+)
+
+// AnkiPackage is an exported Anki package with the name of the card group
+type AnkiPackage struct {
+	Name string
+	Data []byte
+}
+
+type AnkiManagerUsecase interface {
+	ImportAnkiPackage(ctx context.Context, userID int64, input model.ImportAnkiPackage) (*model.CardGroup, error)
+	ExportAnkiPackage(ctx context.Context, cardGroupID int64, userID int64, includeScheduling bool) (*AnkiPackage, error)
+}
+
+type ankiManagerUsecase struct {
+	services services.Services
+	// maxBytes is the maximum size of an extracted collection
+	maxBytes int64
+}
+
+func NewAnkiManagerUsecase(services services.Services, maxBytes int64) AnkiManagerUsecase {
+	return &ankiManagerUsecase{
+		services: services,
+		maxBytes: maxBytes,
+	}
+}
+
+// ImportAnkiPackage creates a card group of the user with the cards of a base64 encoded Anki package.
+// With includeHistory, the intervals of review cards and the review log are carried over
+// into the cards and the swipe records of the user.
+func (a *ankiManagerUsecase) ImportAnkiPackage(ctx context.Context, userID int64, input model.ImportAnkiPackage) (*model.CardGroup, error) {
+	data, err := base64.StdEncoding.DecodeString(input.Package)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to decode base64 package")
+	}
+
+	collection, err := anki.ReadPackage(data, a.maxBytes)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read anki package")
+	}
+
+	frontField := DefaultFrontField
+	if input.FrontField != nil {
+		frontField = *input.FrontField
+	}
+	backField := DefaultBackField
+	if input.BackField != nil {
+		backField = *input.BackField
+	}
+	includeHistory := input.IncludeHistory != nil && *input.IncludeHistory
+
+	cardGroup, err := a.services.CreateCardGroup(ctx, model.NewCardGroup{
+		Name:    input.Name,
+		Created: time.Now().UTC(),
+		Updated: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create card group")
+	}
+	if _, err := a.services.AddUserToCardGroup(ctx, userID, cardGroup.ID, model.CardGroupRoleOwner); err != nil {
+		return nil, goerr.Wrap(err, "failed to add user to card group")
+	}
+
+	// The first card of a note holds the scheduling state of the note
+	noteCards := make(map[int64]*anki.Card)
+	for _, card := range collection.Cards {
+		if _, ok := noteCards[card.NoteID]; !ok {
+			noteCards[card.NoteID] = card
+		}
+	}
+
+	var cards []model.Card
+	noteFronts := make(map[int64]string)
+	for _, note := range collection.Notes {
+		front := anki.StripHTML(collection.Field(note, frontField, frontFieldIndex))
+		back := anki.StripHTML(collection.Field(note, backField, backFieldIndex))
+		if front == "" || back == "" {
+			continue
+		}
+		noteFronts[note.ID] = front
+
+		card := model.Card{
+			Front:        front,
+			Back:         back,
+			ReviewDate:   time.Now().UTC(),
+			IntervalDays: 1,
+			Created:      time.Now().UTC(),
+			Updated:      time.Now().UTC(),
+			CardGroupID:  cardGroup.ID,
+		}
+		if ankiCard, ok := noteCards[note.ID]; ok && includeHistory && ankiCard.Type == anki.CardTypeReview {
+			card.ReviewDate = collection.DueDate(ankiCard)
+			card.IntervalDays = max(ankiCard.Interval, 1)
+		}
+		cards = append(cards, card)
+	}
+
+	createdCards, err := a.services.AddNewCards(ctx, cards, cardGroup.ID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to add new cards")
+	}
+
+	if includeHistory {
+		if err := a.importHistory(ctx, collection, noteFronts, createdCards, userID, cardGroup.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return cardGroup, nil
+}
+
+// importHistory converts the review log into swipe records
+func (a *ankiManagerUsecase) importHistory(ctx context.Context, collection *anki.Collection,
+	noteFronts map[int64]string, createdCards []*model.Card, userID int64, cardGroupID int64) error {
+	cardIDs := make(map[string]int64, len(createdCards))
+	for _, card := range createdCards {
+		cardIDs[card.Front] = card.ID
+	}
+	ankiCardNotes := make(map[int64]int64, len(collection.Cards))
+	for _, card := range collection.Cards {
+		ankiCardNotes[card.ID] = card.NoteID
+	}
+
+	var swipeRecords []model.NewSwipeRecord
+	for _, review := range collection.Reviews {
+		mode, ok := easeToMode(review.Ease)
+		if !ok {
+			continue
+		}
+		cardID, ok := cardIDs[noteFronts[ankiCardNotes[review.CardID]]]
+		if !ok {
+			continue
+		}
+		swipeRecords = append(swipeRecords, model.NewSwipeRecord{
+			UserID:      userID,
+			CardID:      cardID,
+			CardGroupID: cardGroupID,
+			Mode:        mode,
+			Created:     review.Time(),
+			Updated:     review.Time(),
+		})
+	}
+
+	if err := a.services.CreateSwipeRecords(ctx, swipeRecords); err != nil {
+		return goerr.Wrap(err, "failed to import review history")
+	}
+	return nil
+}
+
+// ExportAnkiPackage writes the cards of the card group into an Anki package with the basic note type.
+// With includeScheduling, the cards the user has swiped are exported as review cards with their
+// intervals, and the swipe records of the user as the review log.
+func (a *ankiManagerUsecase) ExportAnkiPackage(ctx context.Context, cardGroupID int64, userID int64, includeScheduling bool) (*AnkiPackage, error) {
+	cardGroup, err := a.services.GetCardGroupByID(ctx, cardGroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get card group")
+	}
+
+	cards, err := a.services.FetchAllCardsByCardGroup(ctx, cardGroupID, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fetch cards")
+	}
+
+	var swipeRecords []*model.SwipeRecord
+	if includeScheduling {
+		if _, err := a.services.GetCardgroupUser(ctx, cardGroupID, userID); err != nil {
+			return nil, goerr.Wrap(err, "failed to get card group user")
+		}
+		records, err := a.services.SwipeRecordsByUser(ctx, userID)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to get swipe records")
+		}
+		for _, record := range records {
+			if record.CardGroupID == cardGroupID {
+				swipeRecords = append(swipeRecords, record)
+			}
+		}
+	}
+
+	collection := newExportCollection(cards, swipeRecords)
+	data, err := anki.WritePackage(collection, cardGroup.Name)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to write anki package")
+	}
+
+	return &AnkiPackage{Name: cardGroup.Name, Data: data}, nil
+}
+
+// toAnkiField converts plain text into an HTML field of Anki, which StripHTML converts back on import.
+// The field separator of Anki is removed, because it would split the field into two.
+func toAnkiField(text string) string {
+	text = strings.ReplaceAll(text, "\x1f", "")
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// newExportCollection converts cards into notes of the basic note type, one card per note.
+// Cards with swipe records become review cards, and the others stay new in the order of the cards.
+func newExportCollection(cards []*model.Card, swipeRecords []*model.SwipeRecord) *anki.Collection {
+	// The collection starts on the earliest review date, so that due days are not negative
+	created := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, card := range cards {
+		if card.ReviewDate.Before(created) {
+			created = card.ReviewDate.UTC().Truncate(24 * time.Hour)
+		}
+	}
+
+	collection := &anki.Collection{
+		Created: created,
+		Models:  map[int64]*anki.Model{anki.BasicModelID: anki.NewBasicModel()},
+	}
+
+	reviewed := make(map[int64]bool, len(swipeRecords))
+	for _, record := range swipeRecords {
+		reviewed[record.CardID] = true
+	}
+
+	newPosition := int64(0)
+	for _, card := range cards {
+		collection.Notes = append(collection.Notes, &anki.Note{
+			ID:      card.ID,
+			GUID:    fmt.Sprintf("flamingo-%d", card.ID),
+			ModelID: anki.BasicModelID,
+			Fields:  []string{toAnkiField(card.Front), toAnkiField(card.Back)},
+		})
+
+		ankiCard := &anki.Card{ID: card.ID, NoteID: card.ID}
+		if reviewed[card.ID] {
+			ankiCard.Type = anki.CardTypeReview
+			ankiCard.Queue = anki.CardTypeReview
+			ankiCard.Due = collection.DueFromDate(card.ReviewDate)
+			ankiCard.Interval = card.IntervalDays
+		} else {
+			newPosition++
+			ankiCard.Due = newPosition
+		}
+		collection.Cards = append(collection.Cards, ankiCard)
+	}
+
+	sort.SliceStable(swipeRecords, func(i, j int) bool {
+		return swipeRecords[i].Created.Before(swipeRecords[j].Created)
+	})
+	var lastID int64
+	for _, record := range swipeRecords {
+		ease, ok := modeToEase(record.Mode)
+		if !ok {
+			continue
+		}
+		// The review log is keyed by the epoch milliseconds of reviews
+		id := max(record.Created.UnixMilli(), lastID+1)
+		lastID = id
+		collection.Reviews = append(collection.Reviews, &anki.Review{
+			ID:     id,
+			CardID: record.CardID,
+			Ease:   ease,
+			Type:   anki.ReviewTypeReview,
+		})
+	}
+
+	return collection
+}
+
+// easeToMode converts an answer button of Anki into a swipe mode.
+// Entries without an answer, such as manual rescheduling, are skipped.
+func easeToMode(ease int) (int, bool) {
+	switch ease {
+	case anki.EaseAgain:
+		return services.UNKNOWN, true
+	case anki.EaseHard:
+		return services.MAYBE, true
+	case anki.EaseGood, anki.EaseEasy:
+		return services.KNOWN, true
+	default:
+		return services.UNDEFINED, false
+	}
+}
+
+// modeToEase converts a swipe mode into an answer button of Anki
+func modeToEase(mode int) (int, bool) {
+	switch mode {
+	case services.UNKNOWN:
+		return anki.EaseAgain, true
+	case services.MAYBE:
+		return anki.EaseHard, true
+	case services.KNOWN:
+		return anki.EaseGood, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,268 @@
+package anki_manager
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/anki"
+	"backend/testutils"
+	"context"
+	"encoding/base64"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+var sv services.Services
+var userService services.UserService
+var cardGroupService services.CardGroupService
+var cardService services.CardService
+var roleService services.RoleService
+
+var migrationFilePath = "../../../db/migrations"
+
+// basicPackagePath is a package with a "Basic" note and a "Vocabulary" note whose fields are ordered differently
+var basicPackagePath = "../../anki/testdata/basic.apkg"
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "flamingo")
+	if err != nil {
+		log.Fatalf("Failed to setup test database: %+v", err)
+	}
+	defer cleanup(migrationFilePath)
+
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		log.Fatalf("failed to run migrations: %+v", err)
+	}
+
+	db = pg.GetDB()
+	sv = services.New(db)
+
+	userService = sv.(services.UserService)
+	cardGroupService = sv.(services.CardGroupService)
+	cardService = sv.(services.CardService)
+	roleService = sv.(services.RoleService)
+
+	m.Run()
+}
+
+func TestImportAnkiPackage(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	usecase := NewAnkiManagerUsecase(sv, 0)
+
+	data, err := os.ReadFile(basicPackagePath)
+	if err != nil {
+		t.Fatalf("failed to read package: %+v", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_ImportAnkiPackage", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cardGroup, err := usecase.ImportAnkiPackage(ctx, user.ID, model.ImportAnkiPackage{
+				Name:    "Imported",
+				Package: encoded,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, "Imported", cardGroup.Name)
+			cards, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroup.ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, cards, 2)
+			assert.Equal(t, "run", cards[0].Front)
+			assert.Equal(t, "走る\n駆ける", cards[0].Back)
+			assert.Equal(t, 1, cards[0].IntervalDays)
+			assert.Equal(t, "rube", cards[1].Front)
+			assert.Equal(t, "田舎者", cards[1].Back) // The second field, Meaning, as Back is not a field of the note type
+
+			var count int64
+			db.Model(&repository.SwipeRecord{}).Where("cardgroup_id = ?", cardGroup.ID).Count(&count)
+			assert.Equal(t, int64(0), count)
+		})
+
+		t.Run("Normal_ImportAnkiPackage_FieldMappingAndHistory", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			backField := "Meaning"
+			includeHistory := true
+
+			// Act
+			cardGroup, err := usecase.ImportAnkiPackage(ctx, user.ID, model.ImportAnkiPackage{
+				Name:           "Imported with history",
+				Package:        encoded,
+				BackField:      &backField,
+				IncludeHistory: &includeHistory,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			cards, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroup.ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, cards, 2)
+			assert.Equal(t, 7, cards[0].IntervalDays)
+			assert.True(t, time.Date(2024, 7, 11, 0, 0, 0, 0, time.UTC).Equal(cards[0].ReviewDate))
+			assert.Equal(t, "田舎者", cards[1].Back)
+
+			var swipeRecords []repository.SwipeRecord
+			db.Where("cardgroup_id = ?", cardGroup.ID).Order("created").Find(&swipeRecords)
+			assert.Len(t, swipeRecords, 2)
+			assert.Equal(t, services.UNKNOWN, swipeRecords[0].Mode)
+			assert.Equal(t, services.KNOWN, swipeRecords[1].Mode)
+			assert.Equal(t, cards[0].ID, swipeRecords[1].CardID)
+			assert.Equal(t, user.ID, swipeRecords[1].UserID)
+		})
+
+		t.Run("Error_ImportAnkiPackage_InvalidPackage", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cardGroup, err := usecase.ImportAnkiPackage(ctx, user.ID, model.ImportAnkiPackage{
+				Name:    "Invalid",
+				Package: base64.StdEncoding.EncodeToString([]byte("not a package")),
+			})
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, cardGroup)
+		})
+	})
+}
+
+func TestExportAnkiPackage(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	usecase := NewAnkiManagerUsecase(sv, 0)
+
+	data, err := os.ReadFile(basicPackagePath)
+	if err != nil {
+		t.Fatalf("failed to read package: %+v", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	backField := "Meaning"
+	includeHistory := true
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_ExportAnkiPackage", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			cardGroup, err := usecase.ImportAnkiPackage(ctx, user.ID, model.ImportAnkiPackage{
+				Name:           "Export",
+				Package:        encoded,
+				BackField:      &backField,
+				IncludeHistory: &includeHistory,
+			})
+			assert.NoError(t, err)
+
+			// Act
+			pkg, err := usecase.ExportAnkiPackage(ctx, cardGroup.ID, user.ID, false)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, "Export", pkg.Name)
+			collection, err := anki.ReadPackage(pkg.Data, 0)
+			assert.NoError(t, err)
+			assert.Len(t, collection.Notes, 2)
+			assert.Equal(t, "run", collection.Field(collection.Notes[0], "Front", 0))
+			assert.Equal(t, "走る<br>駆ける", collection.Field(collection.Notes[0], "Back", 1))
+			for _, card := range collection.Cards {
+				assert.Equal(t, anki.CardTypeNew, card.Type)
+			}
+			assert.Empty(t, collection.Reviews)
+		})
+
+		t.Run("Normal_ExportAnkiPackage_Scheduling", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			cardGroup, err := usecase.ImportAnkiPackage(ctx, user.ID, model.ImportAnkiPackage{
+				Name:           "Export with scheduling",
+				Package:        encoded,
+				BackField:      &backField,
+				IncludeHistory: &includeHistory,
+			})
+			assert.NoError(t, err)
+
+			// Act
+			pkg, err := usecase.ExportAnkiPackage(ctx, cardGroup.ID, user.ID, true)
+
+			// Assert
+			assert.NoError(t, err)
+			collection, err := anki.ReadPackage(pkg.Data, 0)
+			assert.NoError(t, err)
+			assert.Len(t, collection.Cards, 2)
+			assert.Equal(t, anki.CardTypeReview, collection.Cards[0].Type)
+			assert.Equal(t, 7, collection.Cards[0].Interval)
+			assert.True(t, time.Date(2024, 7, 11, 0, 0, 0, 0, time.UTC).Equal(collection.DueDate(collection.Cards[0])))
+			assert.Equal(t, anki.CardTypeNew, collection.Cards[1].Type)
+			assert.Len(t, collection.Reviews, 2)
+			assert.Equal(t, anki.EaseAgain, collection.Reviews[0].Ease)
+			assert.Equal(t, anki.EaseGood, collection.Reviews[1].Ease)
+		})
+
+		t.Run("Normal_ExportAnkiPackage_RoundTrip", func(t *testing.T) {
+			// Arrange
+			cardGroup, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			_, err = cardService.CreateCard(ctx, model.NewCard{
+				Front:       "x < y & z",
+				Back:        "<b>less</b> & more\nline\x1f",
+				ReviewDate:  time.Now().UTC(),
+				CardgroupID: cardGroup.ID,
+			})
+			assert.NoError(t, err)
+
+			// Act
+			pkg, err := usecase.ExportAnkiPackage(ctx, cardGroup.ID, user.ID, false)
+			assert.NoError(t, err)
+			imported, err := usecase.ImportAnkiPackage(ctx, user.ID, model.ImportAnkiPackage{
+				Name:    "Round trip",
+				Package: base64.StdEncoding.EncodeToString(pkg.Data),
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			collection, err := anki.ReadPackage(pkg.Data, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "&lt;b&gt;less&lt;/b&gt; &amp; more<br>line", collection.Field(collection.Notes[0], "Back", 1))
+			cards, err := cardService.FetchAllCardsByCardGroup(ctx, imported.ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, cards, 1)
+			assert.Equal(t, "x < y & z", cards[0].Front)
+			assert.Equal(t, "<b>less</b> & more\nline", cards[0].Back)
+		})
+
+		t.Run("Error_ExportAnkiPackage_NotMember", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			_, otherUser, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			pkg, err := usecase.ExportAnkiPackage(ctx, cardGroup.ID, otherUser.ID, true)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, pkg)
+		})
+	})
+}
@@ -3,6 +3,8 @@ package dictionary_manager
 import (
 	"backend/graph/model"
 	"backend/graph/services"
+	"backend/pkg/furigana"
+	"backend/pkg/jmdict"
 	"backend/pkg/textdic"
 	"context"
 	"fmt"
@@ -11,56 +13,211 @@ import (
 )
 
 type DictionaryManagerUsecase interface {
-	UpsertCards(ctx context.Context, encodedDictionary string, cardGroupID int64) ([]*model.Card, error)
+	UpsertCards(ctx context.Context, input model.UpsertDictionary) ([]*model.Card, error)
+	PreviewCards(ctx context.Context, input model.UpsertDictionary) (*model.DictionaryPreview, error)
+	LookupWord(ctx context.Context, term string) ([]*model.DictionaryEntry, error)
+	FillReadings(ctx context.Context, cardGroupID int64) ([]*model.Card, error)
 }
 
 type dictionaryManagerUsecase struct {
 	cardService           services.CardService          // Pointer to CardService
 	textDictionaryService textdic.TextDictionaryService // Pointer to textDictionaryService
+	dictionary            *jmdict.Lazy                  // Local dictionary to look up words
 }
 
-func NewDictionaryManagerUsecase(cardService services.CardService, textDictionaryService textdic.TextDictionaryService) DictionaryManagerUsecase {
+func NewDictionaryManagerUsecase(cardService services.CardService, textDictionaryService textdic.TextDictionaryService, dictionary *jmdict.Lazy) DictionaryManagerUsecase {
 	return &dictionaryManagerUsecase{
 		cardService:           cardService,
 		textDictionaryService: textDictionaryService,
+		dictionary:            dictionary,
 	}
 }
 
 // UpsertCards decodes a base64 encoded dictionary, processes it, and creates cards from it.
-func (dmu *dictionaryManagerUsecase) UpsertCards(ctx context.Context, encodedDictionary string, cardGroupID int64) ([]*model.Card, error) {
+// The dictionary is decoded while being parsed, so that the decoded text is never held in memory as a whole.
+// In mirror mode, cards missing from the dictionary are deleted from the card group.
+// With auto-fill, words without a definition are defined and readings are filled in by the local dictionary.
+func (dmu *dictionaryManagerUsecase) UpsertCards(ctx context.Context, input model.UpsertDictionary) ([]*model.Card, error) {
+	cards, err := dmu.processDictionary(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Mirror != nil && *input.Mirror {
+		mirroredCards, err := dmu.cardService.MirrorCards(ctx, cards, input.CardgroupID)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to mirror cards")
+		}
+		return mirroredCards, nil
+	}
+
+	// Use AddNewCards to add the generated cards to the card service
+	createdCards, err := dmu.cardService.AddNewCards(ctx, cards, input.CardgroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to add new cards")
+	}
+
+	return createdCards, nil
+}
+
+// PreviewCards returns the cards UpsertCards would create, update, leave unchanged and delete without writing anything.
+func (dmu *dictionaryManagerUsecase) PreviewCards(ctx context.Context, input model.UpsertDictionary) (*model.DictionaryPreview, error) {
+	cards, err := dmu.processDictionary(input)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := dmu.cardService.PreviewNewCards(ctx, cards, input.CardgroupID, input.Mirror != nil && *input.Mirror)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to preview new cards")
+	}
+
+	return preview, nil
+}
 
-	// Decode the base64 encoded dictionary
-	decodedDictionary, err := dmu.textDictionaryService.DecodeBase64(encodedDictionary)
+// LookupWord finds the entries of a Japanese or English word in the local dictionary.
+func (dmu *dictionaryManagerUsecase) LookupWord(ctx context.Context, term string) ([]*model.DictionaryEntry, error) {
+	dic, err := dmu.dictionary.Get()
 	if err != nil {
-		return nil, goerr.Wrap(err, "failed to decode base64 dictionary")
+		return nil, goerr.Wrap(err, "failed to load dictionary")
 	}
 
-	// Process the decoded dictionary to get nodes
-	nodes, errs := dmu.textDictionaryService.Process(decodedDictionary)
+	entries := dic.Lookup(term)
+	results := make([]*model.DictionaryEntry, 0, len(entries))
+	for _, entry := range entries {
+		results = append(results, &model.DictionaryEntry{
+			Kanji:    append([]string{}, entry.Kanji...),
+			Readings: append([]string{}, entry.Readings...),
+			Meanings: entry.Meanings(),
+			Common:   entry.Common,
+		})
+	}
+	return results, nil
+}
+
+// processDictionary parses a base64 encoded dictionary into cards of the card group.
+func (dmu *dictionaryManagerUsecase) processDictionary(input model.UpsertDictionary) ([]model.Card, error) {
+	autoFill := input.AutoFill != nil && *input.AutoFill
+
+	// Process the base64 encoded dictionary to get nodes
+	nodes, errs := dmu.textDictionaryService.ProcessReaderWithOptions(
+		dmu.textDictionaryService.DecodeBase64Reader(input.Dictionary),
+		textdic.ParseOptions{AllowMissingDefinitions: autoFill})
 	if len(errs) > 0 {
 		return nil, goerr.Wrap(fmt.Errorf("failed to process dictionary: %+v", errs))
 	}
 
-	var cards []model.Card
+	cards := make([]model.Card, 0, len(nodes))
 	for _, node := range nodes {
 		card := model.Card{
 			Front:        node.Word,
 			Back:         node.Definition,
+			Reading:      node.Reading,
+			Senses:       node.Senses,
 			ReviewDate:   time.Now().UTC(),
 			IntervalDays: 1,
 			Created:      time.Now().UTC(),
 			Updated:      time.Now().UTC(),
-			CardGroupID:  cardGroupID,
+			CardGroupID:  input.CardgroupID,
 			CardGroup:    nil, // Assuming this will be populated later or left nil
 		}
 		cards = append(cards, card)
 	}
 
-	// Use AddNewCards to add the generated cards to the card service
-	createdCards, err := dmu.cardService.AddNewCards(ctx, cards, cardGroupID)
+	if autoFill {
+		if err := dmu.fillBacks(cards); err != nil {
+			return nil, err
+		}
+		if err := dmu.fillReadings(cards); err != nil {
+			return nil, err
+		}
+	}
+	return cards, nil
+}
+
+// FillReadings fills in the readings of the cards of the card group without a reading
+// by the local dictionary, and returns the cards given a reading.
+func (dmu *dictionaryManagerUsecase) FillReadings(ctx context.Context, cardGroupID int64) ([]*model.Card, error) {
+	cards, err := dmu.cardService.FetchAllCardsByCardGroup(ctx, cardGroupID, nil)
 	if err != nil {
-		return nil, goerr.Wrap(err, "failed to add new cards")
+		return nil, goerr.Wrap(err, "failed to fetch cards")
 	}
 
-	return createdCards, nil
+	targets := make([]model.Card, 0, len(cards))
+	for _, card := range cards {
+		if card.Reading == "" {
+			targets = append(targets, *card)
+		}
+	}
+	if err := dmu.fillReadings(targets); err != nil {
+		return nil, err
+	}
+
+	readings := make(map[int64]string)
+	filled := make([]*model.Card, 0, len(targets))
+	for i := range targets {
+		if targets[i].Reading == "" {
+			continue
+		}
+		targets[i].Ruby = furigana.Ruby(targets[i].Reading)
+		readings[targets[i].ID] = targets[i].Reading
+		filled = append(filled, &targets[i])
+	}
+	if err := dmu.cardService.SetReadings(ctx, cardGroupID, readings); err != nil {
+		return nil, goerr.Wrap(err, "failed to set readings")
+	}
+	return filled, nil
+}
+
+// fillReadings annotates the Japanese side of the cards without a reading, which is the front when
+// it is Japanese and the back otherwise. Each sense is looked up as a word, and cards none of whose
+// words are in the dictionary are left without a reading.
+func (dmu *dictionaryManagerUsecase) fillReadings(cards []model.Card) error {
+	var dic *jmdict.Dictionary
+	for i := range cards {
+		if cards[i].Reading != "" {
+			continue
+		}
+		if dic == nil {
+			var err error
+			if dic, err = dmu.dictionary.Get(); err != nil {
+				return goerr.Wrap(err, "failed to load dictionary")
+			}
+		}
+
+		text, words := cards[i].Back, cards[i].Senses
+		if jmdict.IsJapanese(cards[i].Front) {
+			text, words = cards[i].Front, nil
+		}
+		if len(words) == 0 {
+			words = []string{text}
+		}
+		cards[i].Reading = dic.Annotate(text, words)
+	}
+	return nil
+}
+
+// fillBacks defines the cards without a back by the local dictionary.
+// The dictionary is loaded only when a back is missing.
+func (dmu *dictionaryManagerUsecase) fillBacks(cards []model.Card) error {
+	var dic *jmdict.Dictionary
+	for i := range cards {
+		if cards[i].Back != "" {
+			continue
+		}
+		if dic == nil {
+			var err error
+			if dic, err = dmu.dictionary.Get(); err != nil {
+				return goerr.Wrap(err, "failed to load dictionary")
+			}
+		}
+
+		back, senses, ok := dic.Define(cards[i].Front)
+		if !ok {
+			return goerr.Wrap(fmt.Errorf("no definition found for : %s", cards[i].Front))
+		}
+		cards[i].Back = back
+		cards[i].Senses = senses
+	}
+	return nil
 }
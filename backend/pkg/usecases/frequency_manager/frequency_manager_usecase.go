@@ -0,0 +1,59 @@
+package frequency_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/frequency"
+	"context"
+
+	"github.com/m-mizutani/goerr"
+)
+
+type FrequencyManagerUsecase interface {
+	RankCardsByFrequency(ctx context.Context, cardGroupID int64) ([]*model.Card, error)
+}
+
+type frequencyManagerUsecase struct {
+	cardService   services.CardService
+	frequencyList *frequency.Lazy
+}
+
+func NewFrequencyManagerUsecase(cardService services.CardService, frequencyList *frequency.Lazy) FrequencyManagerUsecase {
+	return &frequencyManagerUsecase{
+		cardService:   cardService,
+		frequencyList: frequencyList,
+	}
+}
+
+// RankCardsByFrequency stores the rank of the front of each card of the card group in the local
+// frequency list, and returns the cards with their new ranks. Cards whose front is not in the list
+// lose their rank, so that they come after the ranked cards in FREQUENCY order.
+func (f *frequencyManagerUsecase) RankCardsByFrequency(ctx context.Context, cardGroupID int64) ([]*model.Card, error) {
+	list, err := f.frequencyList.Get()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to load frequency list")
+	}
+
+	cards, err := f.cardService.FetchAllCardsByCardGroup(ctx, cardGroupID, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fetch cards")
+	}
+
+	ranks := make(map[int64]int, len(cards))
+	for _, card := range cards {
+		if rank, ok := list.Rank(card.Front); ok {
+			ranks[card.ID] = rank
+		}
+	}
+	if err := f.cardService.SetFrequencyRanks(ctx, cardGroupID, ranks); err != nil {
+		return nil, goerr.Wrap(err, "failed to set frequency ranks")
+	}
+
+	for _, card := range cards {
+		card.FrequencyRank = nil
+		if rank, ok := ranks[card.ID]; ok {
+			card.FrequencyRank = &rank
+		}
+	}
+	return cards, nil
+}
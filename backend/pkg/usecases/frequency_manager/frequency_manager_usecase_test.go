@@ -0,0 +1,112 @@
+package frequency_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/frequency"
+	"backend/testutils"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+var sv services.Services
+var userService services.UserService
+var cardGroupService services.CardGroupService
+var cardService services.CardService
+var roleService services.RoleService
+
+var migrationFilePath = "../../../db/migrations"
+var frequencyListPath = "../../frequency/testdata/frequency.tsv"
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "flamingo")
+	if err != nil {
+		log.Fatalf("Failed to setup test database: %+v", err)
+	}
+	defer cleanup(migrationFilePath)
+
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		log.Fatalf("failed to run migrations: %+v", err)
+	}
+
+	db = pg.GetDB()
+	sv = services.New(db)
+
+	userService = sv.(services.UserService)
+	cardGroupService = sv.(services.CardGroupService)
+	cardService = sv.(services.CardService)
+	roleService = sv.(services.RoleService)
+
+	m.Run()
+}
+
+func addCards(ctx context.Context, cardGroupID int64, fronts ...string) error {
+	cards := make([]model.Card, 0, len(fronts))
+	for _, front := range fronts {
+		cards = append(cards, model.Card{
+			Front:        front,
+			Back:         "back of " + front,
+			ReviewDate:   time.Now().UTC(),
+			IntervalDays: 1,
+			Created:      time.Now().UTC(),
+			Updated:      time.Now().UTC(),
+			CardGroupID:  cardGroupID,
+		})
+	}
+	_, err := cardService.AddNewCards(ctx, cards, cardGroupID)
+	return err
+}
+
+func TestRankCardsByFrequency(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_RankCardsByFrequency", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			assert.NoError(t, addCards(ctx, cardGroup.ID, "walk", "Run", "走る"))
+			usecase := NewFrequencyManagerUsecase(cardService, frequency.NewLazy(frequencyListPath))
+
+			// Act
+			cards, err := usecase.RankCardsByFrequency(ctx, cardGroup.ID)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cards, 3)
+			stored, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroup.ID, nil)
+			assert.NoError(t, err)
+			ranks := make(map[string]*int)
+			for _, card := range stored {
+				ranks[card.Front] = card.FrequencyRank
+			}
+			assert.Nil(t, ranks["walk"])
+			assert.Equal(t, 3, *ranks["Run"])
+			assert.Equal(t, 4, *ranks["走る"])
+		})
+
+		t.Run("Error_RankCardsByFrequency_NotConfigured", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			usecase := NewFrequencyManagerUsecase(cardService, frequency.NewLazy(""))
+
+			// Act
+			cards, err := usecase.RankCardsByFrequency(ctx, cardGroup.ID)
+
+			// Assert
+			assert.ErrorIs(t, err, frequency.ErrNotConfigured)
+			assert.Nil(t, cards)
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package kindle_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/kindle"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+)
+
+type KindleManagerUsecase interface {
+	ImportKindleVocabulary(ctx context.Context, input model.ImportKindleVocabulary) ([]*model.Card, error)
+}
+
+type kindleManagerUsecase struct {
+	cardService services.CardService
+}
+
+func NewKindleManagerUsecase(cardService services.CardService) KindleManagerUsecase {
+	return &kindleManagerUsecase{
+		cardService: cardService,
+	}
+}
+
+// ImportKindleVocabulary adds the words of a base64 encoded Kindle vocab.db to the card group.
+// A card is made per dictionary form of the words, with the sentences of the books on the back.
+// Cards go through AddNewCards, so words already in the card group are updated instead of duplicated.
+func (k *kindleManagerUsecase) ImportKindleVocabulary(ctx context.Context, input model.ImportKindleVocabulary) ([]*model.Card, error) {
+	data, err := base64.StdEncoding.DecodeString(input.Vocabulary)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to decode base64 vocabulary")
+	}
+
+	words, err := kindle.ReadVocabulary(data)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read kindle vocabulary")
+	}
+
+	cards := wordsToCards(words, input.Language, input.CardgroupID)
+
+	createdCards, err := k.cardService.AddNewCards(ctx, cards, input.CardgroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to add new cards")
+	}
+
+	return createdCards, nil
+}
+
+// wordsToCards merges the words sharing a dictionary form, such as "ran" and "running",
+// into a card. Words without a sentence have the looked up form on the back.
+func wordsToCards(words []*kindle.Word, language *string, cardGroupID int64) []model.Card {
+	var headwords []string
+	backs := make(map[string][]string)
+	for _, word := range words {
+		if language != nil && *language != "" && word.Lang != *language {
+			continue
+		}
+		headword := strings.TrimSpace(word.Headword())
+		if headword == "" {
+			continue
+		}
+		if _, ok := backs[headword]; !ok {
+			headwords = append(headwords, headword)
+		}
+
+		if len(word.Lookups) == 0 {
+			backs[headword] = appendUnique(backs[headword], word.Word)
+			continue
+		}
+		for _, lookup := range word.Lookups {
+			backs[headword] = appendUnique(backs[headword], usageWithTitle(lookup))
+		}
+	}
+
+	cards := make([]model.Card, 0, len(headwords))
+	for _, headword := range headwords {
+		cards = append(cards, model.Card{
+			Front:        headword,
+			Back:         strings.Join(backs[headword], "\n"),
+			ReviewDate:   time.Now().UTC(),
+			IntervalDays: 1,
+			Created:      time.Now().UTC(),
+			Updated:      time.Now().UTC(),
+			CardGroupID:  cardGroupID,
+		})
+	}
+	return cards
+}
+
+// usageWithTitle returns the sentence followed by the title of the book
+func usageWithTitle(lookup *kindle.Lookup) string {
+	usage := strings.TrimSpace(lookup.Usage)
+	if lookup.BookTitle == "" {
+		return usage
+	}
+	return fmt.Sprintf("%s (%s)", usage, lookup.BookTitle)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,147 @@
+package kindle_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/testutils"
+	"context"
+	"encoding/base64"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+var sv services.Services
+var userService services.UserService
+var cardGroupService services.CardGroupService
+var cardService services.CardService
+var roleService services.RoleService
+
+var migrationFilePath = "../../../db/migrations"
+
+// vocabularyPath is a vocab.db with "running" and "serendipity" looked up in English and "走る" in Japanese
+var vocabularyPath = "../../kindle/testdata/vocab.db"
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "flamingo")
+	if err != nil {
+		log.Fatalf("Failed to setup test database: %+v", err)
+	}
+	defer cleanup(migrationFilePath)
+
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		log.Fatalf("failed to run migrations: %+v", err)
+	}
+
+	db = pg.GetDB()
+	sv = services.New(db)
+
+	userService = sv.(services.UserService)
+	cardGroupService = sv.(services.CardGroupService)
+	cardService = sv.(services.CardService)
+	roleService = sv.(services.RoleService)
+
+	m.Run()
+}
+
+func TestImportKindleVocabulary(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	usecase := NewKindleManagerUsecase(cardService)
+
+	data, err := os.ReadFile(vocabularyPath)
+	if err != nil {
+		t.Fatalf("failed to read vocabulary: %+v", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_ImportKindleVocabulary", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cards, err := usecase.ImportKindleVocabulary(ctx, model.ImportKindleVocabulary{
+				CardgroupID: cardGroup.ID,
+				Vocabulary:  encoded,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cards, 3)
+			fetched, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroup.ID, nil)
+			assert.NoError(t, err)
+			backs := make(map[string]string)
+			for _, card := range fetched {
+				backs[card.Front] = card.Back
+			}
+			assert.Equal(t, "He was running late. (The Book)\nThey kept running. (The Book)", backs["run"])
+			assert.Equal(t, "It was pure serendipity. (The Book)", backs["serendipity"])
+			assert.Equal(t, "走る", backs["走る"])
+		})
+
+		t.Run("Normal_ImportKindleVocabulary_Language", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			language := "en"
+
+			// Act
+			cards, err := usecase.ImportKindleVocabulary(ctx, model.ImportKindleVocabulary{
+				CardgroupID: cardGroup.ID,
+				Vocabulary:  encoded,
+				Language:    &language,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cards, 2)
+		})
+
+		t.Run("Normal_ImportKindleVocabulary_Twice", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			input := model.ImportKindleVocabulary{
+				CardgroupID: cardGroup.ID,
+				Vocabulary:  encoded,
+			}
+			_, err = usecase.ImportKindleVocabulary(ctx, input)
+			assert.NoError(t, err)
+
+			// Act
+			cards, err := usecase.ImportKindleVocabulary(ctx, input)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Empty(t, cards) // Unchanged cards are not updated
+			fetched, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroup.ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, fetched, 3)
+		})
+
+		t.Run("Error_ImportKindleVocabulary_InvalidBase64", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cards, err := usecase.ImportKindleVocabulary(ctx, model.ImportKindleVocabulary{
+				CardgroupID: cardGroup.ID,
+				Vocabulary:  "invalid base64",
+			})
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, cards)
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package prose_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/jmdict"
+	"backend/pkg/prose"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+
+	"github.com/m-mizutani/goerr"
+)
+
+type ProseManagerUsecase interface {
+	ProseCandidates(ctx context.Context, input model.ProseCandidates) ([]*model.ProseCandidate, error)
+}
+
+type proseManagerUsecase struct {
+	cardService services.CardService
+	analyzer    *prose.Analyzer
+	dictionary  *jmdict.Lazy
+}
+
+func NewProseManagerUsecase(cardService services.CardService, analyzer *prose.Analyzer, dictionary *jmdict.Lazy) ProseManagerUsecase {
+	return &proseManagerUsecase{
+		cardService: cardService,
+		analyzer:    analyzer,
+		dictionary:  dictionary,
+	}
+}
+
+// ProseCandidates splits a Japanese text into words in their dictionary form and returns the words
+// not in the card group yet as candidate cards without creating them. Particles and other function
+// words are left out. When the local dictionary is configured, the backs are filled in with its
+// definitions, otherwise they are left empty for the user to write.
+func (p *proseManagerUsecase) ProseCandidates(ctx context.Context, input model.ProseCandidates) ([]*model.ProseCandidate, error) {
+	words, err := p.analyzer.Words(input.Text)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to analyze text")
+	}
+
+	cards, err := p.cardService.FetchAllCardsByCardGroup(ctx, input.CardgroupID, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fetch cards")
+	}
+	known := make(map[string]bool, len(cards))
+	for _, card := range cards {
+		known[utils.Normalize(card.Front)] = true
+	}
+
+	dic, err := p.dictionary.Get()
+	if err != nil && !errors.Is(err, jmdict.ErrNotConfigured) {
+		return nil, goerr.Wrap(err, "failed to load dictionary")
+	}
+
+	candidates := make([]*model.ProseCandidate, 0, len(words))
+	for _, word := range words {
+		if known[utils.Normalize(word.BaseForm)] || known[utils.Normalize(word.Surface)] {
+			continue
+		}
+
+		candidate := &model.ProseCandidate{
+			Front:        word.BaseForm,
+			Reading:      word.Reading,
+			PartOfSpeech: word.PartOfSpeech,
+			Count:        word.Count,
+		}
+		if dic != nil {
+			if back, _, ok := dic.Define(word.BaseForm); ok {
+				candidate.Back = back
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
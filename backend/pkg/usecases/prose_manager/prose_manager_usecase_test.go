@@ -0,0 +1,134 @@
+package prose_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/jmdict"
+	"backend/pkg/prose"
+	"backend/testutils"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+var sv services.Services
+var userService services.UserService
+var cardGroupService services.CardGroupService
+var cardService services.CardService
+var roleService services.RoleService
+
+var migrationFilePath = "../../../db/migrations"
+var jmdictPath = "../../jmdict/testdata/JMdict_e.xml"
+
+const testText = "友達が駅まで走った。友達は速かった。"
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "flamingo")
+	if err != nil {
+		log.Fatalf("Failed to setup test database: %+v", err)
+	}
+	defer cleanup(migrationFilePath)
+
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		log.Fatalf("failed to run migrations: %+v", err)
+	}
+
+	db = pg.GetDB()
+	sv = services.New(db)
+
+	userService = sv.(services.UserService)
+	cardGroupService = sv.(services.CardGroupService)
+	cardService = sv.(services.CardService)
+	roleService = sv.(services.RoleService)
+
+	m.Run()
+}
+
+func TestProseCandidates(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	analyzer := prose.NewAnalyzer()
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_ProseCandidates", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			usecase := NewProseManagerUsecase(cardService, analyzer, jmdict.NewLazy(""))
+
+			// Act
+			candidates, err := usecase.ProseCandidates(ctx, model.ProseCandidates{
+				CardgroupID: cardGroup.ID,
+				Text:        testText,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			fronts := make([]string, 0, len(candidates))
+			for _, candidate := range candidates {
+				fronts = append(fronts, candidate.Front)
+			}
+			assert.Equal(t, []string{"友達", "駅", "走る", "速い"}, fronts) // Particles are left out
+			assert.Equal(t, 2, candidates[0].Count)
+			assert.Equal(t, "", candidates[0].Back) // No dictionary is configured
+			assert.Equal(t, "動詞", candidates[2].PartOfSpeech)
+		})
+
+		t.Run("Normal_ProseCandidates_KnownWordsAndDictionary", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			_, err = cardService.AddNewCards(ctx, []model.Card{{
+				Front:        "駅",
+				Back:         "station",
+				ReviewDate:   time.Now().UTC(),
+				IntervalDays: 1,
+				Created:      time.Now().UTC(),
+				Updated:      time.Now().UTC(),
+				CardGroupID:  cardGroup.ID,
+			}}, cardGroup.ID)
+			assert.NoError(t, err)
+			usecase := NewProseManagerUsecase(cardService, analyzer, jmdict.NewLazy(jmdictPath))
+
+			// Act
+			candidates, err := usecase.ProseCandidates(ctx, model.ProseCandidates{
+				CardgroupID: cardGroup.ID,
+				Text:        testText,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, candidates, 3)
+			assert.Equal(t, "友達", candidates[0].Front)
+			assert.Equal(t, "", candidates[0].Back) // Words missing from the dictionary are left for the user
+			assert.Equal(t, "走る", candidates[1].Front)
+			assert.Equal(t, "to run; to travel (movement of vehicles), to drive", candidates[1].Back)
+			assert.Equal(t, "速い", candidates[2].Front)
+		})
+
+		t.Run("Error_ProseCandidates_DictionaryNotFound", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			usecase := NewProseManagerUsecase(cardService, analyzer, jmdict.NewLazy("not_found.xml"))
+
+			// Act
+			candidates, err := usecase.ProseCandidates(ctx, model.ProseCandidates{
+				CardgroupID: cardGroup.ID,
+				Text:        testText,
+			})
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, candidates)
+		})
+	})
+}
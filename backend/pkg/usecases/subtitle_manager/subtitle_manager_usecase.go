@@ -0,0 +1,110 @@
+package subtitle_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/subtitle"
+	"backend/pkg/utils"
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr"
+)
+
+type SubtitleManagerUsecase interface {
+	SubtitleCandidates(ctx context.Context, input model.SubtitleCandidates) ([]*model.SubtitleCandidate, error)
+	ConfirmSubtitleCandidates(ctx context.Context, input model.ConfirmSubtitleCandidates) ([]*model.Card, error)
+}
+
+type subtitleManagerUsecase struct {
+	cardService services.CardService
+	tokenizer   subtitle.Tokenizer
+}
+
+func NewSubtitleManagerUsecase(cardService services.CardService, tokenizer subtitle.Tokenizer) SubtitleManagerUsecase {
+	return &subtitleManagerUsecase{
+		cardService: cardService,
+		tokenizer:   tokenizer,
+	}
+}
+
+// SubtitleCandidates turns the lines of base64 encoded subtitles into candidate sentence cards without
+// creating them. With a translation track, lines are paired up by timestamp and the translation goes
+// on the back. Lines already in the card group, repeated lines and lines whose words are all known
+// in the card group are left out.
+func (s *subtitleManagerUsecase) SubtitleCandidates(ctx context.Context, input model.SubtitleCandidates) ([]*model.SubtitleCandidate, error) {
+	cues, err := parseSubtitle(input.Subtitle)
+	if err != nil {
+		return nil, err
+	}
+
+	var translations []*subtitle.Cue
+	if input.Translation != nil && *input.Translation != "" {
+		if translations, err = parseSubtitle(*input.Translation); err != nil {
+			return nil, err
+		}
+	}
+
+	cards, err := s.cardService.FetchAllCardsByCardGroup(ctx, input.CardgroupID, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fetch cards")
+	}
+	fronts := make([]string, 0, len(cards))
+	for _, card := range cards {
+		fronts = append(fronts, card.Front)
+	}
+	known := subtitle.NewKnownWords(fronts)
+
+	candidates := make([]*model.SubtitleCandidate, 0, len(cues))
+	seen := make(map[string]bool, len(cues))
+	for _, pair := range subtitle.PairTracks(cues, translations, subtitle.DefaultPairTolerance) {
+		line := utils.Normalize(pair.Text)
+		if seen[line] || known.Contains(pair.Text) || known.AllKnown(pair.Text, s.tokenizer) {
+			continue
+		}
+		seen[line] = true
+
+		candidates = append(candidates, &model.SubtitleCandidate{
+			Front:   pair.Text,
+			Back:    pair.Translation,
+			StartMs: int(pair.Start.Milliseconds()),
+			EndMs:   int(pair.End.Milliseconds()),
+		})
+	}
+
+	return candidates, nil
+}
+
+// ConfirmSubtitleCandidates creates the sentence cards the user has confirmed
+func (s *subtitleManagerUsecase) ConfirmSubtitleCandidates(ctx context.Context, input model.ConfirmSubtitleCandidates) ([]*model.Card, error) {
+	cards := make([]model.Card, 0, len(input.Candidates))
+	for _, candidate := range input.Candidates {
+		cards = append(cards, model.Card{
+			Front:        strings.TrimSpace(candidate.Front),
+			Back:         strings.TrimSpace(candidate.Back),
+			ReviewDate:   time.Now().UTC(),
+			IntervalDays: 1,
+			Created:      time.Now().UTC(),
+			Updated:      time.Now().UTC(),
+			CardGroupID:  input.CardgroupID,
+		})
+	}
+
+	createdCards, err := s.cardService.AddNewCards(ctx, cards, input.CardgroupID)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to add new cards")
+	}
+
+	return createdCards, nil
+}
+
+// parseSubtitle decodes base64 encoded subtitles while parsing them
+func parseSubtitle(encoded string) ([]*subtitle.Cue, error) {
+	cues, err := subtitle.Parse(base64.NewDecoder(base64.StdEncoding, strings.NewReader(encoded)))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to parse subtitles")
+	}
+	return cues, nil
+}
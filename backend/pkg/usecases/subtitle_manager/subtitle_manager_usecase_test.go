@@ -0,0 +1,237 @@
+package subtitle_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/prose"
+	"backend/pkg/subtitle"
+	"backend/testutils"
+	"context"
+	"encoding/base64"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+var sv services.Services
+var userService services.UserService
+var cardGroupService services.CardGroupService
+var cardService services.CardService
+var roleService services.RoleService
+
+var migrationFilePath = "../../../db/migrations"
+
+const testSubtitle = `1
+00:00:01,000 --> 00:00:02,500
+Nice weather today.
+
+2
+00:00:03,000 --> 00:00:04,000
+Shall we go?
+
+3
+00:00:05,000 --> 00:00:06,000
+Nice weather today.
+`
+
+const testTranslation = `WEBVTT
+
+00:01.000 --> 00:02.400
+今日はいい天気だね
+
+00:03.100 --> 00:04.000
+行こうか
+`
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "flamingo")
+	if err != nil {
+		log.Fatalf("Failed to setup test database: %+v", err)
+	}
+	defer cleanup(migrationFilePath)
+
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		log.Fatalf("failed to run migrations: %+v", err)
+	}
+
+	db = pg.GetDB()
+	sv = services.New(db)
+
+	userService = sv.(services.UserService)
+	cardGroupService = sv.(services.CardGroupService)
+	cardService = sv.(services.CardService)
+	roleService = sv.(services.RoleService)
+
+	m.Run()
+}
+
+func addCards(ctx context.Context, cardGroupID int64, fronts ...string) error {
+	cards := make([]model.Card, 0, len(fronts))
+	for _, front := range fronts {
+		cards = append(cards, model.Card{
+			Front:        front,
+			Back:         "back of " + front,
+			ReviewDate:   time.Now().UTC(),
+			IntervalDays: 1,
+			Created:      time.Now().UTC(),
+			Updated:      time.Now().UTC(),
+			CardGroupID:  cardGroupID,
+		})
+	}
+	_, err := cardService.AddNewCards(ctx, cards, cardGroupID)
+	return err
+}
+
+func TestSubtitleCandidates(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	usecase := NewSubtitleManagerUsecase(cardService, subtitle.SplitWords)
+	encoded := base64.StdEncoding.EncodeToString([]byte(testSubtitle))
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_SubtitleCandidates", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			candidates, err := usecase.SubtitleCandidates(ctx, model.SubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Subtitle:    encoded,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, candidates, 2) // The repeated line is left out
+			assert.Equal(t, &model.SubtitleCandidate{Front: "Nice weather today.", Back: "", StartMs: 1000, EndMs: 2500}, candidates[0])
+		})
+
+		t.Run("Normal_SubtitleCandidates_Translation", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			translation := base64.StdEncoding.EncodeToString([]byte(testTranslation))
+
+			// Act
+			candidates, err := usecase.SubtitleCandidates(ctx, model.SubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Subtitle:    encoded,
+				Translation: &translation,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, candidates, 2)
+			assert.Equal(t, "今日はいい天気だね", candidates[0].Back)
+			assert.Equal(t, "行こうか", candidates[1].Back)
+		})
+
+		t.Run("Normal_SubtitleCandidates_KnownWords", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			assert.NoError(t, addCards(ctx, cardGroup.ID, "shall", "we", "go"))
+
+			// Act
+			candidates, err := usecase.SubtitleCandidates(ctx, model.SubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Subtitle:    encoded,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, candidates, 1)
+			assert.Equal(t, "Nice weather today.", candidates[0].Front)
+		})
+
+		t.Run("Normal_SubtitleCandidates_KnownJapaneseWords", func(t *testing.T) {
+			// Arrange
+			japaneseUsecase := NewSubtitleManagerUsecase(cardService, subtitle.NewJapaneseTokenizer(prose.NewAnalyzer()))
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			assert.NoError(t, addCards(ctx, cardGroup.ID, "今日", "いい", "天気"))
+
+			// Act
+			candidates, err := japaneseUsecase.SubtitleCandidates(ctx, model.SubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Subtitle:    base64.StdEncoding.EncodeToString([]byte(testTranslation)),
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, candidates, 1)
+			assert.Equal(t, "行こうか", candidates[0].Front)
+		})
+
+		t.Run("Error_SubtitleCandidates_InvalidSubtitle", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			candidates, err := usecase.SubtitleCandidates(ctx, model.SubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Subtitle:    "invalid base64",
+			})
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, candidates)
+		})
+	})
+}
+
+func TestConfirmSubtitleCandidates(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	usecase := NewSubtitleManagerUsecase(cardService, subtitle.SplitWords)
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_ConfirmSubtitleCandidates", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cards, err := usecase.ConfirmSubtitleCandidates(ctx, model.ConfirmSubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Candidates: []*model.SentenceCard{
+					{Front: "Shall we go?", Back: "行こうか"},
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cards, 1)
+			assert.Equal(t, "Shall we go?", cards[0].Front)
+			assert.Equal(t, "行こうか", cards[0].Back)
+			assert.Equal(t, cardGroup.ID, cards[0].CardGroupID)
+		})
+
+		t.Run("Error_ConfirmSubtitleCandidates_EmptyBack", func(t *testing.T) {
+			// Arrange
+			cardGroup, _, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cards, err := usecase.ConfirmSubtitleCandidates(ctx, model.ConfirmSubtitleCandidates{
+				CardgroupID: cardGroup.ID,
+				Candidates: []*model.SentenceCard{
+					{Front: "Shall we go?", Back: " "},
+				},
+			})
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, cards)
+		})
+	})
+}
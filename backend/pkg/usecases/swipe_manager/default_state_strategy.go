@@ -31,8 +31,8 @@ func NewDefaultStateStrategy(
 func (d *defaultStateStrategy) Run(ctx context.Context,
 	newSwipeRecord model.NewSwipeRecord) ([]*model.Card, error) {
 
-	// Fetch random recent added words
-	cards, err := d.swipeManagerUsecase.Srv().GetRandomCardsFromRecentUpdates(
+	// Fetch recent added words in the new card order of the card group
+	cards, err := d.swipeManagerUsecase.Srv().GetNewCardsFromRecentUpdates(
 		ctx,
 		newSwipeRecord.CardGroupID,
 		config.Cfg.PGQueryLimit,
@@ -30,8 +30,8 @@ func NewEasyStateStrategy(swipeManagerUsecase SwipeManagerUsecase) EasyStateStra
 func (e *easyStateStrategy) Run(ctx context.Context,
 	newSwipeRecord model.NewSwipeRecord) ([]*model.Card, error) {
 
-	// Fetch random unknown words
-	cards, err := e.swipeManagerUsecase.Srv().GetRandomCardsFromRecentUpdates(
+	// Fetch unknown words
+	cards, err := e.swipeManagerUsecase.Srv().GetNewCardsFromRecentUpdates(
 		ctx,
 		newSwipeRecord.CardGroupID,
 		config.Cfg.PGQueryLimit,
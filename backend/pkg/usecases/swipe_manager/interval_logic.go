@@ -1,6 +1,7 @@
 package swipe_manager
 
 import (
+	"backend/graph/services"
 	"sync"
 	"time"
 )
@@ -19,7 +20,7 @@ type intervalLogic struct {
 // NewIntervalLogic returns a new instance of intervalLogic.
 func NewIntervalLogic() IntervalLogic {
 	return &intervalLogic{
-		intervals: []int{1, 3, 7, 14, 30},
+		intervals: services.ReviewIntervalDays,
 	}
 }
 
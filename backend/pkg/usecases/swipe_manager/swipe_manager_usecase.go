@@ -5,6 +5,7 @@ import (
 	"backend/graph/model"
 	"backend/graph/services"
 	"backend/pkg/config"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -123,6 +124,9 @@ func (s *swipeManagerUsecase) updateRecords(
 	if err != nil {
 		return goerr.Wrap(err, "failed to fetch card by ID")
 	}
+	if card.CardGroupID != newSwipeRecord.CardGroupID {
+		return goerr.New(fmt.Sprintf("card %d does not belong to card group : %d", card.ID, newSwipeRecord.CardGroupID))
+	}
 
 	// Update the interval days using the logic
 	intervalLogic := NewIntervalLogic()
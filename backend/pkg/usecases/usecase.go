@@ -2,28 +2,67 @@ package usecases
 
 import (
 	"backend/graph/services"
+	"backend/pkg/config"
+	"backend/pkg/frequency"
+	"backend/pkg/jmdict"
+	"backend/pkg/prose"
+	"backend/pkg/subtitle"
 	"backend/pkg/textdic"
+	"backend/pkg/usecases/anki_manager"
 	"backend/pkg/usecases/dictionary_manager"
+	"backend/pkg/usecases/frequency_manager"
+	"backend/pkg/usecases/kindle_manager"
+	"backend/pkg/usecases/prose_manager"
+	"backend/pkg/usecases/subtitle_manager"
 	"backend/pkg/usecases/swipe_manager"
+	"backend/pkg/usecases/workbook_manager"
 )
 
 // Usecases interface aggregates all usecases interfaces
 type Usecases interface {
+	anki_manager.AnkiManagerUsecase
 	dictionary_manager.DictionaryManagerUsecase
+	frequency_manager.FrequencyManagerUsecase
+	kindle_manager.KindleManagerUsecase
+	prose_manager.ProseManagerUsecase
+	subtitle_manager.SubtitleManagerUsecase
 	swipe_manager.SwipeManagerUsecase
+	workbook_manager.WorkbookManagerUsecase
 }
 
 // usecases struct holds references to all usecases implementations
 type usecases struct {
+	anki_manager.AnkiManagerUsecase
 	dictionary_manager.DictionaryManagerUsecase
+	frequency_manager.FrequencyManagerUsecase
+	kindle_manager.KindleManagerUsecase
+	prose_manager.ProseManagerUsecase
+	subtitle_manager.SubtitleManagerUsecase
 	swipe_manager.SwipeManagerUsecase
+	workbook_manager.WorkbookManagerUsecase
 }
 
 // New creates a new instance of Usecases with the provided services
 func New(sv services.Services) Usecases {
+	dictionary := jmdict.NewLazy(config.Cfg.FLJMdictPath)
+	analyzer := prose.NewAnalyzer()
 	return &usecases{
+		AnkiManagerUsecase: anki_manager.NewAnkiManagerUsecase(sv, config.Cfg.FLDictionaryMaxBytes),
 		DictionaryManagerUsecase: dictionary_manager.NewDictionaryManagerUsecase(
-			sv.(services.CardService), textdic.NewTextDictionaryService()),
-		SwipeManagerUsecase: swipe_manager.NewSwipeManagerUsecase(sv),
+			sv.(services.CardService),
+			textdic.NewTextDictionaryServiceWithOptions(textdic.Options{
+				SenseSplitter: textdic.NewSenseSplitter(config.Cfg.FLSenseSeparators),
+				MaxBytes:      config.Cfg.FLDictionaryMaxBytes,
+			}),
+			dictionary),
+		FrequencyManagerUsecase: frequency_manager.NewFrequencyManagerUsecase(
+			sv.(services.CardService), frequency.NewLazy(config.Cfg.FLFrequencyListPath)),
+		KindleManagerUsecase: kindle_manager.NewKindleManagerUsecase(sv.(services.CardService)),
+		ProseManagerUsecase: prose_manager.NewProseManagerUsecase(
+			sv.(services.CardService), analyzer, dictionary),
+		SubtitleManagerUsecase: subtitle_manager.NewSubtitleManagerUsecase(
+			sv.(services.CardService), subtitle.NewJapaneseTokenizer(analyzer)),
+		SwipeManagerUsecase:    swipe_manager.NewSwipeManagerUsecase(sv),
+		WorkbookManagerUsecase: workbook_manager.NewWorkbookManagerUsecase(sv, dictionary),
 	}
 }
@@ -0,0 +1,188 @@
+package workbook_manager
+
+import (
+	repository "backend/graph/db"
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/jmdict"
+	"backend/pkg/workbook"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/m-mizutani/goerr"
+)
+
+type WorkbookManagerUsecase interface {
+	ImportWorkbook(ctx context.Context, userID int64, input model.ImportWorkbook) ([]*model.CardGroup, error)
+}
+
+type workbookManagerUsecase struct {
+	services   services.Services
+	dictionary *jmdict.Lazy
+}
+
+func NewWorkbookManagerUsecase(services services.Services, dictionary *jmdict.Lazy) WorkbookManagerUsecase {
+	return &workbookManagerUsecase{
+		services:   services,
+		dictionary: dictionary,
+	}
+}
+
+// sheetCards are the cards read from a sheet
+type sheetCards struct {
+	name  string
+	cards []model.Card
+}
+
+// ImportWorkbook creates card groups of the user from a base64 encoded Excel workbook. The chosen columns
+// are mapped to front, back and tags, and either each sheet becomes a card group named after the sheet,
+// or all sheets are merged into a card group with the given name. Every row is validated before any
+// card group is created, and cards are added through AddNewCards like upsertDictionary.
+// With auto-fill, rows without a back are defined by the local dictionary before the validation.
+func (w *workbookManagerUsecase) ImportWorkbook(ctx context.Context, userID int64, input model.ImportWorkbook) ([]*model.CardGroup, error) {
+	merged := input.Mode != nil && *input.Mode == model.WorkbookImportModeMerged
+	if merged && (input.Name == nil || strings.TrimSpace(*input.Name) == "") {
+		return nil, goerr.Wrap(fmt.Errorf("name is required to merge sheets"))
+	}
+
+	sheets, err := workbook.Read(base64.NewDecoder(base64.StdEncoding, strings.NewReader(input.Workbook)))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read workbook")
+	}
+
+	var dic *jmdict.Dictionary
+	if input.AutoFill != nil && *input.AutoFill {
+		if dic, err = w.dictionary.Get(); err != nil {
+			return nil, goerr.Wrap(err, "failed to load dictionary")
+		}
+	}
+
+	var groups []sheetCards
+	for _, sheet := range sheets {
+		cards, err := readCards(sheet, input, dic)
+		if err != nil {
+			return nil, err
+		}
+		if len(cards) == 0 {
+			continue
+		}
+		if merged && len(groups) > 0 {
+			groups[0].cards = append(groups[0].cards, cards...)
+			continue
+		}
+		groups = append(groups, sheetCards{name: sheet.Name, cards: cards})
+	}
+	if len(groups) == 0 {
+		return nil, goerr.Wrap(fmt.Errorf("no cards found in workbook"))
+	}
+	if merged {
+		groups[0].name = strings.TrimSpace(*input.Name)
+	}
+
+	cardGroups := make([]*model.CardGroup, 0, len(groups))
+	for _, group := range groups {
+		cardGroup, err := w.createCardGroup(ctx, group, userID)
+		if err != nil {
+			return nil, err
+		}
+		cardGroups = append(cardGroups, cardGroup)
+	}
+
+	return cardGroups, nil
+}
+
+func (w *workbookManagerUsecase) createCardGroup(ctx context.Context, group sheetCards, userID int64) (*model.CardGroup, error) {
+	cardGroup, err := w.services.CreateCardGroup(ctx, model.NewCardGroup{
+		Name:    group.name,
+		Created: time.Now().UTC(),
+		Updated: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, goerr.Wrap(err, fmt.Errorf("failed to create card group : %s", group.name))
+	}
+	if _, err := w.services.AddUserToCardGroup(ctx, userID, cardGroup.ID, model.CardGroupRoleOwner); err != nil {
+		return nil, goerr.Wrap(err, "failed to add user to card group")
+	}
+
+	for i := range group.cards {
+		group.cards[i].CardGroupID = cardGroup.ID
+	}
+	if _, err := w.services.AddNewCards(ctx, group.cards, cardGroup.ID); err != nil {
+		return nil, goerr.Wrap(err, "failed to add new cards")
+	}
+
+	return cardGroup, nil
+}
+
+// readCards converts the rows of the sheet into cards. Empty rows are skipped,
+// and a row failing the validation of cards is reported with its position.
+// When dic is given, an empty back is filled in with the definition of the front.
+func readCards(sheet *workbook.Sheet, input model.ImportWorkbook, dic *jmdict.Dictionary) ([]model.Card, error) {
+	withHeader := input.HeaderRow == nil || *input.HeaderRow
+
+	front, err := sheet.ColumnIndex(input.FrontColumn, withHeader)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid front column")
+	}
+	back, err := sheet.ColumnIndex(input.BackColumn, withHeader)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid back column")
+	}
+	tags := -1
+	if input.TagsColumn != nil && *input.TagsColumn != "" {
+		if tags, err = sheet.ColumnIndex(*input.TagsColumn, withHeader); err != nil {
+			return nil, goerr.Wrap(err, "invalid tags column")
+		}
+	}
+
+	rows := sheet.Rows
+	firstRow := 1
+	if withHeader && len(rows) > 0 {
+		rows = rows[1:]
+		firstRow = 2
+	}
+
+	cards := make([]model.Card, 0, len(rows))
+	for i, row := range rows {
+		card := model.Card{
+			Front:        workbook.Cell(row, front),
+			Back:         workbook.Cell(row, back),
+			Tags:         splitTags(workbook.Cell(row, tags)),
+			ReviewDate:   time.Now().UTC(),
+			IntervalDays: 1,
+			Created:      time.Now().UTC(),
+			Updated:      time.Now().UTC(),
+		}
+		if card.Front == "" && card.Back == "" && len(card.Tags) == 0 {
+			continue
+		}
+		if dic != nil && card.Back == "" && card.Front != "" {
+			if back, senses, ok := dic.Define(card.Front); ok {
+				card.Back = back
+				card.Senses = senses
+			}
+		}
+
+		if err := (&repository.Card{
+			Front:        card.Front,
+			Back:         card.Back,
+			ReviewDate:   card.ReviewDate,
+			IntervalDays: card.IntervalDays,
+		}).Validate(); err != nil {
+			return nil, goerr.Wrap(err, fmt.Errorf("invalid row %d in sheet %s", firstRow+i, sheet.Name))
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// splitTags splits a cell into tags separated by spaces or commas
+func splitTags(cell string) []string {
+	return strings.FieldsFunc(cell, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '、' || r == ';'
+	})
+}
@@ -0,0 +1,252 @@
+package workbook_manager
+
+import (
+	"backend/graph/model"
+	"backend/graph/services"
+	"backend/pkg/jmdict"
+	"backend/testutils"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+var db *gorm.DB
+var sv services.Services
+var userService services.UserService
+var cardGroupService services.CardGroupService
+var cardService services.CardService
+var roleService services.RoleService
+
+var migrationFilePath = "../../../db/migrations"
+var jmdictPath = "../../jmdict/testdata/JMdict_e.xml"
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pg, cleanup, err := testutils.SetupTestDB(ctx, "user", "password", "flamingo")
+	if err != nil {
+		log.Fatalf("Failed to setup test database: %+v", err)
+	}
+	defer cleanup(migrationFilePath)
+
+	if err := pg.RunGooseMigrationsUp(migrationFilePath); err != nil {
+		log.Fatalf("failed to run migrations: %+v", err)
+	}
+
+	db = pg.GetDB()
+	sv = services.New(db)
+
+	userService = sv.(services.UserService)
+	cardGroupService = sv.(services.CardGroupService)
+	cardService = sv.(services.CardService)
+	roleService = sv.(services.RoleService)
+
+	m.Run()
+}
+
+// encodeWorkbook writes a base64 encoded workbook with the sheets in the order of names
+func encodeWorkbook(t *testing.T, names []string, sheets map[string][][]any) string {
+	t.Helper()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, name := range names {
+		if i == 0 {
+			assert.NoError(t, f.SetSheetName("Sheet1", name))
+		} else {
+			_, err := f.NewSheet(name)
+			assert.NoError(t, err)
+		}
+		for r, row := range sheets[name] {
+			cell, err := excelize.CoordinatesToCellName(1, r+1)
+			assert.NoError(t, err)
+			assert.NoError(t, f.SetSheetRow(name, cell, &row))
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.Write(&buf))
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestImportWorkbook(t *testing.T) {
+	t.Helper()
+	t.Parallel()
+	ctx := context.Background()
+	usecase := NewWorkbookManagerUsecase(sv, jmdict.NewLazy(jmdictPath))
+
+	encoded := encodeWorkbook(t, []string{"Verbs", "Nouns"}, map[string][][]any{
+		"Verbs": {{"Word", "Meaning", "Tags"}, {"run", "走る", "jlpt5, verb"}, {}, {"walk", "歩く", ""}},
+		"Nouns": {{"Word", "Meaning", "Tags"}, {"rube", "田舎者", "slang"}, {"run", "走ること", ""}},
+	})
+	tagsColumn := "Tags"
+
+	testutils.RunServersTest(t, db, func(t *testing.T) {
+		t.Run("Normal_ImportWorkbook_SheetPerGroup", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, user.ID, model.ImportWorkbook{
+				Workbook:    encoded,
+				FrontColumn: "Word",
+				BackColumn:  "Meaning",
+				TagsColumn:  &tagsColumn,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cardGroups, 2)
+			assert.Equal(t, "Verbs", cardGroups[0].Name)
+			assert.Equal(t, "Nouns", cardGroups[1].Name)
+			cards, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroups[0].ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, cards, 2) // The empty row is skipped
+			assert.Equal(t, "run", cards[0].Front)
+			assert.Equal(t, []string{"jlpt5", "verb"}, cards[0].Tags)
+			assert.Equal(t, []string{}, cards[1].Tags)
+		})
+
+		t.Run("Normal_ImportWorkbook_Merged", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			mode := model.WorkbookImportModeMerged
+			name := "Vocabulary"
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, user.ID, model.ImportWorkbook{
+				Workbook:    encoded,
+				FrontColumn: "A",
+				BackColumn:  "B",
+				Mode:        &mode,
+				Name:        &name,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cardGroups, 1)
+			assert.Equal(t, "Vocabulary", cardGroups[0].Name)
+			cards, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroups[0].ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, cards, 3)
+			backs := make(map[string]string)
+			for _, card := range cards {
+				backs[card.Front] = card.Back
+			}
+			assert.Equal(t, "走ること", backs["run"]) // The later sheet wins
+		})
+
+		t.Run("Error_ImportWorkbook_MissingBack", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			invalid := encodeWorkbook(t, []string{"Verbs"}, map[string][][]any{
+				"Verbs": {{"Word", "Meaning"}, {"run", "走る"}, {"walk"}},
+			})
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, user.ID, model.ImportWorkbook{
+				Workbook:    invalid,
+				FrontColumn: "Word",
+				BackColumn:  "Meaning",
+			})
+
+			// Assert
+			assert.ErrorContains(t, err, "invalid row 3 in sheet Verbs")
+			assert.Nil(t, cardGroups)
+		})
+
+		t.Run("Normal_ImportWorkbook_AutoFill", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			fronts := encodeWorkbook(t, []string{"Words"}, map[string][][]any{
+				"Words": {{"Word", "Meaning"}, {"run"}, {"rube", "田舎者"}, {"走る"}},
+			})
+			autoFill := true
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, user.ID, model.ImportWorkbook{
+				Workbook:    fronts,
+				FrontColumn: "Word",
+				BackColumn:  "Meaning",
+				AutoFill:    &autoFill,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, cardGroups, 1)
+			cards, err := cardService.FetchAllCardsByCardGroup(ctx, cardGroups[0].ID, nil)
+			assert.NoError(t, err)
+			assert.Len(t, cards, 3)
+			assert.Equal(t, "走る、駆ける", cards[0].Back)
+			assert.Equal(t, []string{"走る", "駆ける"}, cards[0].Senses)
+			assert.Equal(t, "田舎者", cards[1].Back)
+			assert.Equal(t, "to run; to travel (movement of vehicles), to drive", cards[2].Back)
+		})
+
+		t.Run("Error_ImportWorkbook_AutoFillNotFound", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+			fronts := encodeWorkbook(t, []string{"Words"}, map[string][][]any{
+				"Words": {{"Word", "Meaning"}, {"run"}, {"walk"}},
+			})
+			autoFill := true
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, user.ID, model.ImportWorkbook{
+				Workbook:    fronts,
+				FrontColumn: "Word",
+				BackColumn:  "Meaning",
+				AutoFill:    &autoFill,
+			})
+
+			// Assert
+			assert.ErrorContains(t, err, "invalid row 3 in sheet Words")
+			assert.Nil(t, cardGroups)
+		})
+
+		t.Run("Error_ImportWorkbook_UnknownColumn", func(t *testing.T) {
+			// Arrange
+			_, user, err := testutils.CreateUserAndCardGroup(ctx, userService, cardGroupService, roleService)
+			assert.NoError(t, err)
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, user.ID, model.ImportWorkbook{
+				Workbook:    encoded,
+				FrontColumn: "Word",
+				BackColumn:  "Definition",
+			})
+
+			// Assert
+			assert.ErrorContains(t, err, "invalid back column")
+			assert.Nil(t, cardGroups)
+		})
+
+		t.Run("Error_ImportWorkbook_MergedWithoutName", func(t *testing.T) {
+			// Arrange
+			mode := model.WorkbookImportModeMerged
+
+			// Act
+			cardGroups, err := usecase.ImportWorkbook(ctx, 1, model.ImportWorkbook{
+				Workbook:    encoded,
+				FrontColumn: "Word",
+				BackColumn:  "Meaning",
+				Mode:        &mode,
+			})
+
+			// Assert
+			assert.ErrorContains(t, err, "name is required to merge sheets")
+			assert.Nil(t, cardGroups)
+		})
+	})
+}
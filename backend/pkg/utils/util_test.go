@@ -20,6 +20,12 @@ func TestLevenshteinDistance(t *testing.T) {
 		// Test case where target and compare have no matching characters
 		{"abc", "xyz", 3},
 
+		// Test case where one of the strings is empty
+		{"", "走る", 2},
+
+		// Test case where runes differ
+		{"駆ける", "賭ける", 1},
+
 		// Test case where target and compare are 300 characters long and completely different
 		{"いろはにほへとちりぬるをわかよたれそつねならむうゐのおくやまけふこえてあさきゆめみしゑひもせす", "いろはにほへとちりぬるをわかよたれそつねならむうゐのおうやまけふこえてあさきゆめみしゑひもせす", 1},
 	}
@@ -46,7 +52,9 @@ func TestSimilarity(t *testing.T) {
 		{"kitten", "kitten", 1.0, 1e-9},
 		{"kitten", "sitten", 5.0 / 6.0, 1e-9},
 		{"abc", "xyz", 0.0, 1e-9},
-		{"いろはにほへとちりぬるをわかよたれそつねならむうゐのおくやまけふこえてあさきゆめみしゑひもせす", "いろはにほへとちりぬるをわかよたれそつねならむうゐのおうやまけふこえてあさきゆめみしゑひもせす", 0.9929078014, 1e-9},
+		{"いろはにほへとちりぬるをわかよたれそつねならむうゐのおくやまけふこえてあさきゆめみしゑひもせす", "いろはにほへとちりぬるをわかよたれそつねならむうゐのおうやまけふこえてあさきゆめみしゑひもせす", 1.0 - 1.0/47.0, 1e-9},
+		{"走る", "走れ", 0.5, 1e-9},
+		{"", "", 1.0, 1e-9},
 	}
 
 	for _, tt := range tests {
@@ -60,3 +68,29 @@ func TestSimilarity(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizedSimilarity(t *testing.T) {
+	tests := []struct {
+		target   string
+		compare  string
+		expected float64
+		epsilon  float64
+	}{
+		{"Kitten", "kitten", 1.0, 1e-9},
+		{"ｋｉｔｔｅｎ", "kitten", 1.0, 1e-9},
+		{"trot  out ", "trot out", 1.0, 1e-9},
+		{"ｶﾀｶﾅ", "カタカナ", 1.0, 1e-9},
+		{"kitten", "sitten", 5.0 / 6.0, 1e-9},
+	}
+
+	for _, tt := range tests {
+		tt := tt // Capture range variable
+		t.Run(tt.target+" vs "+tt.compare, func(t *testing.T) {
+			t.Parallel() // Run tests in parallel
+			result := utils.NormalizedSimilarity(tt.target, tt.compare)
+			if !utils.Float64Equal(result, tt.expected, tt.epsilon) {
+				t.Errorf("NormalizedSimilarity(%q, %q) = %.10f; want %.10f", tt.target, tt.compare, result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"golang.org/x/text/unicode/norm"
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"unicode/utf8"
 )
 
 // GetFullPath takes a relative path and returns the full absolute path
@@ -17,45 +20,43 @@ func GetFullPath(path string) (string, error) {
 	return fullPath, nil
 }
 
-// LevenshteinDistance calculates the Levenshtein distance between two strings, using int64 for large values
+// LevenshteinDistance calculates the Levenshtein distance between two strings, using int64 for large values.
+// The strings are compared rune by rune, and only two rows of the matrix are kept in memory.
 func LevenshteinDistance(target, compare string) int64 {
-	targetLen := int64(len(target))
-	compareLen := int64(len(compare))
+	targetRunes := []rune(target)
+	compareRunes := []rune(compare)
 
-	// Initialize a 2D slice (matrix) with int64 values
-	dp := make([][]int64, targetLen+1)
-	for i := range dp {
-		dp[i] = make([]int64, compareLen+1)
+	// Keep the shorter string in the columns to minimize the rows
+	if len(compareRunes) > len(targetRunes) {
+		targetRunes, compareRunes = compareRunes, targetRunes
 	}
 
-	// Set initial values
-	for i := int64(0); i <= targetLen; i++ {
-		dp[i][0] = i
-	}
-	for j := int64(0); j <= compareLen; j++ {
-		dp[0][j] = j
+	prev := make([]int64, len(compareRunes)+1)
+	curr := make([]int64, len(compareRunes)+1)
+	for j := range prev {
+		prev[j] = int64(j)
 	}
 
-	// Fill the DP table
-	for i := int64(1); i <= targetLen; i++ {
-		for j := int64(1); j <= compareLen; j++ {
+	for i := 1; i <= len(targetRunes); i++ {
+		curr[0] = int64(i)
+		for j := 1; j <= len(compareRunes); j++ {
 			cost := int64(0)
-			if target[i-1] != compare[j-1] {
+			if targetRunes[i-1] != compareRunes[j-1] {
 				cost = 1
 			}
-
-			dp[i][j] = int64(math.Min(float64(dp[i-1][j]+1), math.Min(float64(dp[i][j-1]+1), float64(dp[i-1][j-1]+cost))))
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
 		}
+		prev, curr = curr, prev
 	}
 
 	// Return the Levenshtein distance
-	return dp[targetLen][compareLen]
+	return prev[len(compareRunes)]
 }
 
 // Similarity calculates the similarity score based on Levenshtein distance
 func Similarity(target, compare string) float64 {
 	distance := LevenshteinDistance(target, compare)
-	maxLen := math.Max(float64(len(target)), float64(len(compare)))
+	maxLen := math.Max(float64(utf8.RuneCountInString(target)), float64(utf8.RuneCountInString(compare)))
 
 	if maxLen == 0 {
 		// Both strings are empty, treat them as identical
@@ -66,6 +67,17 @@ func Similarity(target, compare string) float64 {
 	return 1.0 - float64(distance)/maxLen
 }
 
+// Normalize folds the differences which do not matter to compare texts,
+// such as full-width letters, letter cases and repeated whitespaces.
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFKC.String(s))), " ")
+}
+
+// NormalizedSimilarity calculates the similarity score of the normalized strings
+func NormalizedSimilarity(target, compare string) float64 {
+	return Similarity(Normalize(target), Normalize(compare))
+}
+
 // Float64Equal checks if two float64 values are equal within a small tolerance.
 func Float64Equal(a, b, epsilon float64) bool {
 	return math.Abs(a-b) <= epsilon
@@ -0,0 +1,68 @@
+package workbook
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/m-mizutani/goerr"
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheet is a worksheet of a workbook
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// Read reads the worksheets of an Excel workbook (.xlsx) in the order of the tabs
+func Read(r io.Reader) ([]*Sheet, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open workbook")
+	}
+	defer f.Close()
+
+	var sheets []*Sheet
+	for _, name := range f.GetSheetList() {
+		rows, err := f.GetRows(name)
+		if err != nil {
+			return nil, goerr.Wrap(err, fmt.Errorf("failed to read sheet : %s", name))
+		}
+		sheets = append(sheets, &Sheet{Name: name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// columnLetters matches column names such as "A" and "AB"
+var columnLetters = regexp.MustCompile(`^[A-Za-z]{1,3}$`)
+
+// ColumnIndex returns the zero-based index of a column given by a header of the first row,
+// or by a column name such as "B". Headers take precedence when withHeader is true.
+func (s *Sheet) ColumnIndex(column string, withHeader bool) (int, error) {
+	column = strings.TrimSpace(column)
+	if withHeader && len(s.Rows) > 0 {
+		for i, header := range s.Rows[0] {
+			if strings.EqualFold(strings.TrimSpace(header), column) {
+				return i, nil
+			}
+		}
+	}
+	if columnLetters.MatchString(column) {
+		n, err := excelize.ColumnNameToNumber(strings.ToUpper(column))
+		if err != nil {
+			return 0, goerr.Wrap(err, fmt.Errorf("invalid column : %s", column))
+		}
+		return n - 1, nil
+	}
+	return 0, goerr.Wrap(fmt.Errorf("column not found in sheet %s : %s", s.Name, column))
+}
+
+// Cell returns the trimmed value of the row at the index, or an empty string for missing cells
+func Cell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
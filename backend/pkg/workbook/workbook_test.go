@@ -0,0 +1,98 @@
+package workbook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// writeTestWorkbook writes a workbook with a sheet per entry of sheets
+func writeTestWorkbook(t *testing.T, names []string, sheets map[string][][]any) []byte {
+	t.Helper()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, name := range names {
+		if i == 0 {
+			require.NoError(t, f.SetSheetName("Sheet1", name))
+		} else {
+			_, err := f.NewSheet(name)
+			require.NoError(t, err)
+		}
+		for r, row := range sheets[name] {
+			cell, err := excelize.CoordinatesToCellName(1, r+1)
+			require.NoError(t, err)
+			require.NoError(t, f.SetSheetRow(name, cell, &row))
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	return buf.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normal_Read", func(t *testing.T) {
+		t.Parallel()
+		data := writeTestWorkbook(t, []string{"Verbs", "Nouns"}, map[string][][]any{
+			"Verbs": {{"Word", "Meaning"}, {"run", "走る"}, {"walk", "歩く"}},
+			"Nouns": {{"Word", "Meaning"}, {"rube", "田舎者"}},
+		})
+
+		sheets, err := Read(bytes.NewReader(data))
+
+		require.NoError(t, err)
+		require.Len(t, sheets, 2)
+		assert.Equal(t, "Verbs", sheets[0].Name)
+		assert.Equal(t, [][]string{{"Word", "Meaning"}, {"run", "走る"}, {"walk", "歩く"}}, sheets[0].Rows)
+		assert.Equal(t, "Nouns", sheets[1].Name)
+	})
+
+	t.Run("Error_Read_NotWorkbook", func(t *testing.T) {
+		t.Parallel()
+
+		sheets, err := Read(bytes.NewReader([]byte("not a workbook")))
+
+		assert.Error(t, err)
+		assert.Nil(t, sheets)
+	})
+}
+
+func TestColumnIndex(t *testing.T) {
+	t.Parallel()
+	sheet := &Sheet{Name: "Verbs", Rows: [][]string{{"Word", " Meaning ", "B"}}}
+
+	testCases := []struct {
+		name       string
+		column     string
+		withHeader bool
+		expected   int
+		wantErr    bool
+	}{
+		{"Header", "meaning", true, 1, false},
+		{"Column name", "a", true, 0, false},
+		{"Header takes precedence", "B", true, 2, false},
+		{"Column name without header", "B", false, 1, false},
+		{"Header ignored without header", "Word", false, 0, true},
+		{"Unknown header", "Example", true, 0, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			index, err := sheet.ColumnIndex(tc.column, tc.withHeader)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, index)
+		})
+	}
+}
@@ -157,7 +157,6 @@ func CreateUserAndCardGroup(
 		Name:    "Test Group" + randstr,
 		Created: time.Now().UTC(),
 		Updated: time.Now().UTC(),
-		UserIds: []int64{createdUser.ID},
 	}
 
 	createdCardGroup, err := cardGroupService.CreateCardGroup(ctx, input)
@@ -165,7 +164,7 @@ func CreateUserAndCardGroup(
 		return nil, nil, goerr.Wrap(err)
 	}
 
-	_, err = cardGroupService.AddUserToCardGroup(ctx, createdUser.ID, createdCardGroup.ID)
+	_, err = cardGroupService.AddUserToCardGroup(ctx, createdUser.ID, createdCardGroup.ID, model.CardGroupRoleOwner)
 	if err != nil {
 		return nil, nil, goerr.Wrap(err)
 	}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"backend/graph/services"
+	"backend/pkg/auth"
+	"backend/pkg/usecases"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ankiPackageContentType is the media type of Anki packages
+const ankiPackageContentType = "application/apkg"
+
+// exportAnkiHandler serves a card group to its members as an Anki package.
+// With scheduling=true, the scheduling state of the authenticated user is included.
+func exportAnkiHandler(srv services.Services, u usecases.Usecases) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		cardGroupID, err := strconv.ParseInt(c.Param("cardGroupID"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid card group ID")
+		}
+
+		includeScheduling := false
+		if v := c.QueryParam("scheduling"); v != "" {
+			if includeScheduling, err = strconv.ParseBool(v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid scheduling")
+			}
+		}
+
+		userID, err := auth.ViewerID(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "login required to export card group")
+		}
+		if err := requireCardGroupViewer(c, srv, cardGroupID, userID); err != nil {
+			return err
+		}
+
+		pkg, err := u.ExportAnkiPackage(ctx, cardGroupID, userID, includeScheduling)
+		if err != nil {
+			c.Logger().Errorf("failed to export anki package : %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to export anki package")
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf("attachment; filename*=UTF-8''%s.apkg", url.PathEscape(pkg.Name)))
+		return c.Blob(http.StatusOK, ankiPackageContentType, pkg.Data)
+	}
+}
+
+// requireCardGroupViewer fails with 403 unless the user is a member of the card group or has the
+// cardgroup:admin permission. Card groups that do not exist are forbidden too, so that their IDs are not revealed.
+func requireCardGroupViewer(c echo.Context, srv services.Services, cardGroupID int64, userID int64) error {
+	ctx := c.Request().Context()
+	role, err := srv.GetCardGroupRole(ctx, cardGroupID, userID)
+	if err != nil {
+		c.Logger().Errorf("failed to check card group membership : %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to check card group membership")
+	}
+	if role != nil {
+		return nil
+	}
+
+	admin, err := srv.HasPermission(ctx, userID, auth.PermissionCardGroupAdmin)
+	if err != nil {
+		c.Logger().Errorf("failed to check permission : %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to check permission")
+	}
+	if !admin {
+		return echo.NewHTTPError(http.StatusForbidden, "not a member of card group")
+	}
+	return nil
+}
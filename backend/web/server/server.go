@@ -3,13 +3,15 @@ package server
 import (
 	"backend/graph"
 	"backend/graph/services"
+	"backend/pkg/auth"
 	"backend/pkg/config"
 	"backend/pkg/middlewares"
 	"backend/pkg/repository"
 	"backend/pkg/usecases"
 	"backend/pkg/validator"
+	"context"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
-	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/m-mizutani/goerr"
 	"log"
 	"net/http"
 	"strconv"
@@ -41,20 +43,16 @@ func NewRouter(db *gorm.DB) *echo.Echo {
 	// Create usecases
 	usecase := usecases.New(service)
 
-	// Configure Auth
-	if !config.IsTest() {
-		e.Use(echojwt.WithConfig(echojwt.Config{
-			SigningKey:  []byte(config.Cfg.JWTSecret),
-			TokenLookup: "cookie:jwt",
-		}))
-	} else {
-		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-			return func(c echo.Context) error {
-				// Skip Auth in the Testing Environment
-				return next(c)
-			}
-		})
-	}
+	// Configure Auth. Anonymous requests are let through in the Testing Environment
+	verifier := auth.NewVerifier(auth.VerifierConfig{
+		Secret:   config.Cfg.JWTSecret,
+		JWKSURL:  config.Cfg.FLJWKSURL,
+		CacheTTL: config.Cfg.FLJWKSCacheTTL,
+		Issuer:   config.Cfg.FLJWTIssuer,
+		Audience: config.Cfg.FLJWTAudience,
+	})
+	e.Use(middlewares.JWTMiddleware(verifier, config.IsTest()))
+	e.Use(middlewares.ViewerCtxMiddleware(newViewerResolver(verifier, service)))
 
 	// Validator
 	validateWrapper := validator.NewValidateWrapper()
@@ -68,7 +66,10 @@ func NewRouter(db *gorm.DB) *echo.Echo {
 		Loaders: graph.NewLoaders(service),
 	}
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Directives: graph.NewDirectives(service),
+	}))
 
 	// GraphQL Complexity configuration
 	srv.Use(extension.FixedComplexityLimit(config.Cfg.GQLComplexity))
@@ -98,9 +99,27 @@ func NewRouter(db *gorm.DB) *echo.Echo {
 		return nil
 	})
 
+	// Anki package download of card groups
+	e.GET("/export/anki/:cardGroupID", exportAnkiHandler(service, usecase))
+
 	return e
 }
 
+// newViewerResolver resolves the viewer by the user ID in the subject of tokens signed with the HMAC
+// secret, or by provisioning the user of the identity in tokens of the identity provider
+func newViewerResolver(verifier *auth.Verifier, users services.UserService) auth.ViewerResolver {
+	if !verifier.UsesJWKS() {
+		return auth.SubjectViewer
+	}
+	return func(ctx context.Context, claims *auth.Claims) (int64, error) {
+		user, err := users.ProvisionUser(ctx, claims.Identity(), claims.Email, claims.IsEmailVerified(), claims.DisplayName())
+		if err != nil {
+			return 0, goerr.Wrap(err, "failed to provision viewer")
+		}
+		return user.ID, nil
+	}
+}
+
 func StartServer(dbConfig repository.DBConfig) {
 	// Initialize the database
 	db := repository.InitializeDatabase(dbConfig)